@@ -0,0 +1,607 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Default Settings of the Arena.
+const (
+	ArenaSizeDefault      = 64 * 1024 * 1024
+	ArenaSlabSizeDefault  = 1024 * 1024
+	ArenaChunkSizeDefault = 64
+)
+
+// Absence of a Node or of a Chunk, and the maximum Index of them.
+const (
+	arenaNone     = int32(-1)
+	maxArenaIndex = int32(^uint32(0) >> 1)
+)
+
+// Settings of the Arena of an Arena Cache.
+// Zero Values select the default Settings.
+type ArenaSettings struct {
+
+	// Total Size of the Arena in Bytes. It is rounded up to whole Slabs.
+	Size int64
+
+	// Size of a single Slab in Bytes. The Arena is allocated as a few large
+	// Slabs. It is rounded down to whole Chunks.
+	SlabSize int
+
+	// Size of a Chunk in Bytes. Each Record occupies a Chain of Chunks which
+	// holds its UID followed by its Value.
+	ChunkSize int
+
+	// A Source of the current Time used for the Expiration of Records.
+	Clock func() time.Time
+}
+
+// Statistics of the Arena Cache's Usage.
+type ArenaCacheStatistics struct {
+
+	// Count of Requests which have found an actual Record.
+	Hits uint64
+
+	// Count of Requests which have not found an actual Record.
+	Misses uint64
+
+	// Count of Records deleted because they were outdated.
+	Expirations uint64
+
+	// Count of actual Records removed from the Bottom of the full Cache.
+	Evictions uint64
+
+	// Current Count of Records in the Cache.
+	Records uint
+
+	// Current Count of Bytes occupied by the Chunks of Records.
+	UsedBytes int64
+
+	// Total Size of the Arena in Bytes.
+	ArenaSize int64
+}
+
+// A fixed-Size Bubble Cache of Byte Values stored outside of the Go Heap's
+// Object Graph.
+//
+// UIDs and Values of Records are copied into large preallocated Byte Slabs.
+// Records are linked by Indices instead of Pointers, and the Index of UIDs is
+// a Hash Table of Indices, so the Garbage Collector sees only a few large
+// pointer-free Allocations regardless of the Count of Records.
+//
+// The Cache is full either when the Count of Records reaches the Capacity or
+// when the Arena has no Space for a new Record. In both Cases the Bottom
+// Records are evicted. As with the ordinary Cache, the Record TTL is counted
+// from the last Access to the Record. Outdated Records are removed before
+// actual Records are evicted.
+type ArenaCache struct {
+	lock sync.Mutex
+
+	capacity  uint
+	recordTTL uint
+	clock     func() time.Time
+
+	// Arena.
+	chunkSize     int
+	chunksPerSlab int
+	slabs         [][]byte
+
+	// Links of Chunks: either to the next Chunk of a Record or to the next
+	// free Chunk.
+	chunkLinks      []int32
+	freeChunk       int32
+	freeChunksCount int
+
+	// Nodes of Records. Free Nodes are linked by their 'nextInBucket' Field.
+	nodes    []arenaNode
+	freeNode int32
+
+	// Hash Index: Heads of the Chains of Nodes.
+	buckets    []int32
+	bucketMask uint32
+
+	top    int32
+	bottom int32
+	size   uint
+
+	statistics ArenaCacheStatistics
+}
+
+// A Node of a Record. It has no Pointers.
+type arenaNode struct {
+	upper          int32
+	lower          int32
+	nextInBucket   int32
+	firstChunk     int32
+	hash           uint32
+	uidSize        uint32
+	valueSize      uint32
+	lastAccessTime uint
+}
+
+// A Position in a Chain of Chunks.
+type arenaCursor struct {
+	chunk  int32
+	offset int
+}
+
+// Creates a new Arena Cache. The Capacity is the maximum Count of Records.
+func NewArenaCache(
+	capacity uint,
+	recordTTL uint,
+	settings ArenaSettings,
+) (cache *ArenaCache, err error) {
+	if capacity == 0 {
+		capacity++
+	}
+	if settings.Size <= 0 {
+		settings.Size = ArenaSizeDefault
+	}
+	if settings.ChunkSize <= 0 {
+		settings.ChunkSize = ArenaChunkSizeDefault
+	}
+	if settings.SlabSize <= 0 {
+		settings.SlabSize = ArenaSlabSizeDefault
+	}
+	if settings.SlabSize < settings.ChunkSize {
+		settings.SlabSize = settings.ChunkSize
+	}
+	if settings.Clock == nil {
+		settings.Clock = time.Now
+	}
+
+	var chunksPerSlab = settings.SlabSize / settings.ChunkSize
+	var slabSize = int64(chunksPerSlab * settings.ChunkSize)
+	var slabsCount = (settings.Size + slabSize - 1) / slabSize
+	var chunksCount = slabsCount * int64(chunksPerSlab)
+	if (chunksCount > int64(maxArenaIndex)) || (uint64(capacity) > uint64(maxArenaIndex)) {
+		err = errors.New(ErrArenaIsTooLarge)
+		return
+	}
+
+	cache = &ArenaCache{
+		capacity:      capacity,
+		recordTTL:     recordTTL,
+		clock:         settings.Clock,
+		chunkSize:     settings.ChunkSize,
+		chunksPerSlab: chunksPerSlab,
+		slabs:         make([][]byte, slabsCount),
+		chunkLinks:    make([]int32, chunksCount),
+		nodes:         make([]arenaNode, capacity),
+	}
+	for i := range cache.slabs {
+		cache.slabs[i] = make([]byte, slabSize)
+	}
+
+	var bucketsCount = uint64(1)
+	for bucketsCount < uint64(capacity) {
+		bucketsCount <<= 1
+	}
+	cache.buckets = make([]int32, bucketsCount)
+	cache.bucketMask = uint32(bucketsCount - 1)
+
+	cache.statistics.ArenaSize = chunksCount * int64(settings.ChunkSize)
+	cache.reset()
+	return
+}
+
+// Links all Nodes and Chunks into the free Lists and empties the Index.
+func (c *ArenaCache) reset() {
+	for i := range c.chunkLinks {
+		c.chunkLinks[i] = int32(i + 1)
+	}
+	c.chunkLinks[len(c.chunkLinks)-1] = arenaNone
+	c.freeChunk = 0
+	c.freeChunksCount = len(c.chunkLinks)
+
+	for i := range c.nodes {
+		c.nodes[i] = arenaNode{nextInBucket: int32(i + 1)}
+	}
+	c.nodes[len(c.nodes)-1].nextInBucket = arenaNone
+	c.freeNode = 0
+
+	for i := range c.buckets {
+		c.buckets[i] = arenaNone
+	}
+
+	c.top = arenaNone
+	c.bottom = arenaNone
+	c.size = 0
+}
+
+// Adds a Record to the Top of the Cache. The UID and the Value are copied
+// into the Arena. An existing Record with the same UID is replaced.
+func (c *ArenaCache) AddRecord(
+	uid FixedSizeBubbleCacheRecordUID,
+	value []byte,
+) (err error) {
+	if len(uid) == 0 {
+		return errors.New(ErrUIDIsEmpty)
+	}
+	var chunksCount = c.chunksOf(len(uid) + len(value))
+	if chunksCount > len(c.chunkLinks) {
+		return fmt.Errorf(ErrfArenaRecordIsTooLarge, uid)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var hash = arenaHash(uid)
+	var existingNode = c.findNode(uid, hash)
+	if existingNode != arenaNone {
+		c.removeNode(existingNode)
+	}
+
+	// Records are ordered by their LAT, so outdated Records are at the Bottom
+	// and are removed before actual Records.
+	var now = c.now()
+	for (c.size == c.capacity) || (c.freeChunksCount < chunksCount) {
+		if c.isOutdated(c.bottom, now) {
+			c.statistics.Expirations++
+		} else {
+			c.statistics.Evictions++
+		}
+		c.removeNode(c.bottom)
+	}
+
+	var i = c.freeNode
+	c.freeNode = c.nodes[i].nextInBucket
+	c.nodes[i] = arenaNode{
+		firstChunk:     c.allocateChunks(chunksCount),
+		hash:           hash,
+		uidSize:        uint32(len(uid)),
+		valueSize:      uint32(len(value)),
+		lastAccessTime: now,
+	}
+	var cursor = arenaCursor{chunk: c.nodes[i].firstChunk}
+	c.writeString(&cursor, uid)
+	c.writeBytes(&cursor, value)
+
+	var bucket = hash & c.bucketMask
+	c.nodes[i].nextInBucket = c.buckets[bucket]
+	c.buckets[bucket] = i
+	c.linkTopNode(i)
+	c.size++
+	return
+}
+
+// Gets a Copy of the Record's Value by its UID. Moves the Record to the Top
+// of the List and refreshes its LAT. If the Record is outdated, deletes it and
+// returns an Error.
+func (c *ArenaCache) GetActualRecordDataByUID(
+	uid FixedSizeBubbleCacheRecordUID,
+) (value []byte, err error) {
+	return c.AppendActualRecordDataByUID(nil, uid)
+}
+
+// Appends the Record's Value to the Buffer, so that the Buffer may be reused
+// without Allocations. Otherwise it works as 'GetActualRecordDataByUID'.
+func (c *ArenaCache) AppendActualRecordDataByUID(
+	buffer []byte,
+	uid FixedSizeBubbleCacheRecordUID,
+) (result []byte, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	result = buffer
+	var i = c.findNode(uid, arenaHash(uid))
+	if i == arenaNone {
+		c.statistics.Misses++
+		err = fmt.Errorf(ErrfRecordWithUidIsNotFound, uid)
+		return
+	}
+
+	var now = c.now()
+	if c.isOutdated(i, now) {
+		c.removeNode(i)
+		c.statistics.Misses++
+		c.statistics.Expirations++
+		err = fmt.Errorf(ErrfRecordWithUidIsOutdated, uid)
+		return
+	}
+
+	if i != c.top {
+		c.unlinkNode(i)
+		c.linkTopNode(i)
+	}
+	c.nodes[i].lastAccessTime = now
+	c.statistics.Hits++
+
+	var cursor = arenaCursor{chunk: c.nodes[i].firstChunk}
+	c.skip(&cursor, int(c.nodes[i].uidSize))
+	result = c.appendBytes(&cursor, int(c.nodes[i].valueSize), result)
+	return
+}
+
+// Deletes a Record specified by its UID from the Cache.
+func (c *ArenaCache) DeleteRecordByUID(
+	uid FixedSizeBubbleCacheRecordUID,
+) (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var i = c.findNode(uid, arenaHash(uid))
+	if i == arenaNone {
+		return fmt.Errorf(ErrfRecordWithUidIsNotFound, uid)
+	}
+	c.removeNode(i)
+	return
+}
+
+// Checks whether the specified Record's UID exists in the Cache.
+func (c *ArenaCache) RecordUIDExists(
+	uid FixedSizeBubbleCacheRecordUID,
+) (uidExists bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.findNode(uid, arenaHash(uid)) != arenaNone
+}
+
+// Deletes all Records from the Cache. The Arena is kept for Reuse.
+func (c *ArenaCache) Clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.reset()
+}
+
+// Returns the Statistics of the Cache's Usage.
+func (c *ArenaCache) Stats() (stats ArenaCacheStatistics) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	stats = c.statistics
+	stats.Records = c.size
+	stats.UsedBytes = int64(len(c.chunkLinks)-c.freeChunksCount) * int64(c.chunkSize)
+	return
+}
+
+// Returns the 'RecordTTL' Parameter of the Cache.
+func (c *ArenaCache) GetRecordTTL() uint {
+	return c.recordTTL
+}
+
+// Returns the 'Capacity' Parameter of the Cache.
+func (c *ArenaCache) GetCapacity() uint {
+	return c.capacity
+}
+
+// Returns the current Unix Time of the Cache's Clock in Seconds.
+func (c *ArenaCache) now() uint {
+	return uint(c.clock().Unix())
+}
+
+// Checks whether the Record of the Node is outdated at the Time.
+func (c *ArenaCache) isOutdated(
+	i int32,
+	now uint,
+) bool {
+	return now >= c.nodes[i].lastAccessTime+c.recordTTL
+}
+
+// Finds the Node of the Record. Returns 'arenaNone' if it is not found.
+func (c *ArenaCache) findNode(
+	uid FixedSizeBubbleCacheRecordUID,
+	hash uint32,
+) int32 {
+	for i := c.buckets[hash&c.bucketMask]; i != arenaNone; i = c.nodes[i].nextInBucket {
+		if (c.nodes[i].hash == hash) &&
+			(c.nodes[i].uidSize == uint32(len(uid))) &&
+			c.uidEquals(c.nodes[i].firstChunk, uid) {
+			return i
+		}
+	}
+	return arenaNone
+}
+
+// Removes the Node from the List and from the Index, frees its Chunks.
+func (c *ArenaCache) removeNode(
+	i int32,
+) {
+	var bucket = c.nodes[i].hash & c.bucketMask
+	if c.buckets[bucket] == i {
+		c.buckets[bucket] = c.nodes[i].nextInBucket
+	} else {
+		var previous = c.buckets[bucket]
+		for c.nodes[previous].nextInBucket != i {
+			previous = c.nodes[previous].nextInBucket
+		}
+		c.nodes[previous].nextInBucket = c.nodes[i].nextInBucket
+	}
+
+	c.unlinkNode(i)
+	c.freeChunks(c.nodes[i].firstChunk, c.chunksOf(int(c.nodes[i].uidSize)+int(c.nodes[i].valueSize)))
+
+	c.nodes[i] = arenaNode{nextInBucket: c.freeNode}
+	c.freeNode = i
+	c.size--
+}
+
+// Unlinks the Node from the List.
+func (c *ArenaCache) unlinkNode(
+	i int32,
+) {
+	var upper, lower = c.nodes[i].upper, c.nodes[i].lower
+	if upper != arenaNone {
+		c.nodes[upper].lower = lower
+	} else {
+		c.top = lower
+	}
+	if lower != arenaNone {
+		c.nodes[lower].upper = upper
+	} else {
+		c.bottom = upper
+	}
+	c.nodes[i].upper = arenaNone
+	c.nodes[i].lower = arenaNone
+}
+
+// Links the Node to the Top of the List.
+func (c *ArenaCache) linkTopNode(
+	i int32,
+) {
+	c.nodes[i].upper = arenaNone
+	c.nodes[i].lower = c.top
+	if c.top != arenaNone {
+		c.nodes[c.top].upper = i
+	} else {
+		c.bottom = i
+	}
+	c.top = i
+}
+
+// Returns the Count of Chunks needed for the Bytes.
+func (c *ArenaCache) chunksOf(
+	size int,
+) int {
+	return (size + c.chunkSize - 1) / c.chunkSize
+}
+
+// Takes the Chain of Chunks from the free List.
+func (c *ArenaCache) allocateChunks(
+	count int,
+) (first int32) {
+	first = c.freeChunk
+	var last = first
+	for n := 1; n < count; n++ {
+		last = c.chunkLinks[last]
+	}
+	c.freeChunk = c.chunkLinks[last]
+	c.chunkLinks[last] = arenaNone
+	c.freeChunksCount -= count
+	return
+}
+
+// Returns the Chain of Chunks into the free List.
+func (c *ArenaCache) freeChunks(
+	first int32,
+	count int,
+) {
+	var last = first
+	for n := 1; n < count; n++ {
+		last = c.chunkLinks[last]
+	}
+	c.chunkLinks[last] = c.freeChunk
+	c.freeChunk = first
+	c.freeChunksCount += count
+}
+
+// Returns the Bytes of the Chunk.
+func (c *ArenaCache) chunkBytes(
+	chunk int32,
+) []byte {
+	var slab = c.slabs[int(chunk)/c.chunksPerSlab]
+	var offset = (int(chunk) % c.chunksPerSlab) * c.chunkSize
+	return slab[offset : offset+c.chunkSize]
+}
+
+// Moves the Cursor to the next Chunk when the current Chunk is used up.
+func (c *ArenaCache) advance(
+	cursor *arenaCursor,
+) {
+	if cursor.offset == c.chunkSize {
+		cursor.chunk = c.chunkLinks[cursor.chunk]
+		cursor.offset = 0
+	}
+}
+
+// Writes the String at the Cursor.
+func (c *ArenaCache) writeString(
+	cursor *arenaCursor,
+	s string,
+) {
+	for len(s) > 0 {
+		c.advance(cursor)
+		var n = copy(c.chunkBytes(cursor.chunk)[cursor.offset:], s)
+		s = s[n:]
+		cursor.offset += n
+	}
+}
+
+// Writes the Bytes at the Cursor.
+func (c *ArenaCache) writeBytes(
+	cursor *arenaCursor,
+	p []byte,
+) {
+	for len(p) > 0 {
+		c.advance(cursor)
+		var n = copy(c.chunkBytes(cursor.chunk)[cursor.offset:], p)
+		p = p[n:]
+		cursor.offset += n
+	}
+}
+
+// Moves the Cursor forward by the Count of Bytes.
+func (c *ArenaCache) skip(
+	cursor *arenaCursor,
+	size int,
+) {
+	for size > 0 {
+		c.advance(cursor)
+		var n = c.chunkSize - cursor.offset
+		if n > size {
+			n = size
+		}
+		size -= n
+		cursor.offset += n
+	}
+}
+
+// Appends the Count of Bytes at the Cursor to the Buffer.
+func (c *ArenaCache) appendBytes(
+	cursor *arenaCursor,
+	size int,
+	buffer []byte,
+) []byte {
+	for size > 0 {
+		c.advance(cursor)
+		var n = c.chunkSize - cursor.offset
+		if n > size {
+			n = size
+		}
+		buffer = append(buffer, c.chunkBytes(cursor.chunk)[cursor.offset:cursor.offset+n]...)
+		size -= n
+		cursor.offset += n
+	}
+	return buffer
+}
+
+// Compares the UID stored in the Chain of Chunks with the UID. The Sizes must
+// be equal.
+func (c *ArenaCache) uidEquals(
+	firstChunk int32,
+	uid FixedSizeBubbleCacheRecordUID,
+) bool {
+	var cursor = arenaCursor{chunk: firstChunk}
+	for len(uid) > 0 {
+		c.advance(&cursor)
+		var n = c.chunkSize - cursor.offset
+		if n > len(uid) {
+			n = len(uid)
+		}
+		if string(c.chunkBytes(cursor.chunk)[cursor.offset:cursor.offset+n]) != uid[:n] {
+			return false
+		}
+		uid = uid[n:]
+		cursor.offset += n
+	}
+	return true
+}
+
+// Returns the FNV-1a Hash of the UID.
+func arenaHash(
+	uid FixedSizeBubbleCacheRecordUID,
+) (hash uint32) {
+	hash = 2166136261
+	for i := 0; i < len(uid); i++ {
+		hash ^= uint32(uid[i])
+		hash *= 16777619
+	}
+	return
+}
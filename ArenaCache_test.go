@@ -0,0 +1,205 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/tester"
+)
+
+// Lists the UIDs of the Arena Cache from the Top to the Bottom.
+func listArenaCacheUIDs(
+	c *ArenaCache,
+) (uids []FixedSizeBubbleCacheRecordUID) {
+	for i := c.top; i != arenaNone; i = c.nodes[i].lower {
+		var cursor = arenaCursor{chunk: c.nodes[i].firstChunk}
+		uids = append(uids, string(c.appendBytes(&cursor, int(c.nodes[i].uidSize), nil)))
+	}
+	return
+}
+
+func Test_NewArenaCache(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache *ArenaCache
+	var err error
+
+	// Test #1. Default Settings.
+	cache, err = NewArenaCache(0, 60, ArenaSettings{})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(cache.GetCapacity(), uint(1))
+	aTest.MustBeEqual(cache.GetRecordTTL(), uint(60))
+	aTest.MustBeEqual(cache.Stats().ArenaSize, int64(ArenaSizeDefault))
+	aTest.MustBeEqual(len(cache.slabs), ArenaSizeDefault/ArenaSlabSizeDefault)
+
+	// Test #2. Size is rounded up to whole Slabs, Slabs to whole Chunks.
+	cache, err = NewArenaCache(10, 60, ArenaSettings{Size: 1000, SlabSize: 300, ChunkSize: 64})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(cache.chunksPerSlab, 4)
+	aTest.MustBeEqual(len(cache.slabs), 4)
+	aTest.MustBeEqual(cache.Stats().ArenaSize, int64(4*4*64))
+
+	// Test #3. Too many Chunks.
+	_, err = NewArenaCache(10, 60, ArenaSettings{Size: 1 << 40, SlabSize: 1 << 40, ChunkSize: 1})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrArenaIsTooLarge)
+}
+
+func Test_ArenaCache_AddRecord(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache, err = NewArenaCache(3, 60, ArenaSettings{Size: 256, SlabSize: 128, ChunkSize: 16})
+	aTest.MustBeNoError(err)
+	var value []byte
+
+	// Test #1. Empty UID and too large Record.
+	err = cache.AddRecord("", []byte("x"))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrUIDIsEmpty)
+	err = cache.AddRecord("big", make([]byte, 256))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfArenaRecordIsTooLarge, "big"))
+
+	// Test #2. Records spanning several Chunks and Slabs.
+	var longUID = strings.Repeat("u", 40)
+	var longValue = []byte(strings.Repeat("0123456789", 10))
+	aTest.MustBeNoError(cache.AddRecord(longUID, longValue))
+	aTest.MustBeNoError(cache.AddRecord("a", []byte("A")))
+	aTest.MustBeNoError(cache.AddRecord("b", []byte{}))
+	value, err = cache.GetActualRecordDataByUID(longUID)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(value, longValue)
+	value, err = cache.GetActualRecordDataByUID("b")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(len(value), 0)
+	aTest.MustBeEqual(listArenaCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"b", longUID, "a"})
+	aTest.MustBeEqual(cache.Stats().UsedBytes, int64((9+1+1)*16))
+
+	// Test #3. Update replaces the Value and moves the Record to the Top.
+	aTest.MustBeNoError(cache.AddRecord("a", []byte("A2")))
+	value, err = cache.GetActualRecordDataByUID("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(value, []byte("A2"))
+	aTest.MustBeEqual(cache.Stats().Records, uint(3))
+
+	// Test #4. Eviction by the Count of Records.
+	aTest.MustBeNoError(cache.AddRecord("c", []byte("C")))
+	aTest.MustBeEqual(listArenaCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"c", "a", "b"})
+	aTest.MustBeEqual(cache.Stats().Evictions, uint64(1))
+
+	// Test #5. Eviction by the Size of the Arena.
+	aTest.MustBeNoError(cache.AddRecord("d", make([]byte, 200)))
+	aTest.MustBeNoError(cache.AddRecord("e", make([]byte, 40)))
+	aTest.MustBeEqual(listArenaCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"e", "d"})
+	aTest.MustBeEqual(cache.Stats().Evictions, uint64(4))
+	aTest.MustBeEqual(cache.Stats().UsedBytes, int64((3+13)*16))
+}
+
+func Test_ArenaCache_GetActualRecordDataByUID(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache, err = NewArenaCache(10, 60, ArenaSettings{Size: 1024, ChunkSize: 8})
+	aTest.MustBeNoError(err)
+	var value []byte
+
+	// Test #1. Missing Record.
+	_, err = cache.GetActualRecordDataByUID("x")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfRecordWithUidIsNotFound, "x"))
+
+	// Test #2. Buffer is reused.
+	aTest.MustBeNoError(cache.AddRecord("a", []byte("value")))
+	var buffer = make([]byte, 0, 16)
+	value, err = cache.AppendActualRecordDataByUID(buffer[:0], "a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(value, []byte("value"))
+	aTest.MustBeEqual(&value[0] == &buffer[:1][0], true)
+
+	// Test #3. Outdated Record.
+	cache.nodes[cache.top].lastAccessTime = 0
+	_, err = cache.GetActualRecordDataByUID("a")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfRecordWithUidIsOutdated, "a"))
+	aTest.MustBeEqual(cache.RecordUIDExists("a"), false)
+
+	var stats = cache.Stats()
+	aTest.MustBeEqual(stats.Hits, uint64(1))
+	aTest.MustBeEqual(stats.Misses, uint64(2))
+	aTest.MustBeEqual(stats.Expirations, uint64(1))
+	aTest.MustBeEqual(stats.UsedBytes, int64(0))
+}
+
+func Test_ArenaCache_Clock(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var now = time.Unix(1000, 0)
+	var cache, err = NewArenaCache(3, 60, ArenaSettings{
+		Size:      1024,
+		ChunkSize: 8,
+		Clock:     func() time.Time { return now },
+	})
+	aTest.MustBeNoError(err)
+	var value []byte
+
+	// Test #1. LATs are taken from the Clock.
+	aTest.MustBeNoError(cache.AddRecord("a", []byte("A")))
+	aTest.MustBeNoError(cache.AddRecord("b", []byte("B")))
+	aTest.MustBeNoError(cache.AddRecord("c", []byte("C")))
+	now = now.Add(30 * time.Second)
+	value, err = cache.GetActualRecordDataByUID("c")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(value, []byte("C"))
+	aTest.MustBeEqual(cache.nodes[cache.top].lastAccessTime, uint(1030))
+
+	// Test #2. Outdated Records are removed instead of Evictions.
+	now = now.Add(40 * time.Second)
+	aTest.MustBeNoError(cache.AddRecord("d", []byte("D")))
+	aTest.MustBeEqual(listArenaCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"d", "c", "b"})
+	var stats = cache.Stats()
+	aTest.MustBeEqual(stats.Expirations, uint64(1))
+	aTest.MustBeEqual(stats.Evictions, uint64(0))
+
+	// Test #3. Requests use the Clock.
+	_, err = cache.GetActualRecordDataByUID("b")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfRecordWithUidIsOutdated, "b"))
+	value, err = cache.GetActualRecordDataByUID("c")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(value, []byte("C"))
+
+	// Test #4. Actual Records are evicted when no Record is outdated.
+	aTest.MustBeNoError(cache.AddRecord("e", []byte("E")))
+	aTest.MustBeNoError(cache.AddRecord("f", []byte("F")))
+	aTest.MustBeEqual(listArenaCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"f", "e", "c"})
+	stats = cache.Stats()
+	aTest.MustBeEqual(stats.Expirations, uint64(2))
+	aTest.MustBeEqual(stats.Evictions, uint64(1))
+}
+
+func Test_ArenaCache_DeleteRecordByUID(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache, err = NewArenaCache(100, 60, ArenaSettings{Size: 4096, ChunkSize: 8})
+	aTest.MustBeNoError(err)
+
+	// Test #1. Deletion from the Middle, with Collisions in the Index.
+	for i := 0; i < 100; i++ {
+		aTest.MustBeNoError(cache.AddRecord("key-"+strconv.Itoa(i), []byte(strconv.Itoa(i))))
+	}
+	aTest.MustBeNoError(cache.DeleteRecordByUID("key-50"))
+	err = cache.DeleteRecordByUID("key-50")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfRecordWithUidIsNotFound, "key-50"))
+	for i := 0; i < 100; i++ {
+		var uid = "key-" + strconv.Itoa(i)
+		aTest.MustBeEqual(cache.RecordUIDExists(uid), i != 50)
+	}
+	aTest.MustBeEqual(len(listArenaCacheUIDs(cache)), 99)
+
+	// Test #2. Clear.
+	cache.Clear()
+	aTest.MustBeEqual(cache.Stats().Records, uint(0))
+	aTest.MustBeEqual(cache.RecordUIDExists("key-1"), false)
+	aTest.MustBeNoError(cache.AddRecord("key-1", []byte("1")))
+	aTest.MustBeEqual(listArenaCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"key-1"})
+}
@@ -0,0 +1,151 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+)
+
+// Names of built-in Codecs.
+const (
+	CodecNameGob  = "gob"
+	CodecNameJSON = "json"
+	CodecNameRaw  = "raw"
+)
+
+// A Codec converts the Data of Records into Bytes and back.
+//
+// Each Cache has its own Codec, which is used by everything that persists or
+// transfers the Cache's Contents: Snapshots, the Write-Ahead Log, Disk Tiers,
+// Network Servers and Cluster Nodes. Network Servers store the Values received
+// from Clients as raw Bytes and encode Data of other Types with the Codec.
+// Cluster Nodes use the Codec of their Cache unless another Codec is set.
+type Codec interface {
+	Encode(data interface{}) (encoded []byte, err error)
+	Decode(encoded []byte) (data interface{}, err error)
+}
+
+// Returns a built-in Codec by its Name.
+func GetCodecByName(
+	name string,
+) (codec Codec, err error) {
+	switch name {
+	case CodecNameGob:
+		return GobCodec{}, nil
+	case CodecNameJSON:
+		return JSONCodec{}, nil
+	case CodecNameRaw:
+		return RawCodec{}, nil
+	default:
+		return nil, fmt.Errorf(ErrfCodecIsUnknown, name)
+	}
+}
+
+// A Codec based on the 'gob' Encoding.
+//
+// Concrete Types of the Data, other than the basic Types, must be registered
+// with the 'gob.Register' Function.
+type GobCodec struct{}
+
+// Encodes the Data.
+func (GobCodec) Encode(
+	data interface{},
+) (encoded []byte, err error) {
+	var buffer bytes.Buffer
+	err = gob.NewEncoder(&buffer).Encode(&data)
+	if err != nil {
+		return
+	}
+	encoded = buffer.Bytes()
+	return
+}
+
+// Decodes the Data.
+func (GobCodec) Decode(
+	encoded []byte,
+) (data interface{}, err error) {
+	err = gob.NewDecoder(bytes.NewReader(encoded)).Decode(&data)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// A Codec based on the JSON Encoding.
+//
+// Decoded Data has generic JSON Types: Numbers are decoded as 'float64',
+// Objects as 'map[string]interface{}', Arrays as '[]interface{}'.
+type JSONCodec struct{}
+
+// Encodes the Data.
+func (JSONCodec) Encode(
+	data interface{},
+) (encoded []byte, err error) {
+	return json.Marshal(data)
+}
+
+// Decodes the Data.
+func (JSONCodec) Decode(
+	encoded []byte,
+) (data interface{}, err error) {
+	err = json.Unmarshal(encoded, &data)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// A Codec which stores raw Bytes as they are.
+// The Data of Records must be of the '[]byte' Type.
+type RawCodec struct{}
+
+// Encodes the Data.
+func (RawCodec) Encode(
+	data interface{},
+) (encoded []byte, err error) {
+	var ok bool
+	encoded, ok = data.([]byte)
+	if !ok {
+		err = fmt.Errorf(ErrfCodecDataTypeIsNotSupported, data)
+		return
+	}
+	return
+}
+
+// Decodes the Data.
+func (RawCodec) Decode(
+	encoded []byte,
+) (data interface{}, err error) {
+	var decoded = make([]byte, len(encoded))
+	copy(decoded, encoded)
+	return decoded, nil
+}
+
+// Sets the Codec of the Cache. Null Codec selects the default 'gob' Codec.
+func (c *FixedSizeBubbleCache) SetCodec(
+	codec Codec,
+) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.codec = codec
+}
+
+// Returns the Codec of the Cache.
+func (c *FixedSizeBubbleCache) GetCodec() Codec {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.getCodec()
+}
+
+// Returns the Codec of the Cache.
+func (c *FixedSizeBubbleCache) getCodec() Codec {
+	if c.codec == nil {
+		return GobCodec{}
+	}
+	return c.codec
+}
@@ -0,0 +1,88 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/vault-thirteen/tester"
+)
+
+func Test_GetCodecByName(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var codec Codec
+	var err error
+
+	// Test #1. Known Codecs.
+	codec, err = GetCodecByName(CodecNameGob)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(codec, Codec(GobCodec{}))
+	codec, err = GetCodecByName(CodecNameJSON)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(codec, Codec(JSONCodec{}))
+	codec, err = GetCodecByName(CodecNameRaw)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(codec, Codec(RawCodec{}))
+
+	// Test #2. Unknown Codec.
+	_, err = GetCodecByName("xml")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfCodecIsUnknown, "xml"))
+}
+
+func Test_Codecs(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var encoded []byte
+	var data interface{}
+	var err error
+
+	// Test #1. Gob.
+	encoded, err = GobCodec{}.Encode(int64(42))
+	aTest.MustBeNoError(err)
+	data, err = GobCodec{}.Decode(encoded)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, int64(42))
+
+	// Test #2. JSON.
+	encoded, err = JSONCodec{}.Encode(map[string]interface{}{"a": 1})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(string(encoded), `{"a":1}`)
+	data, err = JSONCodec{}.Decode(encoded)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, map[string]interface{}{"a": 1.0})
+
+	// Test #3. Raw Bytes.
+	encoded, err = RawCodec{}.Encode([]byte("abc"))
+	aTest.MustBeNoError(err)
+	data, err = RawCodec{}.Decode(encoded)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, []byte("abc"))
+
+	// Test #4. Raw Codec rejects other Types.
+	_, err = RawCodec{}.Encode("abc")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfCodecDataTypeIsNotSupported, "abc"))
+}
+
+func Test_SetCodec(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(2, 60)
+	var buffer bytes.Buffer
+	var err error
+
+	// Test #1. Default Codec.
+	aTest.MustBeEqual(cache.GetCodec(), Codec(GobCodec{}))
+
+	// Test #2. Snapshot uses the Cache's Codec.
+	cache.SetCodec(RawCodec{})
+	aTest.MustBeEqual(cache.GetCodec(), Codec(RawCodec{}))
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: []byte("xyz")})
+	err = cache.WriteSnapshot(&buffer)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(bytes.Contains(buffer.Bytes(), []byte("xyz")), true)
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 1})
+	err = cache.WriteSnapshot(&buffer)
+	aTest.MustBeAnError(err)
+}
@@ -0,0 +1,427 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default Settings of the Disk Tier.
+const (
+	DiskTierCapacityDefault  = 64 * 1024 * 1024
+	DiskTierRecordTTLDefault = 600
+	DiskTierQueueSizeDefault = 1024
+)
+
+// Disk Tier File Format.
+//
+// Each Record is stored in a separate File, named after the SHA-256 Hash of
+// the Record's UID. The File contains:
+//
+//	Magic (4 Bytes), Format Version (1 Byte),
+//	UID Size (uint32), UID Bytes,
+//	Data encoded with the Codec,
+//	CRC-32 (IEEE) Checksum of all the previous Bytes (uint32).
+//
+// All Integers are written in the Big-Endian Byte Order.
+const (
+	DiskTierFileMagic         = "FSBD"
+	DiskTierFileFormatVersion = byte(1)
+	DiskTierFileExtension     = ".fsbd"
+
+	diskTierFileFixedSize = 4 + 1 + 4 + 4
+)
+
+// Settings of the Disk Tier. Zero Values select the default Settings.
+type DiskTierSettings struct {
+
+	// Directory where the Files of Records are stored. It is created when it
+	// does not exist. Files left by a previous Disk Tier are deleted.
+	Directory string
+
+	// Maximum total Size of the Files of Records, in Bytes. When it is
+	// exceeded, the oldest Records are removed.
+	Capacity int64
+
+	// Time-To-Live of a Record in the Disk Tier, measured in Seconds from the
+	// Moment when the Record has been written.
+	RecordTTL uint
+
+	// Maximum Count of evicted Records waiting to be written by a two-Tier
+	// Cache. When the Queue is full, evicted Records are dropped.
+	QueueSize int
+}
+
+// Statistics of the Disk Tier's Usage.
+type DiskTierStatistics struct {
+
+	// Count of Requests which have found an actual Record.
+	Hits uint64
+
+	// Count of Requests which have not found an actual Record.
+	Misses uint64
+
+	// Count of Records deleted because they were outdated.
+	Expirations uint64
+
+	// Count of Records removed to keep the Size within the Capacity.
+	Evictions uint64
+
+	// Count of Records written.
+	Writes uint64
+
+	// Count of Records which could not be written.
+	WriteErrors uint64
+
+	// Count of evicted Records dropped because the Queue of a two-Tier Cache
+	// was full.
+	Drops uint64
+
+	// Current Count of Records.
+	Records uint
+
+	// Current total Size of the Files of Records, in Bytes.
+	Size int64
+}
+
+// A bounded Store of Records on the local Disk. It serves as a second Tier
+// of the Cache for Records evicted from the Bottom.
+//
+// The Index of Records is kept in Memory, so the Records do not survive a
+// Restart of the Process.
+type DiskTier struct {
+	directory string
+	capacity  int64
+	recordTTL uint
+	codec     Codec
+
+	lock    sync.Mutex
+	records map[FixedSizeBubbleCacheRecordUID]*diskTierRecord
+
+	// Records in the Order of Writing, the oldest Record is at the Front.
+	order *list.List
+
+	size       int64
+	statistics DiskTierStatistics
+}
+
+// A Record of the Disk Tier's Index.
+type diskTierRecord struct {
+	uid  FixedSizeBubbleCacheRecordUID
+	ttl  uint
+	size int64
+
+	// Absolute Expiration Time of the Record in the Cache, if any.
+	recordExpirationTime time.Time
+
+	// Time when the Record is outdated in the Disk Tier.
+	expirationTime time.Time
+	element        *list.Element
+}
+
+// Creates a new Disk Tier. The Codec converts the Data of Records into Bytes.
+func NewDiskTier(
+	codec Codec,
+	settings DiskTierSettings,
+) (tier *DiskTier, err error) {
+	if len(settings.Directory) == 0 {
+		err = errors.New(ErrDiskTierDirectoryIsNotSet)
+		return
+	}
+	if codec == nil {
+		codec = GobCodec{}
+	}
+	if settings.Capacity <= 0 {
+		settings.Capacity = DiskTierCapacityDefault
+	}
+	if settings.RecordTTL == 0 {
+		settings.RecordTTL = DiskTierRecordTTLDefault
+	}
+
+	err = os.MkdirAll(settings.Directory, 0700)
+	if err != nil {
+		return
+	}
+	err = removeDiskTierFiles(settings.Directory)
+	if err != nil {
+		return
+	}
+
+	tier = &DiskTier{
+		directory: settings.Directory,
+		capacity:  settings.Capacity,
+		recordTTL: settings.RecordTTL,
+		codec:     codec,
+		records:   make(map[FixedSizeBubbleCacheRecordUID]*diskTierRecord),
+		order:     list.New(),
+	}
+	return
+}
+
+// Deletes the Files of Records from the Directory.
+func removeDiskTierFiles(
+	directory string,
+) (err error) {
+	var files []os.FileInfo
+	files, err = ioutil.ReadDir(directory)
+	if err != nil {
+		return
+	}
+
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), DiskTierFileExtension) {
+			continue
+		}
+		err = os.Remove(filepath.Join(directory, file.Name()))
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Writes the Record. An existing Record with the same UID is replaced.
+// The TTL is the individual TTL of the Record and the Expiration Time is its
+// absolute Expiration Time, zero Time means none. Both are returned by
+// 'Take'. The Record is outdated either after the Disk Tier's Record TTL or at
+// its absolute Expiration Time, whichever comes first.
+func (t *DiskTier) Put(
+	uid FixedSizeBubbleCacheRecordUID,
+	data interface{},
+	ttl uint,
+	expirationTime time.Time,
+) (err error) {
+	var contents []byte
+	contents, err = t.encodeFile(uid, data)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if err == nil && int64(len(contents)) > t.capacity {
+		err = fmt.Errorf(ErrfDiskTierRecordIsTooLarge, uid)
+	}
+	if err != nil {
+		t.statistics.WriteErrors++
+		return
+	}
+
+	var existingRecord, recordExists = t.records[uid]
+	if recordExists {
+		_ = t.removeRecord(existingRecord)
+	}
+	for t.size+int64(len(contents)) > t.capacity {
+		_ = t.removeRecord(t.order.Front().Value.(*diskTierRecord))
+		t.statistics.Evictions++
+	}
+
+	err = ioutil.WriteFile(t.filePath(uid), contents, 0600)
+	if err != nil {
+		_ = os.Remove(t.filePath(uid))
+		t.statistics.WriteErrors++
+		return
+	}
+
+	var record = &diskTierRecord{
+		uid:                  uid,
+		ttl:                  ttl,
+		size:                 int64(len(contents)),
+		recordExpirationTime: expirationTime,
+		expirationTime:       time.Now().Add(time.Duration(t.recordTTL) * time.Second),
+	}
+	if !expirationTime.IsZero() && expirationTime.Before(record.expirationTime) {
+		record.expirationTime = expirationTime
+	}
+	record.element = t.order.PushBack(record)
+	t.records[uid] = record
+	t.size += record.size
+	t.statistics.Writes++
+	return
+}
+
+// Reads the Record and removes it from the Disk Tier. Returns the Record's
+// Data, its individual TTL and its absolute Expiration Time.
+func (t *DiskTier) Take(
+	uid FixedSizeBubbleCacheRecordUID,
+) (data interface{}, ttl uint, expirationTime time.Time, err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var record, recordExists = t.records[uid]
+	if !recordExists {
+		t.statistics.Misses++
+		err = fmt.Errorf(ErrfRecordWithUidIsNotFound, uid)
+		return
+	}
+	if !time.Now().Before(record.expirationTime) {
+		_ = t.removeRecord(record)
+		t.statistics.Misses++
+		t.statistics.Expirations++
+		err = fmt.Errorf(ErrfRecordWithUidIsOutdated, uid)
+		return
+	}
+
+	var contents []byte
+	contents, err = ioutil.ReadFile(t.filePath(uid))
+	if err == nil {
+		data, err = t.decodeFile(uid, contents)
+	}
+	_ = t.removeRecord(record)
+	if err != nil {
+		t.statistics.Misses++
+		return
+	}
+
+	t.statistics.Hits++
+	ttl = record.ttl
+	expirationTime = record.recordExpirationTime
+	return
+}
+
+// Deletes the Record.
+func (t *DiskTier) Delete(
+	uid FixedSizeBubbleCacheRecordUID,
+) (err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var record, recordExists = t.records[uid]
+	if !recordExists {
+		err = fmt.Errorf(ErrfRecordWithUidIsNotFound, uid)
+		return
+	}
+	return t.removeRecord(record)
+}
+
+// Checks whether an actual Record with the specified UID exists.
+func (t *DiskTier) RecordUIDExists(
+	uid FixedSizeBubbleCacheRecordUID,
+) (uidExists bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var record, recordExists = t.records[uid]
+	return recordExists && time.Now().Before(record.expirationTime)
+}
+
+// Deletes all Records.
+func (t *DiskTier) Clear() (err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for t.order.Len() > 0 {
+		var removeErr = t.removeRecord(t.order.Front().Value.(*diskTierRecord))
+		if removeErr != nil {
+			err = removeErr
+		}
+	}
+	return
+}
+
+// Counts Records which have been dropped before they could be written.
+func (t *DiskTier) countDrops(
+	dropsCount uint64,
+) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.statistics.Drops += dropsCount
+}
+
+// Returns the Statistics of the Disk Tier's Usage.
+func (t *DiskTier) Stats() (stats DiskTierStatistics) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	stats = t.statistics
+	stats.Records = uint(len(t.records))
+	stats.Size = t.size
+	return
+}
+
+// Removes the Record from the Index and deletes its File.
+func (t *DiskTier) removeRecord(
+	record *diskTierRecord,
+) (err error) {
+	t.order.Remove(record.element)
+	delete(t.records, record.uid)
+	t.size -= record.size
+
+	err = os.Remove(t.filePath(record.uid))
+	if os.IsNotExist(err) {
+		err = nil
+	}
+	return
+}
+
+// Returns the Path of the Record's File.
+func (t *DiskTier) filePath(
+	uid FixedSizeBubbleCacheRecordUID,
+) string {
+	var hash = sha256.Sum256([]byte(uid))
+	return filepath.Join(t.directory, hex.EncodeToString(hash[:])+DiskTierFileExtension)
+}
+
+// Encodes the Contents of the Record's File.
+func (t *DiskTier) encodeFile(
+	uid FixedSizeBubbleCacheRecordUID,
+	data interface{},
+) (contents []byte, err error) {
+	var encodedData []byte
+	encodedData, err = t.codec.Encode(data)
+	if err != nil {
+		return
+	}
+
+	contents = make([]byte, 0, diskTierFileFixedSize+len(uid)+len(encodedData))
+	contents = append(contents, DiskTierFileMagic...)
+	contents = append(contents, DiskTierFileFormatVersion)
+	var sizeBuf [4]byte
+	binary.BigEndian.PutUint32(sizeBuf[:], uint32(len(uid)))
+	contents = append(contents, sizeBuf[:]...)
+	contents = append(contents, uid...)
+	contents = append(contents, encodedData...)
+	binary.BigEndian.PutUint32(sizeBuf[:], crc32.ChecksumIEEE(contents))
+	contents = append(contents, sizeBuf[:]...)
+	return
+}
+
+// Decodes the Contents of the Record's File.
+func (t *DiskTier) decodeFile(
+	uid FixedSizeBubbleCacheRecordUID,
+	contents []byte,
+) (data interface{}, err error) {
+	var brokenFileErr = fmt.Errorf(ErrfDiskTierFileIsBroken, uid)
+	if len(contents) < diskTierFileFixedSize {
+		return nil, brokenFileErr
+	}
+
+	var checksumOffset = len(contents) - 4
+	if crc32.ChecksumIEEE(contents[:checksumOffset]) != binary.BigEndian.Uint32(contents[checksumOffset:]) {
+		return nil, brokenFileErr
+	}
+	if !bytes.Equal(contents[:4], []byte(DiskTierFileMagic)) || (contents[4] != DiskTierFileFormatVersion) {
+		return nil, brokenFileErr
+	}
+
+	var p = contents[5:checksumOffset]
+	var uidSize = binary.BigEndian.Uint32(p[:4])
+	p = p[4:]
+	if (uint64(uidSize) > uint64(len(p))) || (string(p[:uidSize]) != uid) {
+		return nil, brokenFileErr
+	}
+
+	return t.codec.Decode(p[uidSize:])
+}
@@ -0,0 +1,140 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/tester"
+)
+
+func Test_NewDiskTier(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var directory = t.TempDir()
+	var err error
+
+	// Test #1. No Directory.
+	_, err = NewDiskTier(RawCodec{}, DiskTierSettings{})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrDiskTierDirectoryIsNotSet)
+
+	// Test #2. Default Settings, old Files are deleted.
+	aTest.MustBeNoError(ioutil.WriteFile(filepath.Join(directory, "old"+DiskTierFileExtension), []byte("x"), 0600))
+	aTest.MustBeNoError(ioutil.WriteFile(filepath.Join(directory, "other.txt"), []byte("x"), 0600))
+	var tier *DiskTier
+	tier, err = NewDiskTier(nil, DiskTierSettings{Directory: directory})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(tier.capacity, int64(DiskTierCapacityDefault))
+	aTest.MustBeEqual(tier.recordTTL, uint(DiskTierRecordTTLDefault))
+	aTest.MustBeEqual(tier.codec, Codec(GobCodec{}))
+	var files, _ = ioutil.ReadDir(directory)
+	aTest.MustBeEqual(len(files), 1)
+	aTest.MustBeEqual(files[0].Name(), "other.txt")
+}
+
+func Test_DiskTier_Put(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var tier, err = NewDiskTier(RawCodec{}, DiskTierSettings{Directory: t.TempDir(), Capacity: 100})
+	aTest.MustBeNoError(err)
+	var recordSize = int64(diskTierFileFixedSize + 1 + 10)
+
+	// Test #1. Writing and Replacing.
+	aTest.MustBeNoError(tier.Put("a", []byte("0123456789"), 0, time.Time{}))
+	aTest.MustBeNoError(tier.Put("a", []byte("9876543210"), 5, time.Time{}))
+	var stats = tier.Stats()
+	aTest.MustBeEqual(stats.Records, uint(1))
+	aTest.MustBeEqual(stats.Size, recordSize)
+	aTest.MustBeEqual(stats.Writes, uint64(2))
+
+	// Test #2. The oldest Records are evicted to keep the Capacity.
+	for _, uid := range []string{"b", "c", "d", "e"} {
+		aTest.MustBeNoError(tier.Put(uid, []byte("0123456789"), 0, time.Time{}))
+	}
+	stats = tier.Stats()
+	aTest.MustBeEqual(stats.Records, uint(4))
+	aTest.MustBeEqual(stats.Size, recordSize*4)
+	aTest.MustBeEqual(stats.Evictions, uint64(1))
+	aTest.MustBeEqual(tier.RecordUIDExists("a"), false)
+	aTest.MustBeEqual(tier.RecordUIDExists("b"), true)
+
+	// Test #3. Too large Record.
+	err = tier.Put("f", []byte(strings.Repeat("x", 100)), 0, time.Time{})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfDiskTierRecordIsTooLarge, "f"))
+
+	// Test #4. Data which is not supported by the Codec.
+	err = tier.Put("g", 123, 0, time.Time{})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(tier.Stats().WriteErrors, uint64(2))
+	aTest.MustBeEqual(tier.Stats().Records, uint(4))
+}
+
+func Test_DiskTier_Take(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var directory = t.TempDir()
+	var tier, err = NewDiskTier(GobCodec{}, DiskTierSettings{Directory: directory})
+	aTest.MustBeNoError(err)
+	var data interface{}
+	var ttl uint
+
+	// Test #1. Record is taken out of the Disk Tier.
+	aTest.MustBeNoError(tier.Put("a", "value", 30, time.Time{}))
+	data, ttl, _, err = tier.Take("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "value")
+	aTest.MustBeEqual(ttl, uint(30))
+	aTest.MustBeEqual(tier.RecordUIDExists("a"), false)
+	var files, _ = ioutil.ReadDir(directory)
+	aTest.MustBeEqual(len(files), 0)
+
+	// Test #2. Missing Record.
+	_, _, _, err = tier.Take("a")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfRecordWithUidIsNotFound, "a"))
+
+	// Test #3. Outdated Record.
+	aTest.MustBeNoError(tier.Put("b", "value", 0, time.Time{}))
+	tier.records["b"].expirationTime = time.Now().Add(-time.Second)
+	aTest.MustBeEqual(tier.RecordUIDExists("b"), false)
+	_, _, _, err = tier.Take("b")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfRecordWithUidIsOutdated, "b"))
+
+	// Test #4. Broken File.
+	aTest.MustBeNoError(tier.Put("c", "value", 0, time.Time{}))
+	aTest.MustBeNoError(ioutil.WriteFile(tier.filePath("c"), []byte("broken file contents"), 0600))
+	_, _, _, err = tier.Take("c")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfDiskTierFileIsBroken, "c"))
+
+	var stats = tier.Stats()
+	aTest.MustBeEqual(stats.Hits, uint64(1))
+	aTest.MustBeEqual(stats.Misses, uint64(3))
+	aTest.MustBeEqual(stats.Expirations, uint64(1))
+	aTest.MustBeEqual(stats.Records, uint(0))
+	aTest.MustBeEqual(stats.Size, int64(0))
+}
+
+func Test_DiskTier_Delete(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var tier, err = NewDiskTier(RawCodec{}, DiskTierSettings{Directory: t.TempDir()})
+	aTest.MustBeNoError(err)
+
+	// Test #1. Deletion.
+	aTest.MustBeNoError(tier.Put("a", []byte("1"), 0, time.Time{}))
+	aTest.MustBeNoError(tier.Put("b", []byte("2"), 0, time.Time{}))
+	aTest.MustBeNoError(tier.Delete("a"))
+	err = tier.Delete("a")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfRecordWithUidIsNotFound, "a"))
+
+	// Test #2. Clear.
+	aTest.MustBeNoError(tier.Clear())
+	aTest.MustBeEqual(tier.RecordUIDExists("b"), false)
+	aTest.MustBeEqual(tier.Stats().Size, int64(0))
+}
@@ -41,24 +41,100 @@ type FixedSizeBubbleCache struct {
 	capacity uint
 
 	// An internal List of Records that may be fast requested by their unique
-	// Identifier, a UID.
-	recordsByUID sync.Map
+	// Identifier, a UID. It maps UIDs to Indices of the Storage.
+	recordsByUID recordIndex
+
+	// The Storage of Records. It is allocated once, with the Capacity of the
+	// Cache, and Records are linked by their Indices in the Storage, so that
+	// Additions and Evictions do not allocate Memory. The Top and the Bottom
+	// Records point into the Storage.
+	records []FixedSizeBubbleCacheRecord
+
+	// Index of the first free Record of the Storage. Free Records are linked
+	// by their 'lower' Indices.
+	freeRecord int32
+
+	// The last Version assigned to the Data of a Record.
+	lastVersion uint64
 
 	// Record's Time-To-Live (TTL) is the Period of Time, after which the
 	// Record is considered outdated. If a Record requested from the Cache is
 	// outdated, it is removed from the Cache. Record's TTL is measured in
 	// Seconds.
 	recordTTL uint
+
+	// Time-To-Live of a negative Record, i.e. of a Record which remembers that
+	// the Data with the Record's UID does not exist in the external Source.
+	// Negative Records usually live shorter than ordinary Records. Zero TTL
+	// disables negative Caching. TTL is measured in Seconds.
+	negativeRecordTTL uint
+
+	// Statistics of the Cache's Usage.
+	statistics FixedSizeBubbleCacheStatistics
+
+	// A Codec which converts the Data of Records into Bytes and back.
+	codec Codec
+
+	// An optional Journal which receives all the Changes of the Cache.
+	journal cacheJournal
+
+	// A Lock which protects the Cache from simultaneous Access.
+	lock sync.Mutex
+
+	// An optional Function which loads the Data of missing Records.
+	loader FixedSizeBubbleCacheLoader
+
+	// Optional Settings of the probabilistic early Expiration.
+	// When they are not set, Records are reloaded only when they are outdated.
+	earlyExpiration *FixedSizeBubbleCacheEarlyExpiration
+
+	// An optional Function which receives Records evicted from the Bottom.
+	evictionHandler FixedSizeBubbleCacheEvictionHandler
+
+	// The current Count of pinned Records.
+	pinnedCount uint
+
+	// The maximum Count of pinned Records. Zero Limit selects the default
+	// Limit, which is one Record less than the Capacity.
+	pinnedRecordsLimit uint
 }
 
+// Maximum Capacity of the Cache. Records are linked by 32-Bit Indices.
+const MaxCapacity = uint(^uint32(0) >> 1)
+
 // Creates a new fixed-Size Bubble Cache.
+//
+// The Storage of Records and the Index of UIDs are allocated at once, for the
+// whole Capacity: a Record and from two to four Slots of the Index per each
+// Unit of the Capacity. So the Memory used by an empty Cache is proportional
+// to its Capacity. The Capacity is limited by the 'MaxCapacity', a larger
+// Capacity causes a Panic. The 'NewCheckedFixedSizeBubbleCache' Function
+// returns an Error instead.
 func NewFixedSizeBubbleCache(
 	capacity uint,
 	recordTTL uint,
 ) (cache *FixedSizeBubbleCache) {
+	var err error
+	cache, err = NewCheckedFixedSizeBubbleCache(capacity, recordTTL)
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// Creates a new fixed-Size Bubble Cache as the 'NewFixedSizeBubbleCache'
+// Function does. Returns an Error when the Capacity exceeds the
+// 'MaxCapacity'.
+func NewCheckedFixedSizeBubbleCache(
+	capacity uint,
+	recordTTL uint,
+) (cache *FixedSizeBubbleCache, err error) {
 	if capacity == 0 {
 		capacity++
 	}
+	if capacity > MaxCapacity {
+		return nil, fmt.Errorf(ErrfCapacityIsTooLarge, capacity, MaxCapacity)
+	}
 	cache = new(FixedSizeBubbleCache)
 	cache.initialize(capacity, recordTTL)
 	return
@@ -74,12 +150,97 @@ func (c *FixedSizeBubbleCache) initialize(
 	c.size = 0
 	c.capacity = capacity
 	c.recordTTL = recordTTL
+
+	c.records = make([]FixedSizeBubbleCacheRecord, capacity)
+	for i := range c.records {
+		c.records[i].index = int32(i)
+		c.records[i].upper = noRecord
+		c.records[i].lower = int32(i + 1)
+	}
+	c.records[capacity-1].lower = noRecord
+	c.freeRecord = 0
+	c.recordsByUID = newRecordIndex(c.records, nil)
+}
+
+// Takes a free Record from the Storage and copies the Contents of the Record
+// into it. The stored Record gets a new Version. The Storage must have a free
+// Record.
+func (c *FixedSizeBubbleCache) storeRecord(
+	source *FixedSizeBubbleCacheRecord,
+) (record *FixedSizeBubbleCacheRecord) {
+	var i = c.freeRecord
+	record = &c.records[i]
+	c.freeRecord = record.lower
+
+	*record = FixedSizeBubbleCacheRecord{
+		UID:            source.UID,
+		Data:           source.Data,
+		lastAccessTime: source.lastAccessTime,
+		updateTime:     source.updateTime,
+		ttl:            source.ttl,
+		expirationTime: source.expirationTime,
+		isNegative:     source.isNegative,
+		loadDuration:   source.loadDuration,
+		version:        c.nextVersion(),
+		isStored:       true,
+		index:          i,
+		upper:          noRecord,
+		lower:          noRecord,
+	}
+	return
+}
+
+// Returns the Record to the free Records of the Storage. Its Contents are
+// cleared, so that its Data may be collected by the Garbage Collector.
+func (c *FixedSizeBubbleCache) releaseRecord(
+	record *FixedSizeBubbleCacheRecord,
+) {
+	var i = record.index
+	*record = FixedSizeBubbleCacheRecord{
+		index: i,
+		upper: noRecord,
+		lower: c.freeRecord,
+	}
+	c.freeRecord = i
+}
+
+// Returns the Record stored at the Index, or null for 'noRecord'.
+func (c *FixedSizeBubbleCache) recordAt(
+	i int32,
+) *FixedSizeBubbleCacheRecord {
+	if i == noRecord {
+		return nil
+	}
+	return &c.records[i]
+}
+
+// Returns the upper Record of the Record, or null for the Top.
+func (c *FixedSizeBubbleCache) upperOf(
+	record *FixedSizeBubbleCacheRecord,
+) *FixedSizeBubbleCacheRecord {
+	return c.recordAt(record.upper)
+}
+
+// Returns the lower Record of the Record, or null for the Bottom.
+func (c *FixedSizeBubbleCache) lowerOf(
+	record *FixedSizeBubbleCacheRecord,
+) *FixedSizeBubbleCacheRecord {
+	return c.recordAt(record.lower)
+}
+
+// Checks whether the Index points into the Storage.
+func (c *FixedSizeBubbleCache) isValidIndex(
+	i int32,
+) bool {
+	return (i >= 0) && (int(i) < len(c.records))
 }
 
 // Checks the Record's Parameters and adds it to the fixed-Size Bubble Cache.
 func (c *FixedSizeBubbleCache) AddRecord(
 	record *FixedSizeBubbleCacheRecord,
 ) (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
 
 	// Checks.
 	if record == nil {
@@ -105,36 +266,42 @@ func (c *FixedSizeBubbleCache) AddRecord(
 //	If the Cache is at its maximum Size (Size is equal to Capacity) and a new
 //	Record must be added,
 //		then the Bottom Record is removed from the Cache.
+//
+// The Contents of the added Record are copied into the Storage, the added
+// Record itself is not kept by the Cache.
 func (c *FixedSizeBubbleCache) addRecord(
 	addedRecord *FixedSizeBubbleCacheRecord,
 ) {
-	var existingRecordIfc interface{}
-	var uidExists bool
-	existingRecordIfc, uidExists = c.recordsByUID.Load(addedRecord.UID)
+	var existingIndex, uidExists = c.recordsByUID.find(addedRecord.UID)
 	if uidExists {
-		var existingRecord *FixedSizeBubbleCacheRecord
-		var ok bool
-		existingRecord, ok = existingRecordIfc.(*FixedSizeBubbleCacheRecord)
-		if !ok {
-			panic(ErrTypeCast)
-		}
+		var existingRecord = &c.records[existingIndex]
 		if existingRecord != c.top {
 			c.moveExistingRecordToTop(existingRecord)
 		}
-		c.top.UpdateDataAndLAT(addedRecord.Data)
+		if existingRecord.isNegative != addedRecord.isNegative {
+			c.countNegativeRecord(existingRecord, -1)
+			c.countNegativeRecord(addedRecord, 1)
+		}
+		c.top.updateData(addedRecord.Data, c.now())
+		c.top.version = c.nextVersion()
+		c.top.isNegative = addedRecord.isNegative
+		c.top.ttl = addedRecord.ttl
+		c.top.expirationTime = addedRecord.expirationTime
+		c.journalRecordAdded(c.top)
 		return
 	}
 	if c.size == c.capacity {
-		var removedRecord *FixedSizeBubbleCacheRecord = c.unlinkBottomRecord()
-		c.recordsByUID.Delete(removedRecord.UID)
+		c.evictRecord(c.evictionCandidate())
 	}
-	c.linkTopRecord(addedRecord)
+	var record = c.storeRecord(addedRecord)
+	c.linkTopRecord(record)
+	c.countNegativeRecord(record, 1)
 
-	c.recordsByUID.Store(addedRecord.UID, addedRecord)
-	c.top.UpdateDataAndLAT(addedRecord.Data)
-	if c.size != c.capacity {
-		c.size++ // We can not increase the Size prior to Linking.
-	}
+	c.recordsByUID.insert(record.UID, record.index)
+	c.top.updateData(record.Data, c.now())
+	c.top.creationTime = c.top.lastAccessTime
+	c.size++ // We can not increase the Size prior to Linking.
+	c.journalRecordAdded(c.top)
 }
 
 // Moves the existing Record to the Top.
@@ -164,10 +331,10 @@ func (c *FixedSizeBubbleCache) moveExistingRecordToTop(
 // it does not touch the Size Counter.
 func (c *FixedSizeBubbleCache) unlinkTopRecord() (oldTop *FixedSizeBubbleCacheRecord) {
 	oldTop = c.top
-	c.top = oldTop.lowerRecord
-	c.top.upperRecord = nil
-	oldTop.lowerRecord = nil
-	oldTop.upperRecord = nil
+	c.top = c.lowerOf(oldTop)
+	c.top.upper = noRecord
+	oldTop.lower = noRecord
+	oldTop.upper = noRecord
 	return
 }
 
@@ -178,10 +345,10 @@ func (c *FixedSizeBubbleCache) unlinkTopRecord() (oldTop *FixedSizeBubbleCacheRe
 func (c *FixedSizeBubbleCache) unlinkMiddleRecord(
 	record *FixedSizeBubbleCacheRecord,
 ) *FixedSizeBubbleCacheRecord {
-	record.upperRecord.lowerRecord = record.lowerRecord
-	record.lowerRecord.upperRecord = record.upperRecord
-	record.upperRecord = nil
-	record.lowerRecord = nil
+	c.records[record.upper].lower = record.lower
+	c.records[record.lower].upper = record.upper
+	record.upper = noRecord
+	record.lower = noRecord
 	return record
 }
 
@@ -191,10 +358,10 @@ func (c *FixedSizeBubbleCache) unlinkMiddleRecord(
 // it does not touch the Size Counter.
 func (c *FixedSizeBubbleCache) unlinkBottomRecord() (oldBottom *FixedSizeBubbleCacheRecord) {
 	oldBottom = c.bottom
-	c.bottom = oldBottom.upperRecord
-	c.bottom.lowerRecord = nil
-	oldBottom.upperRecord = nil
-	oldBottom.lowerRecord = nil
+	c.bottom = c.upperOf(oldBottom)
+	c.bottom.lower = noRecord
+	oldBottom.upper = noRecord
+	oldBottom.lower = noRecord
 	return
 }
 
@@ -208,12 +375,12 @@ func (c *FixedSizeBubbleCache) linkTopRecord(
 	if c.size == 0 {
 		c.top = newTop
 		c.bottom = newTop
-		newTop.upperRecord = nil
-		newTop.lowerRecord = nil
+		newTop.upper = noRecord
+		newTop.lower = noRecord
 	} else {
-		c.top.upperRecord = newTop
-		newTop.upperRecord = nil
-		newTop.lowerRecord = c.top
+		c.top.upper = newTop.index
+		newTop.upper = noRecord
+		newTop.lower = c.top.index
 		c.top = newTop
 	}
 	return newTop
@@ -229,12 +396,12 @@ func (c *FixedSizeBubbleCache) linkBottomRecord(
 	if c.size == 0 {
 		c.top = newBottom
 		c.bottom = newBottom
-		newBottom.upperRecord = nil
-		newBottom.lowerRecord = nil
+		newBottom.upper = noRecord
+		newBottom.lower = noRecord
 	} else {
-		c.bottom.lowerRecord = newBottom
-		newBottom.upperRecord = c.bottom
-		newBottom.lowerRecord = nil
+		c.bottom.lower = newBottom.index
+		newBottom.upper = c.bottom.index
+		newBottom.lower = noRecord
 		c.bottom = newBottom
 	}
 	return newBottom
@@ -243,6 +410,14 @@ func (c *FixedSizeBubbleCache) linkBottomRecord(
 // Deletes all Records from the Cache.
 // As opposed to other Deletion Methods, this Method uses the Integrity Check.
 func (c *FixedSizeBubbleCache) Clear() (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.clear()
+}
+
+// Deletes all Records from the Cache using the Integrity Check.
+func (c *FixedSizeBubbleCache) clear() (err error) {
 
 	// Before deleting the Records, we must ensure that Cache is not broken.
 	// Broken Cache Deletion would cost us a lot of Memory Leaks!
@@ -268,16 +443,10 @@ func (c *FixedSizeBubbleCache) Clear() (err error) {
 func (c *FixedSizeBubbleCache) isIntegral() bool {
 
 	// Check Fast Access Register.
-	var ok bool = true
-	var nilSearcher = func(key, value interface{}) bool {
-		if value == nil {
-			ok = false
-			return false
-		}
-		return true
+	if !c.recordsByUID.isIntegral() {
+		return false
 	}
-	c.recordsByUID.Range(nilSearcher)
-	if !ok {
+	if c.recordsByUID.len() != c.size {
 		return false
 	}
 
@@ -313,10 +482,10 @@ func (c *FixedSizeBubbleCache) isIntegral() bool {
 		if top != bottom {
 			return false
 		}
-		if top.upperRecord != nil {
+		if top.upper != noRecord {
 			return false
 		}
-		if bottom.lowerRecord != nil {
+		if bottom.lower != noRecord {
 			return false
 		}
 		return true
@@ -325,26 +494,25 @@ func (c *FixedSizeBubbleCache) isIntegral() bool {
 	// List has two or more Items.
 
 	// Check the Top.
-	if top.upperRecord != nil {
+	if top.upper != noRecord {
 		return false
 	}
 	// Check the Bottom.
-	if bottom.lowerRecord != nil {
+	if bottom.lower != noRecord {
 		return false
 	}
 
 	// Try to inspect all the Items from Top to Bottom.
 	// This checks Connectivity by the 'lower' Pointer.
 	var cursor *FixedSizeBubbleCacheRecord
-	var cursorLowerRecord *FixedSizeBubbleCacheRecord
-	var cursorUpperRecord *FixedSizeBubbleCacheRecord
 	cursor = top
-	cursorLowerRecord = cursor.lowerRecord
 	var i uint = 1
 	var sizeAnomaly bool
-	for cursorLowerRecord != nil {
-		cursor = cursorLowerRecord
-		cursorLowerRecord = cursor.lowerRecord
+	for cursor.lower != noRecord {
+		if !c.isValidIndex(cursor.lower) {
+			return false
+		}
+		cursor = &c.records[cursor.lower]
 		i++
 		// Defence against Self-Loop Anomaly.
 		if i > size {
@@ -370,11 +538,12 @@ func (c *FixedSizeBubbleCache) isIntegral() bool {
 	// Now, try to inspect all Items in a reversed Order.
 	// This checks Connectivity by the 'upper' Pointer.
 	cursor = bottom
-	cursorUpperRecord = cursor.upperRecord
 	i = 1
-	for cursorUpperRecord != nil {
-		cursor = cursorUpperRecord
-		cursorUpperRecord = cursor.upperRecord
+	for cursor.upper != noRecord {
+		if !c.isValidIndex(cursor.upper) {
+			return false
+		}
+		cursor = &c.records[cursor.upper]
 		i++
 		// Defence against Self-Loop Anomaly.
 		if i > size {
@@ -417,19 +586,23 @@ func (c *FixedSizeBubbleCache) deleteRecord(
 		return
 	}
 	if !recordIsKnownToExist {
-		var recordExists bool = c.recordUIDExists(record.UID)
-		if !recordExists {
-			err = fmt.Errorf(ErrfRecordWithUidIsNotFound, record.UID)
+		// The Record may be a Copy, so the stored Record is deleted.
+		record, err = c.getRecordByUID(record.UID)
+		if err != nil {
 			return
 		}
 	}
+	c.journalRecordDeleted(record)
 
 	// A single-Record Cache.
 	if c.size == 1 {
 		c.top = nil
 		c.bottom = nil
 		c.size--
-		c.recordsByUID.Delete(record.UID)
+		c.recordsByUID.remove(record.UID)
+		c.countNegativeRecord(record, -1)
+		c.countPinnedRecord(record, -1)
+		c.releaseRecord(record)
 		return
 	}
 
@@ -442,7 +615,10 @@ func (c *FixedSizeBubbleCache) deleteRecord(
 		c.unlinkMiddleRecord(record)
 	}
 	c.size--
-	c.recordsByUID.Delete(record.UID)
+	c.recordsByUID.remove(record.UID)
+	c.countNegativeRecord(record, -1)
+	c.countPinnedRecord(record, -1)
+	c.releaseRecord(record)
 	return
 }
 
@@ -450,6 +626,9 @@ func (c *FixedSizeBubbleCache) deleteRecord(
 func (c *FixedSizeBubbleCache) RecordUIDExists(
 	uid FixedSizeBubbleCacheRecordUID,
 ) (uidExists bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
 	return c.recordUIDExists(uid)
 }
 
@@ -457,7 +636,7 @@ func (c *FixedSizeBubbleCache) RecordUIDExists(
 func (c *FixedSizeBubbleCache) recordUIDExists(
 	uid FixedSizeBubbleCacheRecordUID,
 ) (uidExists bool) {
-	_, uidExists = c.recordsByUID.Load(uid)
+	_, uidExists = c.recordsByUID.find(uid)
 	return
 }
 
@@ -465,18 +644,12 @@ func (c *FixedSizeBubbleCache) recordUIDExists(
 func (c *FixedSizeBubbleCache) getRecordByUID(
 	uid FixedSizeBubbleCacheRecordUID,
 ) (record *FixedSizeBubbleCacheRecord, err error) {
-	var recordIsFound bool
-	var recordIfc interface{}
-	recordIfc, recordIsFound = c.recordsByUID.Load(uid)
+	var i, recordIsFound = c.recordsByUID.find(uid)
 	if !recordIsFound {
 		err = fmt.Errorf(ErrfRecordWithUidIsNotFound, uid)
 		return
 	}
-	var ok bool
-	record, ok = recordIfc.(*FixedSizeBubbleCacheRecord)
-	if !ok {
-		panic(ErrTypeCast)
-	}
+	record = &c.records[i]
 	return
 }
 
@@ -484,6 +657,9 @@ func (c *FixedSizeBubbleCache) getRecordByUID(
 func (c *FixedSizeBubbleCache) DeleteRecordByUID(
 	uid FixedSizeBubbleCacheRecordUID,
 ) (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
 	var record *FixedSizeBubbleCacheRecord
 	record, err = c.getRecordByUID(uid)
 	if err != nil {
@@ -496,8 +672,12 @@ func (c *FixedSizeBubbleCache) DeleteRecordByUID(
 	return
 }
 
-// Lists the Values of all Records of the Cache.
+// Lists the Values of all Records of the Cache. The Values themselves are not
+// copied, so Values of reference Types must not be modified by the Caller.
 func (c *FixedSizeBubbleCache) ListAllRecordValues() (values []interface{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
 	values = make([]interface{}, c.size)
 	if c.size == 0 {
 		return
@@ -510,14 +690,19 @@ func (c *FixedSizeBubbleCache) ListAllRecordValues() (values []interface{}) {
 	// Get all the rest Items.
 	var i uint
 	for i = 1; i < c.size; i++ {
-		record = record.lowerRecord
+		record = c.lowerOf(record)
 		values[i] = record.Data
 	}
 	return
 }
 
-// Lists all the Records of the Cache.
+// Lists Copies of all the Records of the Cache. The Copies are not linked to
+// the Cache, so changing them does not affect the Cache, and they may be added
+// to any Cache. Records of a Cache themselves are rejected by 'AddRecord'.
 func (c *FixedSizeBubbleCache) ListAllRecords() (records []*FixedSizeBubbleCacheRecord) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
 	records = make([]*FixedSizeBubbleCacheRecord, c.size)
 	if c.size == 0 {
 		return records
@@ -525,37 +710,50 @@ func (c *FixedSizeBubbleCache) ListAllRecords() (records []*FixedSizeBubbleCache
 
 	// Get the first Item.
 	var record *FixedSizeBubbleCacheRecord = c.top
-	records[0] = record
+	records[0] = record.unlinkedCopy()
 
 	// Get all the rest Items.
 	var i uint
 	for i = 1; i < c.size; i++ {
-		record = record.lowerRecord
-		records[i] = record
+		record = c.lowerOf(record)
+		records[i] = record.unlinkedCopy()
 	}
 	return
 }
 
 // Gets the Record's Data by its UID. Moves the Record to the Top of the List
 // and refreshes its LAT. If the Record is outdated, deletes it and returns an
-// Error.
+// Error. If the Record is negative, returns an Error.
 func (c *FixedSizeBubbleCache) GetActualRecordDataByUID(
 	uid FixedSizeBubbleCacheRecordUID,
 ) (data interface{}, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.getActualRecordDataByUID(uid)
+}
+
+// Gets the Record's Data by its UID.
+func (c *FixedSizeBubbleCache) getActualRecordDataByUID(
+	uid FixedSizeBubbleCacheRecordUID,
+) (data interface{}, err error) {
 
 	// Get the Record.
 	var record *FixedSizeBubbleCacheRecord
 	record, err = c.getRecordByUID(uid)
 	if err != nil {
+		c.statistics.Misses++
 		return
 	}
 
 	// Check the TTL. Is the Record Outdated ?
-	if !record.isActual(c.recordTTL) {
+	if !c.isRecordActual(record) {
 		err = c.deleteRecord(record, true)
 		if err != nil {
 			return
 		}
+		c.statistics.Misses++
+		c.statistics.Expirations++
 		err = fmt.Errorf(ErrfRecordWithUidIsOutdated, uid)
 		return
 	}
@@ -564,7 +762,16 @@ func (c *FixedSizeBubbleCache) GetActualRecordDataByUID(
 	if record != c.top {
 		c.moveExistingRecordToTop(record)
 	}
-	c.top.UpdateLAT()
+	c.top.lastAccessTime = c.now()
+	c.top.accessCount++
+	c.journalRecordTouched(c.top)
+
+	if record.isNegative {
+		c.statistics.NegativeHits++
+		err = fmt.Errorf(ErrfRecordWithUidIsNegative, uid)
+		return
+	}
+	c.statistics.Hits++
 
 	data = record.Data
 	return
@@ -575,11 +782,54 @@ func (c *FixedSizeBubbleCache) GetRecordTTL() uint {
 	return c.recordTTL
 }
 
+// Returns the 'Capacity' Parameter of the Cache.
+func (c *FixedSizeBubbleCache) GetCapacity() uint {
+	return c.capacity
+}
+
+// Returns the TTL which is applied to the Record.
+func (c *FixedSizeBubbleCache) getTTLOfRecord(
+	record *FixedSizeBubbleCacheRecord,
+) uint {
+	if record.isNegative {
+		return c.negativeRecordTTL
+	}
+	if record.ttl > 0 {
+		return record.ttl
+	}
+	return c.recordTTL
+}
+
+// Returns the Time when the Record becomes outdated, as Unix Time in Seconds.
+// A negative Record lives for a fixed Period after its Addition, Requests do
+// not extend it, so that the Source is checked again in Time. Neither do they
+// extend the absolute Expiration Time of a Record.
+func (c *FixedSizeBubbleCache) expirationTimeOfRecord(
+	record *FixedSizeBubbleCacheRecord,
+) uint {
+	if record.isNegative {
+		return record.updateTime + c.negativeRecordTTL
+	}
+	if record.expirationTime > 0 {
+		return record.expirationTime
+	}
+	return record.lastAccessTime + c.getTTLOfRecord(record)
+}
+
+// Checks whether the Record is actual by the Cache's Clock.
+func (c *FixedSizeBubbleCache) isRecordActual(
+	record *FixedSizeBubbleCacheRecord,
+) bool {
+	return c.now() < c.expirationTimeOfRecord(record)
+}
+
 // Checks whether the specified Record's UID exists in the Cache and
 // the Record with such UID is still active (not outdated).
 func (c *FixedSizeBubbleCache) IsRecordUIDActive(
 	uid FixedSizeBubbleCacheRecordUID,
 ) (recordIsActive bool, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
 
 	// Get the Record.
 	var record *FixedSizeBubbleCacheRecord
@@ -588,6 +838,6 @@ func (c *FixedSizeBubbleCache) IsRecordUIDActive(
 		return
 	}
 
-	recordIsActive = record.isActual(c.recordTTL)
+	recordIsActive = c.isRecordActual(record)
 	return
 }
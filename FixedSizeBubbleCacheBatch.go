@@ -0,0 +1,126 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Checks the Records' Parameters and adds them to the Cache at once. Returns
+// an Error for each Record, successfully added Records have null Errors.
+//
+// The Records are added in their Order, as with the 'AddRecord' Method, so
+// the last Record becomes the Top. When a UID is repeated, its last Record is
+// used. The Space for new Records is freed once, before the Additions, by
+// evicting unpinned Bottom Records which are not in the Batch. Pinned Records
+// which are not in the Batch are kept, so when the Batch has more Records than
+// the Rest of the Capacity, only the last Records of the Batch are added and
+// the first Records get an Error. Records of the Batch never evict each other.
+func (c *FixedSizeBubbleCache) AddRecords(
+	records []*FixedSizeBubbleCacheRecord,
+) (errs []error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	errs = make([]error, len(records))
+
+	// Checks. The last Record of each UID is kept.
+	var lastRecords = make(map[FixedSizeBubbleCacheRecordUID]int, len(records))
+	for i, record := range records {
+		if record == nil {
+			errs[i] = errors.New(ErrRecordIsNotSet)
+			continue
+		}
+		errs[i] = record.Check()
+		if errs[i] != nil {
+			continue
+		}
+		lastRecords[record.UID] = i
+	}
+
+	// The Room for the Batch is the Capacity without pinned Records which are
+	// not in the Batch. Records which do not fit are dropped, starting from the
+	// first One. A dropped Record which is pinned leaves the Batch and takes
+	// its Place in the Room.
+	var keptRecords = make([]int, 0, len(lastRecords))
+	var room = c.capacity - c.pinnedCount
+	for i, record := range records {
+		if (errs[i] == nil) && (lastRecords[record.UID] == i) {
+			keptRecords = append(keptRecords, i)
+			if c.isPinnedUID(record.UID) {
+				room++
+			}
+		}
+	}
+	for uint(len(keptRecords)) > room {
+		var i = keptRecords[0]
+		errs[i] = fmt.Errorf(ErrfRecordWithUidDoesNotFit, records[i].UID)
+		delete(lastRecords, records[i].UID)
+		keptRecords = keptRecords[1:]
+		if c.isPinnedUID(records[i].UID) {
+			room--
+		}
+	}
+
+	// Evictions.
+	var newRecordsCount uint
+	for _, i := range keptRecords {
+		if !c.recordUIDExists(records[i].UID) {
+			newRecordsCount++
+		}
+	}
+
+	var cursor = c.bottom
+	for (cursor != nil) && (c.size+newRecordsCount > c.capacity) {
+		var upperRecord = c.upperOf(cursor)
+		if _, isInBatch := lastRecords[cursor.UID]; !isInBatch && !cursor.isPinned {
+			c.evictRecord(cursor)
+		}
+		cursor = upperRecord
+	}
+
+	// Additions.
+	for _, i := range keptRecords {
+		c.addRecord(records[i])
+	}
+	return
+}
+
+// Gets the Data of Records by their UIDs at once. Returns the Data and an
+// Error for each UID, as with the 'GetActualRecordDataByUID' Method. Found
+// Records are moved to the Top in the Order of UIDs, so the last found Record
+// becomes the Top.
+func (c *FixedSizeBubbleCache) GetMany(
+	uids []FixedSizeBubbleCacheRecordUID,
+) (data []interface{}, errs []error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	data = make([]interface{}, len(uids))
+	errs = make([]error, len(uids))
+	for i, uid := range uids {
+		data[i], errs[i] = c.getActualRecordDataByUID(uid)
+	}
+	return
+}
+
+// Deletes Records specified by their UIDs at once. Returns an Error for each
+// UID, as with the 'DeleteRecordByUID' Method.
+func (c *FixedSizeBubbleCache) DeleteMany(
+	uids []FixedSizeBubbleCacheRecordUID,
+) (errs []error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	errs = make([]error, len(uids))
+	for i, uid := range uids {
+		var record *FixedSizeBubbleCacheRecord
+		record, errs[i] = c.getRecordByUID(uid)
+		if errs[i] != nil {
+			continue
+		}
+		errs[i] = c.deleteRecord(record, true)
+	}
+	return
+}
@@ -0,0 +1,137 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/tester"
+)
+
+// Lists the UIDs of the Cache from the Top to the Bottom.
+func listCacheUIDs(
+	cache *FixedSizeBubbleCache,
+) (uids []FixedSizeBubbleCacheRecordUID) {
+	uids = []FixedSizeBubbleCacheRecordUID{}
+	for _, record := range cache.ListAllRecords() {
+		uids = append(uids, record.UID)
+	}
+	return
+}
+
+func Test_AddRecords(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(4, 60)
+	var evictedUIDs []FixedSizeBubbleCacheRecordUID
+	cache.SetEvictionHandler(func(uid FixedSizeBubbleCacheRecordUID, data interface{}, ttl uint, expirationTime time.Time) {
+		evictedUIDs = append(evictedUIDs, uid)
+	})
+
+	// Test #1. Records are added in their Order, bad Records are reported.
+	var errs = cache.AddRecords([]*FixedSizeBubbleCacheRecord{
+		{UID: "a", Data: 1},
+		nil,
+		{UID: "b", Data: 2},
+		{UID: "", Data: 3},
+		{UID: "c"},
+		{UID: "a", Data: 4},
+	})
+	aTest.MustBeEqual(len(errs), 6)
+	aTest.MustBeNoError(errs[0])
+	aTest.MustBeAnError(errs[1])
+	aTest.MustBeNoError(errs[2])
+	aTest.MustBeAnError(errs[3])
+	aTest.MustBeAnError(errs[4])
+	aTest.MustBeNoError(errs[5])
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"a", "b"})
+	var data, err = cache.GetActualRecordDataByUID("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, 4)
+	aTest.MustBeEqual(cache.isIntegral(), true)
+
+	// Test #2. Bottom Records out of the Batch are evicted once.
+	// [a,b] => [a,b,c,d] => [x,y,b,a].
+	errs = cache.AddRecords([]*FixedSizeBubbleCacheRecord{{UID: "c", Data: 3}, {UID: "d", Data: 4}})
+	aTest.MustBeEqual(errs, []error{nil, nil})
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"d", "c", "a", "b"})
+	errs = cache.AddRecords([]*FixedSizeBubbleCacheRecord{
+		{UID: "a", Data: 1},
+		{UID: "b", Data: 2},
+		{UID: "y", Data: 25},
+		{UID: "x", Data: 24},
+	})
+	aTest.MustBeEqual(errs, []error{nil, nil, nil, nil})
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"x", "y", "b", "a"})
+	aTest.MustBeEqual(evictedUIDs, []FixedSizeBubbleCacheRecordUID{"c", "d"})
+	aTest.MustBeEqual(cache.Stats().Evictions, uint64(2))
+	aTest.MustBeEqual(cache.isIntegral(), true)
+
+	// Test #3. Records which do not fit are reported.
+	errs = cache.AddRecords([]*FixedSizeBubbleCacheRecord{
+		{UID: "1", Data: 1},
+		{UID: "2", Data: 2},
+		{UID: "x", Data: 3},
+		{UID: "3", Data: 3},
+		{UID: "4", Data: 4},
+		{UID: "5", Data: 5},
+	})
+	aTest.MustBeAnError(errs[0])
+	aTest.MustBeAnError(errs[1])
+	aTest.MustBeNoError(errs[2])
+	aTest.MustBeNoError(errs[3])
+	aTest.MustBeNoError(errs[4])
+	aTest.MustBeNoError(errs[5])
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"5", "4", "3", "x"})
+	aTest.MustBeEqual(evictedUIDs, []FixedSizeBubbleCacheRecordUID{"c", "d", "a", "b", "y"})
+	aTest.MustBeEqual(cache.isIntegral(), true)
+
+	// Test #4. Empty Batch.
+	aTest.MustBeEqual(cache.AddRecords(nil), []error{})
+}
+
+func Test_GetMany(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(4, 60)
+	cache.SetNegativeRecordTTL(60)
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2}))
+	aTest.MustBeNoError(cache.AddNegativeRecord("n"))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "c", Data: 3}))
+
+	// Test #1. Found Records are moved to the Top in the Order of UIDs.
+	var data, errs = cache.GetMany([]FixedSizeBubbleCacheRecordUID{"b", "x", "n", "a"})
+	aTest.MustBeEqual(data, []interface{}{2, nil, nil, 1})
+	aTest.MustBeNoError(errs[0])
+	aTest.MustBeAnError(errs[1])
+	aTest.MustBeAnError(errs[2])
+	aTest.MustBeNoError(errs[3])
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"a", "n", "b", "c"})
+
+	var stats = cache.Stats()
+	aTest.MustBeEqual(stats.Hits, uint64(2))
+	aTest.MustBeEqual(stats.Misses, uint64(1))
+	aTest.MustBeEqual(stats.NegativeHits, uint64(1))
+}
+
+func Test_DeleteMany(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(4, 60)
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "c", Data: 3}))
+
+	// Test #1. Missing and repeated UIDs are reported.
+	var errs = cache.DeleteMany([]FixedSizeBubbleCacheRecordUID{"c", "x", "a", "c"})
+	aTest.MustBeNoError(errs[0])
+	aTest.MustBeAnError(errs[1])
+	aTest.MustBeNoError(errs[2])
+	aTest.MustBeAnError(errs[3])
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"b"})
+
+	// Test #2. The last Record.
+	errs = cache.DeleteMany([]FixedSizeBubbleCacheRecordUID{"b"})
+	aTest.MustBeEqual(errs, []error{nil})
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{})
+	aTest.MustBeEqual(cache.isIntegral(), true)
+}
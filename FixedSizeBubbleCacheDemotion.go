@@ -0,0 +1,106 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"errors"
+)
+
+// Checks the Record's Parameters and adds it to the Bottom of the Cache, so
+// that it is the first Record to be evicted. This suits prefetched or
+// speculative Data. If the Record with the same UID exists, its Contents are
+// updated and it is moved to the Bottom.
+func (c *FixedSizeBubbleCache) AddRecordAtBottom(
+	record *FixedSizeBubbleCacheRecord,
+) (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// Checks.
+	if record == nil {
+		return errors.New(ErrRecordIsNotSet)
+	}
+	err = record.Check()
+	if err != nil {
+		return
+	}
+
+	// Addition.
+	c.addRecordAtBottom(record)
+	return
+}
+
+// Adds a Record to the Bottom of the Cache. When the Cache is full, the lowest
+// unpinned Record is evicted to free the Space.
+func (c *FixedSizeBubbleCache) addRecordAtBottom(
+	addedRecord *FixedSizeBubbleCacheRecord,
+) {
+	var existingIndex, uidExists = c.recordsByUID.find(addedRecord.UID)
+	if uidExists {
+		var existingRecord = &c.records[existingIndex]
+		if existingRecord != c.bottom {
+			c.moveExistingRecordToBottom(existingRecord)
+		}
+		if existingRecord.isNegative != addedRecord.isNegative {
+			c.countNegativeRecord(existingRecord, -1)
+			c.countNegativeRecord(addedRecord, 1)
+		}
+		c.bottom.updateData(addedRecord.Data, c.now())
+		c.bottom.version = c.nextVersion()
+		c.bottom.isNegative = addedRecord.isNegative
+		c.bottom.ttl = addedRecord.ttl
+		c.bottom.expirationTime = addedRecord.expirationTime
+		c.journalRecordAddedAtBottom(c.bottom)
+		return
+	}
+	if c.size == c.capacity {
+		c.evictRecord(c.evictionCandidate())
+	}
+	var record = c.storeRecord(addedRecord)
+	c.linkBottomRecord(record)
+	c.countNegativeRecord(record, 1)
+
+	c.recordsByUID.insert(record.UID, record.index)
+	c.bottom.updateData(record.Data, c.now())
+	c.bottom.creationTime = c.bottom.lastAccessTime
+	c.size++ // We can not increase the Size prior to Linking.
+	c.journalRecordAddedAtBottom(c.bottom)
+}
+
+// Moves an existing Record to the Bottom of the Cache, so that it is the first
+// Record to be evicted. The LAT of the Record is not changed.
+func (c *FixedSizeBubbleCache) DemoteToBottom(
+	uid FixedSizeBubbleCacheRecordUID,
+) (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var record *FixedSizeBubbleCacheRecord
+	record, err = c.getRecordByUID(uid)
+	if err != nil {
+		return
+	}
+
+	if record != c.bottom {
+		c.moveExistingRecordToBottom(record)
+	}
+	c.journalRecordAddedAtBottom(c.bottom)
+	return
+}
+
+// Moves the existing Record to the Bottom.
+// The Record must be a non-Bottom Record.
+// This Method only manipulates the Links, so it does not check the Integrity
+// (you must do the Checks beforehand), it does not touch the fast-Access Map,
+// it does not touch the Size Counter.
+func (c *FixedSizeBubbleCache) moveExistingRecordToBottom(
+	existingRecord *FixedSizeBubbleCacheRecord,
+) {
+	// When the Record is not the Bottom, then the Cache Size is 2 or more.
+	if existingRecord == c.top {
+		c.unlinkTopRecord()
+	} else {
+		c.unlinkMiddleRecord(existingRecord)
+	}
+	c.linkBottomRecord(existingRecord)
+}
@@ -0,0 +1,104 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/tester"
+)
+
+func Test_AddRecordAtBottom(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(3, 60)
+	var evictedUIDs []FixedSizeBubbleCacheRecordUID
+	cache.SetEvictionHandler(func(uid FixedSizeBubbleCacheRecordUID, data interface{}, ttl uint, expirationTime time.Time) {
+		evictedUIDs = append(evictedUIDs, uid)
+	})
+
+	// Test #1. Empty Cache.
+	aTest.MustBeNoError(cache.AddRecordAtBottom(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1}))
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"a"})
+	aTest.MustBeEqual(cache.isIntegral(), true)
+
+	// Test #2. New Records go below the existing Records.
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2}))
+	aTest.MustBeNoError(cache.AddRecordAtBottom(&FixedSizeBubbleCacheRecord{UID: "p", Data: 3}))
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"b", "a", "p"})
+	aTest.MustBeEqual(cache.isIntegral(), true)
+
+	// Test #3. Full Cache evicts the Bottom Record.
+	aTest.MustBeNoError(cache.AddRecordAtBottom(&FixedSizeBubbleCacheRecord{UID: "q", Data: 4}))
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"b", "a", "q"})
+	aTest.MustBeEqual(evictedUIDs, []FixedSizeBubbleCacheRecordUID{"p"})
+	aTest.MustBeEqual(cache.isIntegral(), true)
+
+	// Test #4. Existing Record is updated and is moved to the Bottom.
+	aTest.MustBeNoError(cache.AddRecordAtBottom(&FixedSizeBubbleCacheRecord{UID: "b", Data: 22}))
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"a", "q", "b"})
+	aTest.MustBeEqual(cache.ListAllRecordValues(), []interface{}{1, 4, 22})
+	aTest.MustBeEqual(cache.isIntegral(), true)
+
+	// Test #5. The next Addition evicts the Record added at the Bottom.
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "c", Data: 5}))
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"c", "a", "q"})
+
+	// Test #6. Bad Records.
+	aTest.MustBeAnError(cache.AddRecordAtBottom(nil))
+	aTest.MustBeAnError(cache.AddRecordAtBottom(&FixedSizeBubbleCacheRecord{UID: "d"}))
+}
+
+func Test_DemoteToBottom(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(3, 60)
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1}))
+
+	// Test #1. Single Record.
+	aTest.MustBeNoError(cache.DemoteToBottom("a"))
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"a"})
+
+	// Test #2. Top and middle Records.
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "c", Data: 3}))
+	var recordBefore, _ = cache.GetRecordInfo("c")
+	aTest.MustBeNoError(cache.DemoteToBottom("c"))
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"b", "a", "c"})
+	aTest.MustBeNoError(cache.DemoteToBottom("a"))
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"b", "c", "a"})
+	aTest.MustBeEqual(cache.isIntegral(), true)
+
+	// Test #3. The LAT and the Version are kept.
+	var recordAfter, _ = cache.GetRecordInfo("c")
+	aTest.MustBeEqual(recordAfter.LastAccessTime, recordBefore.LastAccessTime)
+	aTest.MustBeEqual(recordAfter.Version, recordBefore.Version)
+
+	// Test #4. Missing Record.
+	aTest.MustBeAnError(cache.DemoteToBottom("x"))
+}
+
+func Test_WriteAheadLog_AddRecordAtBottom(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var directory = t.TempDir()
+
+	// Test #1. Additions to the Bottom and Demotions are replayed.
+	var cache = NewFixedSizeBubbleCache(4, 60)
+	var wal, err = OpenWriteAheadLog(cache, directory, walTestSettings)
+	aTest.MustBeNoError(err)
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2}))
+	aTest.MustBeNoError(cache.AddRecordAtBottom(&FixedSizeBubbleCacheRecord{UID: "p", Data: 3}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "c", Data: 4}))
+	aTest.MustBeNoError(cache.DemoteToBottom("b"))
+	aTest.MustBeNoError(wal.Close())
+	var expectedUIDs = []FixedSizeBubbleCacheRecordUID{"c", "a", "p", "b"}
+	aTest.MustBeEqual(listCacheUIDs(cache), expectedUIDs)
+
+	cache = NewFixedSizeBubbleCache(4, 60)
+	wal, err = OpenWriteAheadLog(cache, directory, walTestSettings)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(listCacheUIDs(cache), expectedUIDs)
+	aTest.MustBeEqual(cache.ListAllRecordValues(), []interface{}{4, 1, 3, 2})
+	aTest.MustBeEqual(cache.isIntegral(), true)
+	aTest.MustBeNoError(wal.Close())
+}
@@ -0,0 +1,80 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Default Value of the 'Beta' Parameter of the early Expiration.
+const EarlyExpirationBetaDefault = 1.0
+
+// Settings of the probabilistic early Expiration, also known as 'XFetch'.
+//
+// Each Read of a Record through the Loader Path decides whether the Record's
+// Data should be reloaded before the Record becomes outdated. The Probability
+// of the early Reload grows when the Data comes closer to its Expiration
+// and when the last Load of the Record was expensive. The Life of the Data
+// is counted from its last Load, not from the last Access, so that Requests
+// of a hot Record do not postpone its Reload. This makes a single
+// Caller refresh a hot Record before it expires, while all other Callers
+// continue to use the existing Data, so that the Source of the Data does not
+// receive a Stampede of simultaneous Requests.
+type FixedSizeBubbleCacheEarlyExpiration struct {
+
+	// The Beta Parameter scales the Eagerness of early Reloads.
+	// Values greater than One favour earlier Reloads, values less than One
+	// favour later Reloads.
+	Beta float64
+
+	// A Source of random Numbers in the (0; 1] Range.
+	Random func() float64
+
+	// A Source of the current Time. The Cache also uses it for the Expiration
+	// of Records.
+	Clock func() time.Time
+}
+
+// Creates new Settings of the early Expiration with the default Sources of
+// random Numbers and Time.
+func NewEarlyExpiration(
+	beta float64,
+) (ee *FixedSizeBubbleCacheEarlyExpiration) {
+	if beta <= 0 {
+		beta = EarlyExpirationBetaDefault
+	}
+	ee = &FixedSizeBubbleCacheEarlyExpiration{
+		Beta:   beta,
+		Random: randomPositiveFloat64,
+		Clock:  time.Now,
+	}
+	return
+}
+
+// Returns a random Number in the (0; 1] Range.
+func randomPositiveFloat64() float64 {
+	return 1 - rand.Float64()
+}
+
+// Decides whether the Record's Data must be reloaded before its Expiration.
+//
+// The Decision follows the 'XFetch' Rule:
+//
+//	Now - Delta * Beta * ln(Random) >= Expiration Time,
+//
+// where Delta is the Duration of the last Load of the Record's Data and the
+// Expiration Time is the Time of the last Load plus the TTL.
+func (ee *FixedSizeBubbleCacheEarlyExpiration) isReloadRequired(
+	record *FixedSizeBubbleCacheRecord,
+	ttl uint,
+) bool {
+	if record.loadDuration <= 0 {
+		return false
+	}
+	var expirationTime = time.Unix(int64(record.updateTime+ttl), 0)
+	var remainingLife = expirationTime.Sub(ee.Clock())
+	var gap = -float64(record.loadDuration) * ee.Beta * math.Log(ee.Random())
+	return gap >= float64(remainingLife)
+}
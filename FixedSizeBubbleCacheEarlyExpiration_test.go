@@ -0,0 +1,76 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/tester"
+)
+
+func Test_NewEarlyExpiration(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var ee *FixedSizeBubbleCacheEarlyExpiration
+
+	// Test #1. Normal Beta.
+	ee = NewEarlyExpiration(2)
+	aTest.MustBeEqual(ee.Beta, 2.0)
+	aTest.MustBeEqual(ee.Random != nil, true)
+	aTest.MustBeEqual(ee.Clock != nil, true)
+
+	// Test #2. Bad Beta.
+	ee = NewEarlyExpiration(0)
+	aTest.MustBeEqual(ee.Beta, EarlyExpirationBetaDefault)
+}
+
+func Test_randomPositiveFloat64(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+
+	// Test #1.
+	for i := 0; i < 1000; i++ {
+		var x = randomPositiveFloat64()
+		aTest.MustBeEqual(x > 0, true)
+		aTest.MustBeEqual(x <= 1, true)
+	}
+}
+
+func Test_isReloadRequired(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var tsNow = time.Unix(1000, 0)
+	var random float64
+	var ee = &FixedSizeBubbleCacheEarlyExpiration{
+		Beta:   1,
+		Random: func() float64 { return random },
+		Clock:  func() time.Time { return tsNow },
+	}
+	var record = &FixedSizeBubbleCacheRecord{
+		lastAccessTime: 950,
+		updateTime:     950,
+		loadDuration:   time.Second * 5,
+	}
+
+	// Test #1. Far from Expiration (10 Seconds left), ln(0.5) * 5s ~ 3.5s.
+	random = 0.5
+	aTest.MustBeEqual(ee.isReloadRequired(record, 60), false)
+
+	// Test #2. Close to Expiration (2 Seconds left).
+	aTest.MustBeEqual(ee.isReloadRequired(record, 52), true)
+
+	// Test #3. Random Number close to One never reloads early.
+	random = 1
+	aTest.MustBeEqual(ee.isReloadRequired(record, 52), false)
+
+	// Test #4. Random Number close to Zero reloads early.
+	random = 1e-9
+	aTest.MustBeEqual(ee.isReloadRequired(record, 60), true)
+
+	// Test #5. Requests of the Record do not postpone the Reload.
+	random = 0.5
+	record.lastAccessTime = 1000
+	aTest.MustBeEqual(ee.isReloadRequired(record, 52), true)
+
+	// Test #6. Unknown Load Duration.
+	record.loadDuration = 0
+	aTest.MustBeEqual(ee.isReloadRequired(record, 51), false)
+}
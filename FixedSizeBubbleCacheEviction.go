@@ -0,0 +1,61 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"time"
+)
+
+// A Function which receives a Record evicted from the Bottom of the full
+// Cache, or a Record removed by the 'PopBottom', 'PopTop' and 'Drain' Methods,
+// so that it may be observed as an Eviction. The TTL is the individual TTL of
+// the Record, zero TTL means that the Cache's Record TTL has been used. The
+// Expiration Time is the absolute Expiration Time of the Record, zero Time
+// means that the Record expires by its TTL.
+//
+// The Handler is called while the Cache is locked, so it must not call any
+// locking Methods of the Cache. Negative and outdated Records are not passed
+// to the Handler.
+type FixedSizeBubbleCacheEvictionHandler func(
+	uid FixedSizeBubbleCacheRecordUID,
+	data interface{},
+	ttl uint,
+	expirationTime time.Time,
+)
+
+// Sets the Eviction Handler of the Cache. Null Handler disables it.
+func (c *FixedSizeBubbleCache) SetEvictionHandler(
+	handler FixedSizeBubbleCacheEvictionHandler,
+) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.evictionHandler = handler
+}
+
+// Passes the evicted Record to the Eviction Handler.
+func (c *FixedSizeBubbleCache) notifyEviction(
+	record *FixedSizeBubbleCacheRecord,
+) {
+	if c.evictionHandler == nil {
+		return
+	}
+	if record.isNegative || !c.isRecordActual(record) {
+		return
+	}
+	var expirationTime time.Time
+	if record.expirationTime > 0 {
+		expirationTime = time.Unix(int64(record.expirationTime), 0)
+	}
+	c.evictionHandler(record.UID, record.Data, record.ttl, expirationTime)
+}
+
+// Evicts the Record from the Cache.
+func (c *FixedSizeBubbleCache) evictRecord(
+	record *FixedSizeBubbleCacheRecord,
+) {
+	c.notifyEviction(record)
+	// The Deletion also handles a single-Record Cache.
+	_ = c.deleteRecord(record, true)
+	c.statistics.Evictions++
+}
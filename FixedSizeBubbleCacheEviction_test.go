@@ -0,0 +1,61 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/tester"
+)
+
+func Test_SetEvictionHandler(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(2, 60)
+	cache.SetNegativeRecordTTL(60)
+	var evictedUIDs []FixedSizeBubbleCacheRecordUID
+	var evictedTTLs []uint
+	cache.SetEvictionHandler(func(uid FixedSizeBubbleCacheRecordUID, data interface{}, ttl uint, expirationTime time.Time) {
+		evictedUIDs = append(evictedUIDs, uid)
+		evictedTTLs = append(evictedTTLs, ttl)
+	})
+
+	// Test #1. Bottom Records are passed to the Handler.
+	aTest.MustBeNoError(cache.AddRecordWithTTL(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1}, 30))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "c", Data: 3}))
+	aTest.MustBeEqual(evictedUIDs, []FixedSizeBubbleCacheRecordUID{"a"})
+	aTest.MustBeEqual(evictedTTLs, []uint{30})
+
+	// Test #2. Deletions are not Evictions.
+	aTest.MustBeNoError(cache.DeleteRecordByUID("c"))
+	aTest.MustBeNoError(cache.Clear())
+	aTest.MustBeEqual(len(evictedUIDs), 1)
+
+	// Test #3. Negative and outdated Records are not passed to the Handler.
+	aTest.MustBeNoError(cache.AddNegativeRecord("n"))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "o", Data: 1}))
+	cache.top.lastAccessTime = 0
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "d", Data: 4}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "e", Data: 5}))
+	aTest.MustBeEqual(len(evictedUIDs), 1)
+	aTest.MustBeEqual(cache.Stats().Evictions, uint64(3))
+
+	// Test #4. Null Handler.
+	cache.SetEvictionHandler(nil)
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "f", Data: 6}))
+	aTest.MustBeEqual(len(evictedUIDs), 1)
+
+	// Test #5. Absolute Expiration Times are passed to the Handler.
+	var evictedExpirationTimes []time.Time
+	cache.SetEvictionHandler(func(uid FixedSizeBubbleCacheRecordUID, data interface{}, ttl uint, expirationTime time.Time) {
+		evictedExpirationTimes = append(evictedExpirationTimes, expirationTime)
+	})
+	var expirationTime = time.Unix(time.Now().Unix()+30, 0)
+	var record = &FixedSizeBubbleCacheRecord{UID: "g", Data: 7}
+	record.SetExpirationTime(expirationTime)
+	aTest.MustBeNoError(cache.AddRecord(record))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "h", Data: 8}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "i", Data: 9}))
+	aTest.MustBeEqual(evictedExpirationTimes, []time.Time{{}, {}, expirationTime})
+}
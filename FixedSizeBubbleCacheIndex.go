@@ -0,0 +1,187 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+// A Function which calculates the Hash of a Record's UID for the Index of the
+// Cache. Equal UIDs must have equal Hashes.
+type FixedSizeBubbleCacheHashFunction func(
+	uid FixedSizeBubbleCacheRecordUID,
+) uint64
+
+// Returns the 64-Bit FNV-1a Hash of the UID. It is the default Hash Function
+// of the Cache's Index.
+func FNV1aHash(
+	uid FixedSizeBubbleCacheRecordUID,
+) (hash uint64) {
+	hash = 14695981039346656037
+	for i := 0; i < len(uid); i++ {
+		hash ^= uint64(uid[i])
+		hash *= 1099511628211
+	}
+	return
+}
+
+// Sets the Hash Function of the Cache's Index. Null Function selects the
+// default FNV-1a Hash. The Index is rebuilt with the new Function.
+func (c *FixedSizeBubbleCache) SetHashFunction(
+	hashFunction FixedSizeBubbleCacheHashFunction,
+) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.recordsByUID = newRecordIndex(c.records, hashFunction)
+	for record := c.top; record != nil; record = c.lowerOf(record) {
+		c.recordsByUID.insert(record.UID, record.index)
+	}
+}
+
+// An Index of the Records of the Cache, which maps UIDs to Indices of the
+// Storage.
+//
+// It is a Hash Table with open Addressing and linear Probing. The Table has at
+// least twice as many Slots as the Storage has Records, so it is never filled
+// more than by a Half and never grows. Keys are not stored in the Table, they
+// are the UIDs of the Records in the Storage. Deleted Slots are filled by
+// shifting the following Slots backwards, so the Table has no Tombstones.
+type recordIndex struct {
+	records      []FixedSizeBubbleCacheRecord
+	hashFunction FixedSizeBubbleCacheHashFunction
+	slots        []recordIndexSlot
+	mask         uint64
+	count        uint
+}
+
+// A Slot of the Index. An empty Slot refers to 'noRecord'.
+type recordIndexSlot struct {
+	hash   uint64
+	record int32
+}
+
+// Creates an empty Index of the Storage of Records.
+func newRecordIndex(
+	records []FixedSizeBubbleCacheRecord,
+	hashFunction FixedSizeBubbleCacheHashFunction,
+) (index recordIndex) {
+	if hashFunction == nil {
+		hashFunction = FNV1aHash
+	}
+
+	var slotsCount = 2
+	for slotsCount < 2*len(records) {
+		slotsCount *= 2
+	}
+
+	index = recordIndex{
+		records:      records,
+		hashFunction: hashFunction,
+		slots:        make([]recordIndexSlot, slotsCount),
+		mask:         uint64(slotsCount - 1),
+	}
+	for i := range index.slots {
+		index.slots[i].record = noRecord
+	}
+	return
+}
+
+// Returns the Number of Slot where the UID is stored, or the Number of the
+// empty Slot where it must be stored.
+func (x *recordIndex) findSlot(
+	uid FixedSizeBubbleCacheRecordUID,
+	hash uint64,
+) (slot uint64, uidIsFound bool) {
+	slot = hash & x.mask
+	for {
+		var s = &x.slots[slot]
+		if s.record == noRecord {
+			return slot, false
+		}
+		if (s.hash == hash) && (x.records[s.record].UID == uid) {
+			return slot, true
+		}
+		slot = (slot + 1) & x.mask
+	}
+}
+
+// Returns the Index of the Record with the UID in the Storage.
+func (x *recordIndex) find(
+	uid FixedSizeBubbleCacheRecordUID,
+) (record int32, uidIsFound bool) {
+	var slot uint64
+	slot, uidIsFound = x.findSlot(uid, x.hashFunction(uid))
+	if !uidIsFound {
+		return noRecord, false
+	}
+	return x.slots[slot].record, true
+}
+
+// Stores the Index of the Record with the UID in the Storage. An existing
+// Index of the UID is replaced.
+func (x *recordIndex) insert(
+	uid FixedSizeBubbleCacheRecordUID,
+	record int32,
+) {
+	var hash = x.hashFunction(uid)
+	var slot, uidIsFound = x.findSlot(uid, hash)
+	if !uidIsFound {
+		x.count++
+	}
+	x.slots[slot] = recordIndexSlot{
+		hash:   hash,
+		record: record,
+	}
+}
+
+// Removes the UID from the Index. Returns 'false' if the UID is not found.
+func (x *recordIndex) remove(
+	uid FixedSizeBubbleCacheRecordUID,
+) (uidIsFound bool) {
+	var slot uint64
+	slot, uidIsFound = x.findSlot(uid, x.hashFunction(uid))
+	if !uidIsFound {
+		return
+	}
+
+	// Following Slots which can not be found from their Home Slot after the
+	// Removal are shifted backwards.
+	var next = (slot + 1) & x.mask
+	for x.slots[next].record != noRecord {
+		var home = x.slots[next].hash & x.mask
+		if ((next - home) & x.mask) >= ((next - slot) & x.mask) {
+			x.slots[slot] = x.slots[next]
+			slot = next
+		}
+		next = (next + 1) & x.mask
+	}
+	x.slots[slot] = recordIndexSlot{record: noRecord}
+	x.count--
+	return
+}
+
+// Returns the Count of UIDs in the Index.
+func (x *recordIndex) len() uint {
+	return x.count
+}
+
+// Checks the Integrity of the Index: every Slot refers to an existing Record
+// of the Storage, which can be found by its UID.
+func (x *recordIndex) isIntegral() bool {
+	var count uint
+	for _, s := range x.slots {
+		if s.record == noRecord {
+			continue
+		}
+		if (s.record < 0) || (int(s.record) >= len(x.records)) {
+			return false
+		}
+		var uid = x.records[s.record].UID
+		if s.hash != x.hashFunction(uid) {
+			return false
+		}
+		var record, uidIsFound = x.find(uid)
+		if !uidIsFound || (record != s.record) {
+			return false
+		}
+		count++
+	}
+	return count == x.count
+}
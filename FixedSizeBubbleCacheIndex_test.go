@@ -0,0 +1,138 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/vault-thirteen/tester"
+)
+
+func Test_FNV1aHash(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+
+	// Test #1. Reference Values.
+	aTest.MustBeEqual(FNV1aHash(""), uint64(0xcbf29ce484222325))
+	aTest.MustBeEqual(FNV1aHash("a"), uint64(0xaf63dc4c8601ec8c))
+	aTest.MustBeEqual(FNV1aHash("foobar"), uint64(0x85944171f73967e8))
+}
+
+func Test_recordIndex(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var records = make([]FixedSizeBubbleCacheRecord, 8)
+	for i := range records {
+		records[i].UID = fmt.Sprintf("%v", i)
+	}
+
+	// All UIDs collide, so that the Probing and the backward Shift are used.
+	var index = newRecordIndex(records, func(uid FixedSizeBubbleCacheRecordUID) uint64 {
+		return 3
+	})
+	aTest.MustBeEqual(len(index.slots), 16)
+
+	// Test #1. Insertion.
+	for i := range records {
+		index.insert(records[i].UID, int32(i))
+	}
+	aTest.MustBeEqual(index.len(), uint(8))
+	aTest.MustBeEqual(index.isIntegral(), true)
+	for i := range records {
+		var record, uidIsFound = index.find(records[i].UID)
+		aTest.MustBeEqual(uidIsFound, true)
+		aTest.MustBeEqual(record, int32(i))
+	}
+	var record, uidIsFound = index.find("x")
+	aTest.MustBeEqual(uidIsFound, false)
+	aTest.MustBeEqual(record, noRecord)
+
+	// Test #2. Removal from the Middle of the Chain.
+	aTest.MustBeEqual(index.remove("2"), true)
+	aTest.MustBeEqual(index.remove("2"), false)
+	aTest.MustBeEqual(index.len(), uint(7))
+	aTest.MustBeEqual(index.isIntegral(), true)
+	for i := range records {
+		_, uidIsFound = index.find(records[i].UID)
+		aTest.MustBeEqual(uidIsFound, i != 2)
+	}
+
+	// Test #3. Replacement.
+	index.insert("5", 5)
+	aTest.MustBeEqual(index.len(), uint(7))
+	record, _ = index.find("5")
+	aTest.MustBeEqual(record, int32(5))
+	aTest.MustBeEqual(index.isIntegral(), true)
+
+	// Test #4. Removal of all UIDs.
+	for i := range records {
+		index.remove(records[i].UID)
+	}
+	aTest.MustBeEqual(index.len(), uint(0))
+	aTest.MustBeEqual(index.isIntegral(), true)
+	for _, s := range index.slots {
+		aTest.MustBeEqual(s.record, noRecord)
+	}
+}
+
+func Test_recordIndex_Wrapping(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var records = []FixedSizeBubbleCacheRecord{{UID: "a"}, {UID: "b"}, {UID: "c"}}
+
+	// Home Slots are at the End of the Table, so the Probing wraps around.
+	var index = newRecordIndex(records, func(uid FixedSizeBubbleCacheRecordUID) uint64 {
+		if uid == "c" {
+			return 0
+		}
+		return 7
+	})
+	index.insert("a", 0)
+	index.insert("b", 1)
+	index.insert("c", 2)
+
+	// Test #1. Removal before the wrapped Slots.
+	aTest.MustBeEqual(index.remove("a"), true)
+	aTest.MustBeEqual(index.isIntegral(), true)
+	var record, uidIsFound = index.find("b")
+	aTest.MustBeEqual(uidIsFound, true)
+	aTest.MustBeEqual(record, int32(1))
+	aTest.MustBeEqual(index.slots[7].record, int32(1))
+	record, uidIsFound = index.find("c")
+	aTest.MustBeEqual(uidIsFound, true)
+	aTest.MustBeEqual(record, int32(2))
+	aTest.MustBeEqual(index.slots[0].record, int32(2))
+}
+
+func Test_SetHashFunction(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(4, 60)
+	for _, uid := range []FixedSizeBubbleCacheRecordUID{"a", "b", "c"} {
+		aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: uid, Data: uid}))
+	}
+
+	// Test #1. The Index is rebuilt with the new Function.
+	var calls int
+	cache.SetHashFunction(func(uid FixedSizeBubbleCacheRecordUID) uint64 {
+		calls++
+		return uint64(len(uid))
+	})
+	aTest.MustBeEqual(calls, 3)
+	aTest.MustBeEqual(cache.isIntegral(), true)
+	var data, err = cache.GetActualRecordDataByUID("b")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "b")
+
+	// Test #2. Evictions and Deletions keep the Index integral.
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "d", Data: "d"}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "e", Data: "e"}))
+	aTest.MustBeEqual(cache.RecordUIDExists("a"), false)
+	aTest.MustBeNoError(cache.DeleteRecordByUID("c"))
+	aTest.MustBeEqual(cache.isIntegral(), true)
+	aTest.MustBeEqual(cache.RecordUIDExists("b"), true)
+	aTest.MustBeEqual(cache.RecordUIDExists("d"), true)
+	aTest.MustBeEqual(cache.RecordUIDExists("e"), true)
+
+	// Test #3. Null Function selects the default Hash.
+	cache.SetHashFunction(nil)
+	aTest.MustBeEqual(cache.isIntegral(), true)
+	aTest.MustBeEqual(cache.RecordUIDExists("d"), true)
+}
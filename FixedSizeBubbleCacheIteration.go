@@ -0,0 +1,84 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+// A Function which receives a Record during the Iteration over the Cache.
+// Returning 'false' stops the Iteration.
+//
+// The Function is called while the Cache is locked, so it must not call any
+// locking Methods of the Cache.
+type FixedSizeBubbleCacheVisitor func(
+	uid FixedSizeBubbleCacheRecordUID,
+	data interface{},
+) (proceed bool)
+
+// A Sequence of the Records' UIDs and Data. The Sequence has the Shape of the
+// 'iter.Seq2' Type, so in newer Versions of Go it may be used in a 'for'
+// Loop with 'range'. The Yield Function follows the Rules of the Visitor.
+type FixedSizeBubbleCacheSequence func(
+	yield func(uid FixedSizeBubbleCacheRecordUID, data interface{}) bool,
+)
+
+// Settings of the Iteration over the Cache. Zero Values select the default
+// Settings.
+type IterationSettings struct {
+
+	// Direction of the Iteration. By Default, Records are visited from the
+	// Top to the Bottom.
+	FromBottom bool
+
+	// Outdated Records are skipped. By Default, all Records are visited.
+	SkipOutdated bool
+}
+
+// Calls the Visitor for the Records of the Cache, until the Visitor returns
+// 'false'. Negative Records have no Data and are not visited. The Records
+// are neither moved nor refreshed.
+//
+// The Cache is locked during the whole Iteration, so the Visitor sees a
+// consistent State of the Cache and no Memory is allocated for the Records.
+func (c *FixedSizeBubbleCache) ForEachRecord(
+	settings IterationSettings,
+	visitor FixedSizeBubbleCacheVisitor,
+) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.forEachRecord(settings, visitor)
+}
+
+// Returns a Sequence of the Records of the Cache. Each Pass over the Sequence
+// iterates the Cache as the 'ForEachRecord' Method does.
+func (c *FixedSizeBubbleCache) Records(
+	settings IterationSettings,
+) FixedSizeBubbleCacheSequence {
+	return func(yield func(uid FixedSizeBubbleCacheRecordUID, data interface{}) bool) {
+		c.ForEachRecord(settings, yield)
+	}
+}
+
+// Calls the Visitor for the Records of the Cache.
+func (c *FixedSizeBubbleCache) forEachRecord(
+	settings IterationSettings,
+	visitor FixedSizeBubbleCacheVisitor,
+) {
+	var record = c.top
+	var next = c.lowerOf
+	if settings.FromBottom {
+		record = c.bottom
+		next = c.upperOf
+	}
+
+	var now = c.now()
+	for ; record != nil; record = next(record) {
+		if record.isNegative {
+			continue
+		}
+		if settings.SkipOutdated && (now >= c.expirationTimeOfRecord(record)) {
+			continue
+		}
+		if !visitor(record.UID, record.Data) {
+			return
+		}
+	}
+}
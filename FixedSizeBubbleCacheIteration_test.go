@@ -0,0 +1,106 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"testing"
+
+	"github.com/vault-thirteen/tester"
+)
+
+func Test_ForEachRecord(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(5, 60)
+	cache.SetNegativeRecordTTL(60)
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2}))
+	aTest.MustBeNoError(cache.AddNegativeRecord("n"))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "c", Data: 3}))
+	var outdatedRecord, _ = cache.getRecordByUID("b")
+	outdatedRecord.lastAccessTime = 0
+
+	var uids []FixedSizeBubbleCacheRecordUID
+	var values []interface{}
+	var visitor = func(uid FixedSizeBubbleCacheRecordUID, data interface{}) bool {
+		uids = append(uids, uid)
+		values = append(values, data)
+		return true
+	}
+
+	// Test #1. From the Top to the Bottom.
+	cache.ForEachRecord(IterationSettings{}, visitor)
+	aTest.MustBeEqual(uids, []FixedSizeBubbleCacheRecordUID{"c", "b", "a"})
+	aTest.MustBeEqual(values, []interface{}{3, 2, 1})
+
+	// Test #2. From the Bottom to the Top without outdated Records.
+	uids, values = nil, nil
+	cache.ForEachRecord(IterationSettings{FromBottom: true, SkipOutdated: true}, visitor)
+	aTest.MustBeEqual(uids, []FixedSizeBubbleCacheRecordUID{"a", "c"})
+	aTest.MustBeEqual(values, []interface{}{1, 3})
+
+	// Test #3. Early Stop.
+	uids = nil
+	cache.ForEachRecord(IterationSettings{}, func(uid FixedSizeBubbleCacheRecordUID, data interface{}) bool {
+		uids = append(uids, uid)
+		return uid != "b"
+	})
+	aTest.MustBeEqual(uids, []FixedSizeBubbleCacheRecordUID{"c", "b"})
+
+	// Test #4. Records are neither moved nor deleted.
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"c", "n", "b", "a"})
+
+	// Test #5. Empty Cache.
+	uids = nil
+	aTest.MustBeNoError(cache.Clear())
+	cache.ForEachRecord(IterationSettings{FromBottom: true}, visitor)
+	aTest.MustBeEqual(len(uids), 0)
+}
+
+func Test_Records(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(3, 60)
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "c", Data: 3}))
+
+	// Test #1. Each Pass iterates the current Records.
+	var sequence = cache.Records(IterationSettings{FromBottom: true})
+	var sum int
+	sequence(func(uid FixedSizeBubbleCacheRecordUID, data interface{}) bool {
+		sum += data.(int)
+		return true
+	})
+	aTest.MustBeEqual(sum, 6)
+	aTest.MustBeNoError(cache.DeleteRecordByUID("b"))
+	var uids []FixedSizeBubbleCacheRecordUID
+	sequence(func(uid FixedSizeBubbleCacheRecordUID, data interface{}) bool {
+		uids = append(uids, uid)
+		return true
+	})
+	aTest.MustBeEqual(uids, []FixedSizeBubbleCacheRecordUID{"a", "c"})
+
+	// Test #2. Early Stop.
+	uids = nil
+	sequence(func(uid FixedSizeBubbleCacheRecordUID, data interface{}) bool {
+		uids = append(uids, uid)
+		return false
+	})
+	aTest.MustBeEqual(uids, []FixedSizeBubbleCacheRecordUID{"a"})
+}
+
+func Benchmark_ForEachRecord(b *testing.B) {
+	var cache = NewFixedSizeBubbleCache(benchmarkCapacity, 3600)
+	for _, uid := range makeBenchmarkUIDs(benchmarkCapacity) {
+		_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: uid, Data: 1})
+	}
+	var sum int
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.ForEachRecord(IterationSettings{SkipOutdated: true}, func(uid FixedSizeBubbleCacheRecordUID, data interface{}) bool {
+			sum += data.(int)
+			return true
+		})
+	}
+}
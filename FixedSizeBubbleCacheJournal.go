@@ -0,0 +1,81 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+// A Journal receives all the Changes of the Cache.
+//
+// Its Methods are called while the Cache is locked, so they must not call any
+// locking Methods of the Cache.
+type cacheJournal interface {
+
+	// A Record has been added to the Top or has been updated at the Top.
+	recordAdded(record *FixedSizeBubbleCacheRecord)
+
+	// An existing Record has been requested and has been moved to the Top.
+	recordTouched(record *FixedSizeBubbleCacheRecord)
+
+	// A Record has been added to the Bottom, or an existing Record has been
+	// updated or has been moved to the Bottom.
+	recordAddedAtBottom(record *FixedSizeBubbleCacheRecord)
+
+	// A Record is being deleted from the Cache.
+	recordDeleted(record *FixedSizeBubbleCacheRecord)
+
+	// A Record has been pinned or unpinned.
+	recordPinChanged(record *FixedSizeBubbleCacheRecord)
+}
+
+// Sets the Journal of the Cache. Null Journal disables Journaling.
+func (c *FixedSizeBubbleCache) setJournal(
+	journal cacheJournal,
+) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.journal = journal
+}
+
+// Passes the Addition of a Record to the Journal.
+func (c *FixedSizeBubbleCache) journalRecordAdded(
+	record *FixedSizeBubbleCacheRecord,
+) {
+	if c.journal != nil {
+		c.journal.recordAdded(record)
+	}
+}
+
+// Passes the Request of a Record to the Journal.
+func (c *FixedSizeBubbleCache) journalRecordTouched(
+	record *FixedSizeBubbleCacheRecord,
+) {
+	if c.journal != nil {
+		c.journal.recordTouched(record)
+	}
+}
+
+// Passes the Addition of a Record to the Bottom to the Journal.
+func (c *FixedSizeBubbleCache) journalRecordAddedAtBottom(
+	record *FixedSizeBubbleCacheRecord,
+) {
+	if c.journal != nil {
+		c.journal.recordAddedAtBottom(record)
+	}
+}
+
+// Passes the Deletion of a Record to the Journal.
+func (c *FixedSizeBubbleCache) journalRecordDeleted(
+	record *FixedSizeBubbleCacheRecord,
+) {
+	if c.journal != nil {
+		c.journal.recordDeleted(record)
+	}
+}
+
+// Passes the Pinning or the Unpinning of a Record to the Journal.
+func (c *FixedSizeBubbleCache) journalRecordPinChanged(
+	record *FixedSizeBubbleCacheRecord,
+) {
+	if c.journal != nil {
+		c.journal.recordPinChanged(record)
+	}
+}
@@ -0,0 +1,178 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// A Function which loads the Data of a Record from an external Source, when
+// the Record is either missing in the Cache or is outdated.
+//
+// If the Data does not exist in the Source, the Loader must return null Data
+// without an Error. When negative Caching is enabled, such a Result is stored
+// in the Cache as a negative Record.
+type FixedSizeBubbleCacheLoader func(
+	uid FixedSizeBubbleCacheRecordUID,
+) (data interface{}, err error)
+
+// Sets the Loader of the Cache.
+func (c *FixedSizeBubbleCache) SetLoader(
+	loader FixedSizeBubbleCacheLoader,
+) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.loader = loader
+}
+
+// Sets the Settings of the probabilistic early Expiration.
+// Null Settings disable the early Expiration.
+func (c *FixedSizeBubbleCache) SetEarlyExpiration(
+	ee *FixedSizeBubbleCacheEarlyExpiration,
+) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.earlyExpiration = ee
+}
+
+// Gets the Record's Data by its UID. If the Record is missing or is outdated,
+// its Data is loaded by the Loader and is stored in the Cache. If the Record
+// is negative, returns an Error without loading.
+//
+// When the early Expiration is enabled, an actual Record may also be reloaded
+// before its Expiration. Only one Caller reloads the Record at a Time, other
+// Callers receive the existing Data.
+func (c *FixedSizeBubbleCache) GetOrLoadRecordDataByUID(
+	uid FixedSizeBubbleCacheRecordUID,
+) (data interface{}, err error) {
+	c.lock.Lock()
+
+	if c.loader == nil {
+		c.lock.Unlock()
+		err = errors.New(ErrLoaderIsNotSet)
+		return
+	}
+	var loader = c.loader
+	var clock = c.clock()
+
+	var record *FixedSizeBubbleCacheRecord
+	record, err = c.getRecordByUID(uid)
+	if err == nil {
+		if c.isRecordActual(record) {
+			var reloadIsRequired = (c.earlyExpiration != nil) &&
+				(!record.isBeingRefreshed) &&
+				c.earlyExpiration.isReloadRequired(record, c.getTTLOfRecord(record))
+
+			if record != c.top {
+				c.moveExistingRecordToTop(record)
+			}
+			c.top.lastAccessTime = c.now()
+			c.top.accessCount++
+			c.journalRecordTouched(c.top)
+
+			if record.isNegative {
+				c.statistics.NegativeHits++
+				c.lock.Unlock()
+				err = fmt.Errorf(ErrfRecordWithUidIsNegative, uid)
+				return
+			}
+			c.statistics.Hits++
+			data = record.Data
+
+			if !reloadIsRequired {
+				c.lock.Unlock()
+				return
+			}
+
+			// Early Reload.
+			record.isBeingRefreshed = true
+			c.lock.Unlock()
+
+			var freshData interface{}
+			var loadErr error
+			freshData, loadErr = c.load(loader, clock, uid)
+			if loadErr != nil {
+				// The existing Data is still actual. The Record might have
+				// been deleted while the Cache was unlocked, so it is
+				// requested again.
+				c.lock.Lock()
+				var existingRecord, lookupErr = c.getRecordByUID(uid)
+				if lookupErr == nil {
+					existingRecord.isBeingRefreshed = false
+				}
+				c.lock.Unlock()
+				return
+			}
+			data = freshData
+			return
+		}
+
+		err = c.deleteRecord(record, true)
+		if err != nil {
+			c.lock.Unlock()
+			return
+		}
+		c.statistics.Expirations++
+	}
+	c.statistics.Misses++
+	c.lock.Unlock()
+
+	return c.load(loader, clock, uid)
+}
+
+// Loads the Record's Data using the Loader and stores it in the Cache.
+// The Cache must not be locked by the Caller.
+func (c *FixedSizeBubbleCache) load(
+	loader FixedSizeBubbleCacheLoader,
+	clock func() time.Time,
+	uid FixedSizeBubbleCacheRecordUID,
+) (data interface{}, err error) {
+	var loadStartTime = clock()
+	data, err = loader(uid)
+	if err != nil {
+		return
+	}
+	var loadDuration = clock().Sub(loadStartTime)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var record = &FixedSizeBubbleCacheRecord{
+		UID:        uid,
+		Data:       data,
+		isNegative: (data == nil) && (c.negativeRecordTTL > 0),
+	}
+	err = record.Check()
+	if err != nil {
+		return
+	}
+
+	c.addRecord(record)
+	c.top.loadDuration = loadDuration
+	c.top.isBeingRefreshed = false
+
+	if record.isNegative {
+		err = fmt.Errorf(ErrfRecordWithUidIsNegative, uid)
+		return
+	}
+	return
+}
+
+// Returns the Source of the current Time used by the Cache. The Clock of the
+// early Expiration is used when it is set, so that the Expiration of Records
+// and the Decisions of the early Expiration follow the same Time.
+func (c *FixedSizeBubbleCache) clock() func() time.Time {
+	if (c.earlyExpiration != nil) && (c.earlyExpiration.Clock != nil) {
+		return c.earlyExpiration.Clock
+	}
+	return time.Now
+}
+
+// Returns the current Unix Time of the Cache's Clock in Seconds.
+func (c *FixedSizeBubbleCache) now() uint {
+	return uint(c.clock()().Unix())
+}
@@ -0,0 +1,118 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/tester"
+)
+
+func Test_GetOrLoadRecordDataByUID(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(2, 60)
+	var data interface{}
+	var err error
+	var loadsCount int
+	var loader = func(uid FixedSizeBubbleCacheRecordUID) (interface{}, error) {
+		loadsCount++
+		if uid == "bad" {
+			return nil, errors.New("load failure")
+		}
+		return fmt.Sprintf("%v-%v", uid, loadsCount), nil
+	}
+
+	// Test #1. No Loader.
+	data, err = cache.GetOrLoadRecordDataByUID("a")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrLoaderIsNotSet)
+
+	// Test #2. Missing Record is loaded.
+	cache.SetLoader(loader)
+	data, err = cache.GetOrLoadRecordDataByUID("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "a-1")
+	aTest.MustBeEqual(cache.top.UID, "a")
+
+	// Test #3. Existing Record is not loaded.
+	data, err = cache.GetOrLoadRecordDataByUID("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "a-1")
+	aTest.MustBeEqual(loadsCount, 1)
+
+	// Test #4. Loader Failure.
+	data, err = cache.GetOrLoadRecordDataByUID("bad")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(cache.RecordUIDExists("bad"), false)
+
+	// Test #5. Outdated Record is reloaded.
+	cache.top.lastAccessTime = 0
+	data, err = cache.GetOrLoadRecordDataByUID("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "a-3")
+	aTest.MustBeEqual(cache.size, uint(1))
+}
+
+func Test_GetOrLoadRecordDataByUID_EarlyExpiration(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(2, 60)
+	var data interface{}
+	var err error
+	var tsNow = time.Now()
+	var random float64 = 1
+	var loadsCount int
+	cache.SetLoader(func(uid FixedSizeBubbleCacheRecordUID) (interface{}, error) {
+		loadsCount++
+		tsNow = tsNow.Add(time.Second * 10) // An expensive Load.
+		return loadsCount, nil
+	})
+	cache.SetEarlyExpiration(&FixedSizeBubbleCacheEarlyExpiration{
+		Beta:   1,
+		Random: func() float64 { return random },
+		Clock:  func() time.Time { return tsNow },
+	})
+
+	// Test #1. Initial Load measures the Load Duration.
+	data, err = cache.GetOrLoadRecordDataByUID("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, 1)
+	aTest.MustBeEqual(cache.top.loadDuration, time.Second*10)
+
+	// Test #2. No early Reload.
+	data, err = cache.GetOrLoadRecordDataByUID("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, 1)
+	aTest.MustBeEqual(loadsCount, 1)
+
+	// Test #3. Early Reload.
+	random = 1e-9
+	data, err = cache.GetOrLoadRecordDataByUID("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, 2)
+	aTest.MustBeEqual(loadsCount, 2)
+	aTest.MustBeEqual(cache.top.isBeingRefreshed, false)
+
+	// Test #4. A Reload in Progress is not duplicated.
+	cache.top.isBeingRefreshed = true
+	data, err = cache.GetOrLoadRecordDataByUID("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, 2)
+	aTest.MustBeEqual(loadsCount, 2)
+
+	// Test #5. Expiration follows the Clock of the early Expiration.
+	cache.top.isBeingRefreshed = false
+	random = 1
+	tsNow = tsNow.Add(time.Second * 60)
+	aTest.MustBeEqual(cache.RecordUIDExists("a"), true)
+	var recordIsActive bool
+	recordIsActive, err = cache.IsRecordUIDActive("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(recordIsActive, false)
+	data, err = cache.GetOrLoadRecordDataByUID("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, 3)
+	aTest.MustBeEqual(cache.Stats().Expirations, uint64(1))
+}
@@ -0,0 +1,72 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"errors"
+)
+
+// Sets the Time-To-Live of negative Records, measured in Seconds.
+// Zero TTL disables negative Caching.
+func (c *FixedSizeBubbleCache) SetNegativeRecordTTL(
+	ttl uint,
+) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.negativeRecordTTL = ttl
+}
+
+// Returns the 'NegativeRecordTTL' Parameter of the Cache.
+func (c *FixedSizeBubbleCache) GetNegativeRecordTTL() uint {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.negativeRecordTTL
+}
+
+// Adds a negative Record to the Cache.
+//
+// A negative Record remembers that the Data with the specified UID does not
+// exist in the external Source, so that the Source is not requested again
+// until the negative Record becomes outdated. The negative Record TTL is
+// counted from the Addition, Requests of the negative Record do not extend
+// it. An existing Record with the same UID is replaced by the negative
+// Record.
+func (c *FixedSizeBubbleCache) AddNegativeRecord(
+	uid FixedSizeBubbleCacheRecordUID,
+) (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.negativeRecordTTL == 0 {
+		return errors.New(ErrNegativeCachingIsDisabled)
+	}
+
+	var record = &FixedSizeBubbleCacheRecord{
+		UID:        uid,
+		isNegative: true,
+	}
+	err = record.Check()
+	if err != nil {
+		return
+	}
+
+	c.addRecord(record)
+	return
+}
+
+// Updates the Counter of negative Records if the Record is negative.
+func (c *FixedSizeBubbleCache) countNegativeRecord(
+	record *FixedSizeBubbleCacheRecord,
+	delta int,
+) {
+	if !record.isNegative {
+		return
+	}
+	if delta > 0 {
+		c.statistics.NegativeRecords++
+	} else {
+		c.statistics.NegativeRecords--
+	}
+}
@@ -0,0 +1,124 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/vault-thirteen/tester"
+)
+
+func Test_SetNegativeRecordTTL(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(2, 60)
+
+	// Test #1.
+	aTest.MustBeEqual(cache.GetNegativeRecordTTL(), uint(0))
+	cache.SetNegativeRecordTTL(5)
+	aTest.MustBeEqual(cache.GetNegativeRecordTTL(), uint(5))
+}
+
+func Test_AddNegativeRecord(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(2, 60)
+	var data interface{}
+	var err error
+
+	// Test #1. Negative Caching is disabled.
+	err = cache.AddNegativeRecord("x")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrNegativeCachingIsDisabled)
+
+	// Test #2. Empty UID.
+	cache.SetNegativeRecordTTL(5)
+	err = cache.AddNegativeRecord("")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrUIDIsEmpty)
+
+	// Test #3. Normal.
+	err = cache.AddNegativeRecord("x")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(cache.top.isNegative, true)
+	data, err = cache.GetActualRecordDataByUID("x")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfRecordWithUidIsNegative, "x"))
+	aTest.MustBeEqual(data, nil)
+	aTest.MustBeEqual(cache.Stats().NegativeHits, uint64(1))
+	aTest.MustBeEqual(cache.Stats().NegativeRecords, uint(1))
+
+	// Test #4. Negative Record is replaced by an ordinary Record.
+	err = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "x", Data: 1})
+	aTest.MustBeNoError(err)
+	data, err = cache.GetActualRecordDataByUID("x")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, 1)
+	aTest.MustBeEqual(cache.Stats().NegativeRecords, uint(0))
+
+	// Test #5. Negative Record has its own TTL.
+	err = cache.AddNegativeRecord("y")
+	aTest.MustBeNoError(err)
+	cache.top.updateTime -= 10
+	data, err = cache.GetActualRecordDataByUID("y")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfRecordWithUidIsOutdated, "y"))
+	aTest.MustBeEqual(cache.Stats().NegativeRecords, uint(0))
+
+	// Test #6. Requests do not extend the Life of a negative Record.
+	err = cache.AddNegativeRecord("z")
+	aTest.MustBeNoError(err)
+	cache.top.updateTime -= 3
+	cache.top.lastAccessTime -= 3
+	_, err = cache.GetActualRecordDataByUID("z")
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfRecordWithUidIsNegative, "z"))
+	cache.top.updateTime -= 3
+	_, err = cache.GetActualRecordDataByUID("z")
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfRecordWithUidIsOutdated, "z"))
+}
+
+func Test_GetOrLoadRecordDataByUID_NegativeRecord(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(2, 60)
+	var err error
+	var loadsCount int
+	cache.SetLoader(func(uid FixedSizeBubbleCacheRecordUID) (interface{}, error) {
+		loadsCount++
+		return nil, nil
+	})
+
+	// Test #1. Negative Caching is disabled.
+	_, err = cache.GetOrLoadRecordDataByUID("x")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrDataIsEmpty)
+	aTest.MustBeEqual(cache.RecordUIDExists("x"), false)
+
+	// Test #2. Negative Record is stored and is not loaded again.
+	cache.SetNegativeRecordTTL(5)
+	_, err = cache.GetOrLoadRecordDataByUID("x")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfRecordWithUidIsNegative, "x"))
+	_, err = cache.GetOrLoadRecordDataByUID("x")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfRecordWithUidIsNegative, "x"))
+	aTest.MustBeEqual(loadsCount, 2)
+	aTest.MustBeEqual(cache.Stats().NegativeHits, uint64(1))
+}
+
+func Test_countNegativeRecord(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(2, 60)
+	cache.SetNegativeRecordTTL(5)
+
+	// Test #1. Eviction of a negative Record.
+	_ = cache.AddNegativeRecord("a")
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2})
+	aTest.MustBeEqual(cache.Stats().NegativeRecords, uint(1))
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "c", Data: 3})
+	aTest.MustBeEqual(cache.Stats().NegativeRecords, uint(0))
+
+	// Test #2. Deletion of a negative Record.
+	_ = cache.AddNegativeRecord("d")
+	aTest.MustBeEqual(cache.Stats().NegativeRecords, uint(1))
+	_ = cache.DeleteRecordByUID("d")
+	aTest.MustBeEqual(cache.Stats().NegativeRecords, uint(0))
+}
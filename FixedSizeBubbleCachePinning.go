@@ -0,0 +1,143 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"errors"
+)
+
+// Sets the maximum Count of pinned Records. Zero Limit selects the default
+// Limit, which is one Record less than the Capacity. The Limit can not exceed
+// the default Limit, so that the Cache can always accept new Records. Records
+// pinned before are kept pinned.
+func (c *FixedSizeBubbleCache) SetPinnedRecordsLimit(
+	limit uint,
+) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.pinnedRecordsLimit = limit
+}
+
+// Returns the maximum Count of pinned Records.
+func (c *FixedSizeBubbleCache) GetPinnedRecordsLimit() uint {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.getPinnedRecordsLimit()
+}
+
+// Returns the maximum Count of pinned Records.
+func (c *FixedSizeBubbleCache) getPinnedRecordsLimit() uint {
+	var defaultLimit = c.capacity - 1
+	if (c.pinnedRecordsLimit == 0) || (c.pinnedRecordsLimit > defaultLimit) {
+		return defaultLimit
+	}
+	return c.pinnedRecordsLimit
+}
+
+// Pins an actual Record, so that it is not evicted when the Cache is full.
+// The Record is not moved. A pinned Record is still deleted when it becomes
+// outdated, when it is deleted explicitly or when the Cache is cleared.
+// Negative Records can not be pinned.
+func (c *FixedSizeBubbleCache) Pin(
+	uid FixedSizeBubbleCacheRecordUID,
+) (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var record *FixedSizeBubbleCacheRecord
+	record, err = c.getUpdatableRecord(uid)
+	if err != nil {
+		return
+	}
+	if record.isPinned {
+		return
+	}
+	if c.pinnedCount >= c.getPinnedRecordsLimit() {
+		return errors.New(ErrTooManyPinnedRecords)
+	}
+
+	record.isPinned = true
+	c.countPinnedRecord(record, 1)
+	c.journalRecordPinChanged(record)
+	return
+}
+
+// Unpins the Record, so that it may be evicted again.
+func (c *FixedSizeBubbleCache) Unpin(
+	uid FixedSizeBubbleCacheRecordUID,
+) (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var record *FixedSizeBubbleCacheRecord
+	record, err = c.getRecordByUID(uid)
+	if err != nil {
+		return
+	}
+	if !record.isPinned {
+		return
+	}
+
+	c.countPinnedRecord(record, -1)
+	record.isPinned = false
+	c.journalRecordPinChanged(record)
+	return
+}
+
+// Pins or unpins a restored Record. The Record is not pinned when the Limit of
+// pinned Records is reached.
+func (c *FixedSizeBubbleCache) restorePin(
+	record *FixedSizeBubbleCacheRecord,
+	isPinned bool,
+) {
+	if record.isPinned == isPinned {
+		return
+	}
+	if isPinned && (c.pinnedCount >= c.getPinnedRecordsLimit()) {
+		return
+	}
+
+	if isPinned {
+		record.isPinned = true
+		c.countPinnedRecord(record, 1)
+	} else {
+		c.countPinnedRecord(record, -1)
+		record.isPinned = false
+	}
+}
+
+// Returns the lowest Record which is not pinned. The Cache must not be empty.
+// The Count of pinned Records is less than the Capacity, so a full Cache
+// always has such a Record.
+func (c *FixedSizeBubbleCache) evictionCandidate() (record *FixedSizeBubbleCacheRecord) {
+	record = c.bottom
+	for record.isPinned {
+		record = c.upperOf(record)
+	}
+	return
+}
+
+// Checks whether the Record with the UID exists and is pinned.
+func (c *FixedSizeBubbleCache) isPinnedUID(
+	uid FixedSizeBubbleCacheRecordUID,
+) bool {
+	var i, uidExists = c.recordsByUID.find(uid)
+	return uidExists && c.records[i].isPinned
+}
+
+// Updates the Counter of pinned Records if the Record is pinned.
+func (c *FixedSizeBubbleCache) countPinnedRecord(
+	record *FixedSizeBubbleCacheRecord,
+	delta int,
+) {
+	if !record.isPinned {
+		return
+	}
+	if delta > 0 {
+		c.pinnedCount++
+	} else {
+		c.pinnedCount--
+	}
+}
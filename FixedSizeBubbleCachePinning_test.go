@@ -0,0 +1,134 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/tester"
+)
+
+func Test_PinnedRecordsLimit(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(4, 60)
+
+	// Test #1. Default Limit.
+	aTest.MustBeEqual(cache.GetPinnedRecordsLimit(), uint(3))
+
+	// Test #2. Custom Limit.
+	cache.SetPinnedRecordsLimit(2)
+	aTest.MustBeEqual(cache.GetPinnedRecordsLimit(), uint(2))
+
+	// Test #3. Limit can not exceed the default Limit.
+	cache.SetPinnedRecordsLimit(10)
+	aTest.MustBeEqual(cache.GetPinnedRecordsLimit(), uint(3))
+	aTest.MustBeEqual(NewFixedSizeBubbleCache(1, 60).GetPinnedRecordsLimit(), uint(0))
+}
+
+func Test_Pin(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(3, 60)
+	cache.SetNegativeRecordTTL(60)
+	var evictedUIDs []FixedSizeBubbleCacheRecordUID
+	cache.SetEvictionHandler(func(uid FixedSizeBubbleCacheRecordUID, data interface{}, ttl uint, expirationTime time.Time) {
+		evictedUIDs = append(evictedUIDs, uid)
+	})
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "c", Data: 3}))
+
+	// Test #1. Pinned Records are skipped by Evictions.
+	aTest.MustBeNoError(cache.Pin("a"))
+	aTest.MustBeNoError(cache.Pin("a"))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "d", Data: 4}))
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"d", "c", "a"})
+	aTest.MustBeEqual(evictedUIDs, []FixedSizeBubbleCacheRecordUID{"b"})
+	var info, _ = cache.GetRecordInfo("a")
+	aTest.MustBeEqual(info.IsPinned, true)
+	aTest.MustBeEqual(cache.isIntegral(), true)
+
+	// Test #2. The Limit keeps Space for new Records.
+	aTest.MustBeNoError(cache.Pin("c"))
+	aTest.MustBeAnError(cache.Pin("d"))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "e", Data: 5}))
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"e", "c", "a"})
+	aTest.MustBeNoError(cache.AddRecordAtBottom(&FixedSizeBubbleCacheRecord{UID: "f", Data: 6}))
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"c", "a", "f"})
+	var errs = cache.AddRecords([]*FixedSizeBubbleCacheRecord{{UID: "g", Data: 7}})
+	aTest.MustBeEqual(errs, []error{nil})
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"g", "c", "a"})
+	aTest.MustBeEqual(evictedUIDs, []FixedSizeBubbleCacheRecordUID{"b", "d", "e", "f"})
+
+	// Test #3. Deletion of a pinned Record releases its Pin.
+	aTest.MustBeNoError(cache.DeleteRecordByUID("c"))
+	aTest.MustBeNoError(cache.Pin("g"))
+	aTest.MustBeEqual(cache.pinnedCount, uint(2))
+
+	// Test #4. Unpinned Records are evicted again.
+	aTest.MustBeNoError(cache.Unpin("a"))
+	aTest.MustBeNoError(cache.Unpin("a"))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "h", Data: 8}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "i", Data: 9}))
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"i", "h", "g"})
+	aTest.MustBeEqual(cache.pinnedCount, uint(1))
+
+	// Test #5. Missing and negative Records.
+	aTest.MustBeAnError(cache.Pin("x"))
+	aTest.MustBeAnError(cache.Unpin("x"))
+	aTest.MustBeNoError(cache.AddNegativeRecord("n"))
+	aTest.MustBeAnError(cache.Pin("n"))
+
+	// Test #6. Clearing releases all Pins.
+	aTest.MustBeNoError(cache.Clear())
+	aTest.MustBeEqual(cache.pinnedCount, uint(0))
+}
+
+func Test_AddRecords_PinnedRecords(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(4, 60)
+	for _, uid := range []FixedSizeBubbleCacheRecordUID{"a", "b", "c", "d"} {
+		aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: uid, Data: uid}))
+	}
+	aTest.MustBeNoError(cache.Pin("a"))
+	aTest.MustBeNoError(cache.Pin("b"))
+
+	// Test #1. Records of the Batch do not evict each other.
+	var errs = cache.AddRecords([]*FixedSizeBubbleCacheRecord{
+		{UID: "x", Data: 1},
+		{UID: "y", Data: 2},
+		{UID: "z", Data: 3},
+	})
+	aTest.MustBeAnError(errs[0])
+	aTest.MustBeNoError(errs[1])
+	aTest.MustBeNoError(errs[2])
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"z", "y", "b", "a"})
+
+	// Test #2. Pinned Records of the Batch use their own Places.
+	errs = cache.AddRecords([]*FixedSizeBubbleCacheRecord{
+		{UID: "p", Data: 4},
+		{UID: "a", Data: 5},
+		{UID: "q", Data: 6},
+		{UID: "r", Data: 7},
+	})
+	aTest.MustBeAnError(errs[0])
+	aTest.MustBeNoError(errs[1])
+	aTest.MustBeNoError(errs[2])
+	aTest.MustBeNoError(errs[3])
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"r", "q", "a", "b"})
+
+	// Test #3. A dropped pinned Record keeps its Place.
+	errs = cache.AddRecords([]*FixedSizeBubbleCacheRecord{
+		{UID: "a", Data: 8},
+		{UID: "s", Data: 9},
+		{UID: "t", Data: 10},
+		{UID: "u", Data: 11},
+	})
+	aTest.MustBeAnError(errs[0])
+	aTest.MustBeAnError(errs[1])
+	aTest.MustBeNoError(errs[2])
+	aTest.MustBeNoError(errs[3])
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"u", "t", "a", "b"})
+	aTest.MustBeEqual(cache.ListAllRecordValues(), []interface{}{11, 10, 5, "b"})
+	aTest.MustBeEqual(cache.isIntegral(), true)
+}
@@ -0,0 +1,101 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"errors"
+)
+
+// Removes the Bottom Record, i.e. the least recently used Record, from the
+// Cache and returns its Copy. The Copy may be added to a Cache again.
+// Returns an Error when the Cache is empty. As with Evictions, the Record is
+// passed to the Eviction Handler. As with 'Drain', negative and outdated
+// Records are removed on the Way and are not returned; outdated Records are
+// counted as Expirations.
+func (c *FixedSizeBubbleCache) PopBottom() (record *FixedSizeBubbleCacheRecord, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.popRecord(false)
+}
+
+// Removes the Top Record, i.e. the most recently used Record, from the Cache
+// and returns its Copy. The Copy may be added to a Cache again. Returns an
+// Error when the Cache is empty. As with Evictions, the Record is passed to
+// the Eviction Handler. As with 'Drain', negative and outdated Records are
+// removed on the Way and are not returned; outdated Records are counted as
+// Expirations.
+func (c *FixedSizeBubbleCache) PopTop() (record *FixedSizeBubbleCacheRecord, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.popRecord(true)
+}
+
+// Removes Records from the Bottom of the Cache and passes them to the
+// Visitor, until the Visitor returns 'false' or the Cache is empty. The
+// Record passed to the Visitor is already removed, the Records which have not
+// been passed stay in the Cache. Negative Records have no Data and are removed
+// without being passed to the Visitor. Outdated Records are removed without
+// being passed to the Visitor as well and are counted as Expirations. Returns
+// the Count of removed Records. As with Evictions, the passed Records are also
+// passed to the Eviction Handler.
+//
+// The Cache is locked during the whole Drain, so no other Changes are mixed
+// with it. The Visitor is called while the Cache is locked, so it must not
+// call any locking Methods of the Cache.
+func (c *FixedSizeBubbleCache) Drain(
+	visitor FixedSizeBubbleCacheVisitor,
+) (removedCount uint) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for c.size > 0 {
+		var record = c.bottom
+		var uid, data = record.UID, record.Data
+		var isPassed = c.removeEndRecord(record)
+		removedCount++
+		if !isPassed {
+			continue
+		}
+		if !visitor(uid, data) {
+			return
+		}
+	}
+	return
+}
+
+// Deletes actual Records from the Top or from the Bottom of the Cache until
+// an actual Record is deleted. Returns the Copy of that Record.
+func (c *FixedSizeBubbleCache) popRecord(
+	fromTop bool,
+) (poppedRecord *FixedSizeBubbleCacheRecord, err error) {
+	for c.size > 0 {
+		var record = c.bottom
+		if fromTop {
+			record = c.top
+		}
+
+		var copiedRecord = record.unlinkedCopy()
+		if c.removeEndRecord(record) {
+			return copiedRecord, nil
+		}
+	}
+	return nil, errors.New(ErrCacheZeroSize)
+}
+
+// Removes a Record from an End of the Cache and passes it to the Eviction
+// Handler. Returns whether the Record was actual and not negative. Outdated
+// Records are counted as Expirations.
+func (c *FixedSizeBubbleCache) removeEndRecord(
+	record *FixedSizeBubbleCacheRecord,
+) (isActual bool) {
+	isActual = !record.isNegative
+	if !c.isRecordActual(record) {
+		c.statistics.Expirations++
+		isActual = false
+	}
+	c.notifyEviction(record)
+	_ = c.deleteRecord(record, true)
+	return
+}
@@ -0,0 +1,180 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/tester"
+)
+
+// A Journal which lists the UIDs of deleted Records.
+type deletionTestJournal struct {
+	deletedUIDs []FixedSizeBubbleCacheRecordUID
+}
+
+func (j *deletionTestJournal) recordAdded(record *FixedSizeBubbleCacheRecord)         {}
+func (j *deletionTestJournal) recordTouched(record *FixedSizeBubbleCacheRecord)       {}
+func (j *deletionTestJournal) recordAddedAtBottom(record *FixedSizeBubbleCacheRecord) {}
+func (j *deletionTestJournal) recordPinChanged(record *FixedSizeBubbleCacheRecord)    {}
+func (j *deletionTestJournal) recordDeleted(record *FixedSizeBubbleCacheRecord) {
+	j.deletedUIDs = append(j.deletedUIDs, record.UID)
+}
+
+func Test_PopBottom(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(3, 60)
+	var journal = &deletionTestJournal{}
+	cache.setJournal(journal)
+	var evictedUIDs []FixedSizeBubbleCacheRecordUID
+	cache.SetEvictionHandler(func(uid FixedSizeBubbleCacheRecordUID, data interface{}, ttl uint, expirationTime time.Time) {
+		evictedUIDs = append(evictedUIDs, uid)
+	})
+
+	// Test #1. Empty Cache.
+	var record, err = cache.PopBottom()
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(record == nil, true)
+
+	// Test #2. Records go from the Bottom.
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2}))
+	record, err = cache.PopBottom()
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(record.UID, "a")
+	aTest.MustBeEqual(record.Data, 1)
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"b"})
+	aTest.MustBeEqual(cache.isIntegral(), true)
+
+	// Test #3. Single Record.
+	record, err = cache.PopBottom()
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(record.UID, "b")
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{})
+	aTest.MustBeEqual(cache.isIntegral(), true)
+	aTest.MustBeEqual(journal.deletedUIDs, []FixedSizeBubbleCacheRecordUID{"a", "b"})
+	aTest.MustBeEqual(evictedUIDs, []FixedSizeBubbleCacheRecordUID{"a", "b"})
+	aTest.MustBeEqual(cache.Stats().Evictions, uint64(0))
+
+	// Test #4. The popped Copy may be added again.
+	aTest.MustBeNoError(cache.AddRecord(record))
+	aTest.MustBeEqual(cache.ListAllRecordValues(), []interface{}{2})
+
+	// Test #5. Negative and outdated Records are skipped.
+	cache.SetNegativeRecordTTL(60)
+	aTest.MustBeNoError(cache.AddNegativeRecord("n"))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "o", Data: 0}))
+	cache.top.lastAccessTime -= 60
+	record, err = cache.PopBottom()
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(record.UID, "b")
+	record, err = cache.PopBottom()
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(record == nil, true)
+	aTest.MustBeEqual(cache.Stats().Expirations, uint64(1))
+	aTest.MustBeEqual(cache.isIntegral(), true)
+}
+
+func Test_PopTop(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(3, 60)
+	var journal = &deletionTestJournal{}
+	cache.setJournal(journal)
+
+	// Test #1. Empty Cache.
+	var _, err = cache.PopTop()
+	aTest.MustBeAnError(err)
+
+	// Test #2. Records go from the Top.
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "c", Data: 3}))
+	aTest.MustBeNoError(cache.Pin("c"))
+	var record *FixedSizeBubbleCacheRecord
+	record, err = cache.PopTop()
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(record.UID, "c")
+	aTest.MustBeEqual(record.Data, 3)
+	aTest.MustBeEqual(cache.pinnedCount, uint(0))
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"b", "a"})
+	record, err = cache.PopTop()
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(record.UID, "b")
+	record, err = cache.PopTop()
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(record.UID, "a")
+	aTest.MustBeEqual(cache.isIntegral(), true)
+	aTest.MustBeEqual(journal.deletedUIDs, []FixedSizeBubbleCacheRecordUID{"c", "b", "a"})
+
+	// Test #3. Empty again.
+	_, err = cache.PopTop()
+	aTest.MustBeAnError(err)
+
+	// Test #4. Negative and outdated Records are skipped.
+	cache.SetNegativeRecordTTL(60)
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "d", Data: 4}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "o", Data: 0}))
+	cache.top.lastAccessTime -= 60
+	aTest.MustBeNoError(cache.AddNegativeRecord("n"))
+	record, err = cache.PopTop()
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(record.UID, "d")
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{})
+	aTest.MustBeEqual(cache.Stats().Expirations, uint64(1))
+}
+
+func Test_Drain(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(5, 60)
+	cache.SetNegativeRecordTTL(60)
+	var journal = &deletionTestJournal{}
+	cache.setJournal(journal)
+	var evictedUIDs []FixedSizeBubbleCacheRecordUID
+	cache.SetEvictionHandler(func(uid FixedSizeBubbleCacheRecordUID, data interface{}, ttl uint, expirationTime time.Time) {
+		evictedUIDs = append(evictedUIDs, uid)
+	})
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1}))
+	aTest.MustBeNoError(cache.AddNegativeRecord("n"))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "o", Data: 0}))
+	cache.top.lastAccessTime -= 60
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "c", Data: 3}))
+
+	// Test #1. Early Stop keeps the remaining Records.
+	var uids []FixedSizeBubbleCacheRecordUID
+	var values []interface{}
+	var removedCount = cache.Drain(func(uid FixedSizeBubbleCacheRecordUID, data interface{}) bool {
+		uids = append(uids, uid)
+		values = append(values, data)
+		return uid != "b"
+	})
+	aTest.MustBeEqual(removedCount, uint(4))
+	aTest.MustBeEqual(uids, []FixedSizeBubbleCacheRecordUID{"a", "b"})
+	aTest.MustBeEqual(values, []interface{}{1, 2})
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"c"})
+	aTest.MustBeEqual(cache.Stats().NegativeRecords, uint(0))
+	aTest.MustBeEqual(journal.deletedUIDs, []FixedSizeBubbleCacheRecordUID{"a", "n", "o", "b"})
+
+	// Test #2. Outdated Records are counted as Expirations, only the passed
+	// Records reach the Eviction Handler.
+	aTest.MustBeEqual(cache.Stats().Expirations, uint64(1))
+	aTest.MustBeEqual(evictedUIDs, []FixedSizeBubbleCacheRecordUID{"a", "b"})
+
+	// Test #3. Full Drain.
+	uids = nil
+	removedCount = cache.Drain(func(uid FixedSizeBubbleCacheRecordUID, data interface{}) bool {
+		uids = append(uids, uid)
+		return true
+	})
+	aTest.MustBeEqual(removedCount, uint(1))
+	aTest.MustBeEqual(uids, []FixedSizeBubbleCacheRecordUID{"c"})
+	aTest.MustBeEqual(cache.isIntegral(), true)
+
+	// Test #4. Empty Cache.
+	removedCount = cache.Drain(func(uid FixedSizeBubbleCacheRecordUID, data interface{}) bool {
+		t.Fatal("the Cache is empty")
+		return true
+	})
+	aTest.MustBeEqual(removedCount, uint(0))
+}
@@ -0,0 +1,99 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+// A Record returned by a Range Query: the Record's Data with the Information
+// about the Record.
+type RangeRecord struct {
+	RecordInfo
+
+	// Data stored in the Record. Negative Records have no Data.
+	Data interface{}
+}
+
+// Returns up to N Records from the Top of the Cache, i.e. the most recently
+// used Records. The Records are neither moved nor refreshed.
+func (c *FixedSizeBubbleCache) TopN(
+	n uint,
+) (records []RangeRecord) {
+	return c.Range(0, n)
+}
+
+// Returns up to N Records from the Bottom of the Cache, i.e. the Records which
+// are the next to be evicted. The Bottom Record goes first. The Records are
+// neither moved nor refreshed.
+func (c *FixedSizeBubbleCache) BottomN(
+	n uint,
+) (records []RangeRecord) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if n > c.size {
+		n = c.size
+	}
+
+	records = make([]RangeRecord, 0, n)
+	var now = c.now()
+	var position = c.size - 1
+	for record := c.bottom; uint(len(records)) < n; record = c.upperOf(record) {
+		records = append(records, c.rangeRecord(record, position, now))
+		position--
+	}
+	return
+}
+
+// Returns up to 'limit' Records starting at the Position 'offset', counted
+// from the Top, which has the Position 0. The Records go from the Top to the
+// Bottom and are neither moved nor refreshed.
+//
+// The Walk starts at the nearer End of the Cache, so the Time of the Query is
+// proportional to the Distance of the Range from that End and to its Length.
+func (c *FixedSizeBubbleCache) Range(
+	offset uint,
+	limit uint,
+) (records []RangeRecord) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if offset >= c.size {
+		return []RangeRecord{}
+	}
+	if limit > c.size-offset {
+		limit = c.size - offset
+	}
+
+	// Find the first Record of the Range.
+	var record *FixedSizeBubbleCacheRecord
+	var position uint
+	if offset <= c.size/2 {
+		record = c.top
+		for position = 0; position < offset; position++ {
+			record = c.lowerOf(record)
+		}
+	} else {
+		record = c.bottom
+		for position = c.size - 1; position > offset; position-- {
+			record = c.upperOf(record)
+		}
+	}
+
+	records = make([]RangeRecord, 0, limit)
+	var now = c.now()
+	for ; uint(len(records)) < limit; record = c.lowerOf(record) {
+		records = append(records, c.rangeRecord(record, position, now))
+		position++
+	}
+	return
+}
+
+// Returns the Record at the Position for a Range Query.
+func (c *FixedSizeBubbleCache) rangeRecord(
+	record *FixedSizeBubbleCacheRecord,
+	position uint,
+	now uint,
+) RangeRecord {
+	return RangeRecord{
+		RecordInfo: c.recordInfo(record, position, now),
+		Data:       record.Data,
+	}
+}
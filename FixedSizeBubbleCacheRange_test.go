@@ -0,0 +1,106 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"testing"
+
+	"github.com/vault-thirteen/tester"
+)
+
+// Lists the UIDs, the Data and the Positions of the Records of a Range.
+func describeRangeRecords(
+	records []RangeRecord,
+) (uids []FixedSizeBubbleCacheRecordUID, values []interface{}, positions []uint) {
+	uids = []FixedSizeBubbleCacheRecordUID{}
+	values = []interface{}{}
+	positions = []uint{}
+	for _, record := range records {
+		uids = append(uids, record.UID)
+		values = append(values, record.Data)
+		positions = append(positions, record.Position)
+	}
+	return
+}
+
+// Creates a Cache with the Records [e,d,c,b,a].
+func newRangeTestCache(
+	aTest *tester.Test,
+) (cache *FixedSizeBubbleCache) {
+	cache = NewFixedSizeBubbleCache(5, 60)
+	for i, uid := range []FixedSizeBubbleCacheRecordUID{"a", "b", "c", "d", "e"} {
+		aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: uid, Data: i + 1}))
+	}
+	return
+}
+
+func Test_TopN(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = newRangeTestCache(aTest)
+
+	// Test #1. Hottest Records.
+	var uids, values, positions = describeRangeRecords(cache.TopN(2))
+	aTest.MustBeEqual(uids, []FixedSizeBubbleCacheRecordUID{"e", "d"})
+	aTest.MustBeEqual(values, []interface{}{5, 4})
+	aTest.MustBeEqual(positions, []uint{0, 1})
+
+	// Test #2. N is larger than the Size.
+	uids, _, _ = describeRangeRecords(cache.TopN(10))
+	aTest.MustBeEqual(uids, []FixedSizeBubbleCacheRecordUID{"e", "d", "c", "b", "a"})
+
+	// Test #3. Zero N and empty Cache.
+	aTest.MustBeEqual(cache.TopN(0), []RangeRecord{})
+	aTest.MustBeEqual(NewFixedSizeBubbleCache(3, 60).TopN(3), []RangeRecord{})
+}
+
+func Test_BottomN(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = newRangeTestCache(aTest)
+
+	// Test #1. Records next to be evicted.
+	var uids, values, positions = describeRangeRecords(cache.BottomN(2))
+	aTest.MustBeEqual(uids, []FixedSizeBubbleCacheRecordUID{"a", "b"})
+	aTest.MustBeEqual(values, []interface{}{1, 2})
+	aTest.MustBeEqual(positions, []uint{4, 3})
+
+	// Test #2. N is larger than the Size.
+	uids, _, positions = describeRangeRecords(cache.BottomN(10))
+	aTest.MustBeEqual(uids, []FixedSizeBubbleCacheRecordUID{"a", "b", "c", "d", "e"})
+	aTest.MustBeEqual(positions, []uint{4, 3, 2, 1, 0})
+
+	// Test #3. Zero N and empty Cache.
+	aTest.MustBeEqual(cache.BottomN(0), []RangeRecord{})
+	aTest.MustBeEqual(NewFixedSizeBubbleCache(3, 60).BottomN(3), []RangeRecord{})
+
+	// Test #4. Records are not moved.
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"e", "d", "c", "b", "a"})
+}
+
+func Test_Range(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = newRangeTestCache(aTest)
+
+	// Test #1. Range near the Top.
+	var uids, _, positions = describeRangeRecords(cache.Range(1, 2))
+	aTest.MustBeEqual(uids, []FixedSizeBubbleCacheRecordUID{"d", "c"})
+	aTest.MustBeEqual(positions, []uint{1, 2})
+
+	// Test #2. Range near the Bottom.
+	uids, _, positions = describeRangeRecords(cache.Range(3, 10))
+	aTest.MustBeEqual(uids, []FixedSizeBubbleCacheRecordUID{"b", "a"})
+	aTest.MustBeEqual(positions, []uint{3, 4})
+	uids, _, _ = describeRangeRecords(cache.Range(4, 1))
+	aTest.MustBeEqual(uids, []FixedSizeBubbleCacheRecordUID{"a"})
+
+	// Test #3. Range out of the Cache.
+	aTest.MustBeEqual(cache.Range(5, 1), []RangeRecord{})
+	aTest.MustBeEqual(cache.Range(2, 0), []RangeRecord{})
+
+	// Test #4. Metadata.
+	var records = cache.Range(2, 1)
+	aTest.MustBeEqual(records[0].UID, "c")
+	aTest.MustBeEqual(records[0].Data, 3)
+	aTest.MustBeEqual(records[0].TTL, uint(60))
+	aTest.MustBeEqual(records[0].Cost, uint(1))
+	aTest.MustBeEqual(records[0].Version, uint64(3))
+}
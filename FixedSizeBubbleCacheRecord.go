@@ -16,18 +16,69 @@ type FixedSizeBubbleCacheRecord struct {
 	// Time of the last Access to the Record.
 	lastAccessTime uint
 
-	// A Pointer to an upper Record.
-	upperRecord *FixedSizeBubbleCacheRecord
+	// Time when the Record has been added to the Cache.
+	creationTime uint
 
-	// A Pointer to a lower Record.
-	lowerRecord *FixedSizeBubbleCacheRecord
+	// Time of the last Change of the Record's Data. The probabilistic early
+	// Expiration counts the Age of the Data from this Time, so that Requests,
+	// which refresh the LAT, do not postpone the Reload.
+	updateTime uint
+
+	// Count of Requests of the Record's Data.
+	accessCount uint64
+
+	// An individual Time-To-Live of the Record, measured in Seconds.
+	// Zero TTL means that the Cache's Record TTL is used.
+	ttl uint
+
+	// An absolute Expiration Time of the Record, as Unix Time in Seconds.
+	// Zero Time means that the Record expires by its TTL. Requests do not
+	// extend the absolute Expiration Time.
+	expirationTime uint
+
+	// A Flag showing that the Record is negative, i.e. it remembers that the
+	// Data with the Record's UID does not exist. Negative Records have no
+	// Data.
+	isNegative bool
+
+	// Duration of the last Load of the Record's Data by the Cache's Loader.
+	// It is used by the probabilistic early Expiration.
+	loadDuration time.Duration
+
+	// A Flag showing that the Record's Data is being reloaded by one of the
+	// Callers, so that other Callers do not start the same Reload.
+	isBeingRefreshed bool
+
+	// Version of the Record's Data. It is assigned by the Cache and increases
+	// with every Change of the Data.
+	version uint64
+
+	// A Flag showing that the Record is pinned, i.e. it is not evicted when
+	// the Cache is full.
+	isPinned bool
+
+	// A Flag showing that the Record belongs to the Storage of a Cache and is
+	// linked into its List. Such Records must not be added to any Cache.
+	isStored bool
+
+	// Index of the Record in the Storage of the Cache.
+	index int32
+
+	// Index of an upper Record in the Storage of the Cache, or 'noRecord'.
+	upper int32
+
+	// Index of a lower Record in the Storage of the Cache, or 'noRecord'.
+	lower int32
 }
 
+// Absence of a Record in Links between Records.
+const noRecord = int32(-1)
+
 // Checks the Record before insertion into the Cache.
 func (r *FixedSizeBubbleCacheRecord) Check() (err error) {
 
 	// Check the 'Data' Field.
-	if r.Data == nil {
+	if (r.Data == nil) && (!r.isNegative) {
 		return errors.New(ErrDataIsEmpty)
 	}
 
@@ -35,6 +86,11 @@ func (r *FixedSizeBubbleCacheRecord) Check() (err error) {
 	if len(r.UID) == 0 {
 		return errors.New(ErrUIDIsEmpty)
 	}
+
+	// Records of a Cache are never added again, only their Copies are.
+	if r.isStored {
+		return errors.New(ErrRecordIsStoredInCache)
+	}
 	return
 }
 
@@ -57,9 +113,60 @@ func (r *FixedSizeBubbleCacheRecord) updateLATWithCurrentTime() {
 	r.lastAccessTime = uint(time.Now().Unix())
 }
 
-// Checks whether the Record is outdated or not.
-func (r *FixedSizeBubbleCacheRecord) isActual(
-	ttl uint,
-) bool {
-	return uint(time.Now().Unix()) < r.lastAccessTime+ttl
+// Sets an absolute Expiration Time of the Record, which is used instead of
+// its TTL. Requests do not extend this Time. The Time is truncated to whole
+// Seconds, as all Times of the Cache. Zero Time removes the absolute
+// Expiration Time.
+func (r *FixedSizeBubbleCacheRecord) SetExpirationTime(
+	expirationTime time.Time,
+) {
+	r.expirationTime = unixTimeOfExpiration(expirationTime)
+}
+
+// Converts an absolute Expiration Time into Unix Time in Seconds. Zero Time is
+// converted into Zero, Times before the Epoch are converted into the first
+// Second of the Epoch, which has already passed.
+func unixTimeOfExpiration(
+	expirationTime time.Time,
+) uint {
+	if expirationTime.IsZero() {
+		return 0
+	}
+	var seconds = expirationTime.Unix()
+	if seconds < 1 {
+		return 1
+	}
+	return uint(seconds)
+}
+
+// Updates the Record's Data, its Last Access Time and its Update Time with
+// the Time provided.
+func (r *FixedSizeBubbleCacheRecord) updateData(
+	data interface{},
+	now uint,
+) {
+	r.Data = data
+	r.lastAccessTime = now
+	r.updateTime = now
+}
+
+// Returns a Copy of the Record which is not linked to any Cache.
+func (r *FixedSizeBubbleCacheRecord) unlinkedCopy() *FixedSizeBubbleCacheRecord {
+	return &FixedSizeBubbleCacheRecord{
+		UID:            r.UID,
+		Data:           r.Data,
+		lastAccessTime: r.lastAccessTime,
+		creationTime:   r.creationTime,
+		updateTime:     r.updateTime,
+		accessCount:    r.accessCount,
+		ttl:            r.ttl,
+		expirationTime: r.expirationTime,
+		isNegative:     r.isNegative,
+		isPinned:       r.isPinned,
+		loadDuration:   r.loadDuration,
+		version:        r.version,
+		index:          noRecord,
+		upper:          noRecord,
+		lower:          noRecord,
+	}
 }
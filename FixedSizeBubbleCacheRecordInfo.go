@@ -0,0 +1,124 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"time"
+)
+
+// Information about a Record of the Cache. It is a Copy of the Record's
+// Parameters, so it stays unchanged when the Record is changed.
+type RecordInfo struct {
+
+	// A unique Identifier of the Record.
+	UID FixedSizeBubbleCacheRecordUID
+
+	// Time of the last Access to the Record.
+	LastAccessTime time.Time
+
+	// Time when the Record has been added to the Cache.
+	CreationTime time.Time
+
+	// Time when the Record becomes outdated.
+	ExpirationTime time.Time
+
+	// Time-To-Live applied to the Record, measured in Seconds.
+	TTL uint
+
+	// Remaining Time-To-Live of the Record, measured in Seconds. It is zero
+	// for an outdated Record.
+	RemainingTTL uint
+
+	// Count of Requests of the Record's Data.
+	AccessCount uint64
+
+	// Cost of the Record in the Capacity of the Cache. Each Record costs one
+	// Unit, so the Sum of all Costs is the Size of the Cache.
+	Cost uint
+
+	// Position of the Record in the Cache, counted from the Top, which has
+	// the Position 0.
+	Position uint
+
+	// Version of the Record's Data.
+	Version uint64
+
+	// A Flag showing that the Record is negative.
+	IsNegative bool
+
+	// A Flag showing that the Record is pinned.
+	IsPinned bool
+}
+
+// Cost of a Record in the Capacity of the Cache.
+const recordCost = 1
+
+// Returns the Information about the Record. The Record is neither moved nor
+// refreshed, an outdated Record is reported with zero remaining TTL.
+//
+// The Position of the Record is found by walking from the Top, so the Time of
+// the Request is proportional to the Position.
+func (c *FixedSizeBubbleCache) GetRecordInfo(
+	uid FixedSizeBubbleCacheRecordUID,
+) (info RecordInfo, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var record *FixedSizeBubbleCacheRecord
+	record, err = c.getRecordByUID(uid)
+	if err != nil {
+		return
+	}
+
+	var position uint
+	for cursor := c.top; cursor != record; cursor = c.lowerOf(cursor) {
+		position++
+	}
+
+	return c.recordInfo(record, position, c.now()), nil
+}
+
+// Lists the Information about all the Records of the Cache, from the Top to
+// the Bottom. The Records are neither moved nor refreshed.
+func (c *FixedSizeBubbleCache) ListAllRecordInfos() (infos []RecordInfo) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	infos = make([]RecordInfo, 0, c.size)
+	var now = c.now()
+	var position uint
+	for record := c.top; record != nil; record = c.lowerOf(record) {
+		infos = append(infos, c.recordInfo(record, position, now))
+		position++
+	}
+	return
+}
+
+// Returns the Information about the Record at the Position. The Time is the
+// current Unix Time of the Cache's Clock in Seconds.
+func (c *FixedSizeBubbleCache) recordInfo(
+	record *FixedSizeBubbleCacheRecord,
+	position uint,
+	now uint,
+) (info RecordInfo) {
+	var ttl = c.getTTLOfRecord(record)
+	var expirationTime = c.expirationTimeOfRecord(record)
+
+	info = RecordInfo{
+		UID:            record.UID,
+		LastAccessTime: time.Unix(int64(record.lastAccessTime), 0),
+		CreationTime:   time.Unix(int64(record.creationTime), 0),
+		ExpirationTime: time.Unix(int64(expirationTime), 0),
+		TTL:            ttl,
+		AccessCount:    record.accessCount,
+		Cost:           recordCost,
+		Position:       position,
+		Version:        record.version,
+		IsNegative:     record.isNegative,
+		IsPinned:       record.isPinned,
+	}
+	if now < expirationTime {
+		info.RemainingTTL = expirationTime - now
+	}
+	return
+}
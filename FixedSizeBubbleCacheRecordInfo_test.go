@@ -0,0 +1,103 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/tester"
+)
+
+func Test_GetRecordInfo(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(3, 60)
+	cache.SetNegativeRecordTTL(10)
+	aTest.MustBeNoError(cache.AddRecordWithTTL(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1}, 30))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2}))
+	aTest.MustBeNoError(cache.AddNegativeRecord("n"))
+	var now = uint(time.Now().Unix())
+
+	// Test #1. Ordinary Record.
+	var record, _ = cache.getRecordByUID("a")
+	record.lastAccessTime = now - 10
+	record.creationTime = now - 20
+	var info, err = cache.GetRecordInfo("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(info, RecordInfo{
+		UID:            "a",
+		LastAccessTime: time.Unix(int64(now-10), 0),
+		CreationTime:   time.Unix(int64(now-20), 0),
+		ExpirationTime: time.Unix(int64(now+20), 0),
+		TTL:            30,
+		RemainingTTL:   20,
+		AccessCount:    0,
+		Cost:           1,
+		Position:       2,
+		Version:        1,
+		IsNegative:     false,
+		IsPinned:       false,
+	})
+
+	// Test #2. Requests are counted, the Info does not move the Record.
+	_, err = cache.GetActualRecordDataByUID("b")
+	aTest.MustBeNoError(err)
+	_, err = cache.GetActualRecordDataByUID("b")
+	aTest.MustBeNoError(err)
+	info, err = cache.GetRecordInfo("b")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(info.AccessCount, uint64(2))
+	aTest.MustBeEqual(info.Position, uint(0))
+	aTest.MustBeEqual(info.TTL, uint(60))
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"b", "n", "a"})
+
+	// Test #3. Negative Record.
+	info, err = cache.GetRecordInfo("n")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(info.IsNegative, true)
+	aTest.MustBeEqual(info.TTL, uint(10))
+	aTest.MustBeEqual(info.Position, uint(1))
+
+	// Test #4. Outdated Record is reported with zero remaining TTL.
+	record, _ = cache.getRecordByUID("a")
+	record.lastAccessTime = now - 40
+	info, err = cache.GetRecordInfo("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(info.RemainingTTL, uint(0))
+	aTest.MustBeEqual(cache.RecordUIDExists("a"), true)
+
+	// Test #5. Missing Record.
+	_, err = cache.GetRecordInfo("x")
+	aTest.MustBeAnError(err)
+}
+
+func Test_ListAllRecordInfos(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(3, 60)
+
+	// Test #1. Empty Cache.
+	aTest.MustBeEqual(cache.ListAllRecordInfos(), []RecordInfo{})
+
+	// Test #2. Records from the Top to the Bottom.
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "c", Data: 3}))
+	var infos = cache.ListAllRecordInfos()
+	aTest.MustBeEqual(len(infos), 3)
+	for i, uid := range []FixedSizeBubbleCacheRecordUID{"c", "b", "a"} {
+		aTest.MustBeEqual(infos[i].UID, uid)
+		aTest.MustBeEqual(infos[i].Position, uint(i))
+		aTest.MustBeEqual(infos[i].Cost, uint(1))
+		aTest.MustBeEqual(infos[i].RemainingTTL > 58, true)
+		aTest.MustBeEqual(infos[i].CreationTime.IsZero(), false)
+	}
+
+	// Test #3. An Update keeps the Creation Time.
+	var record, _ = cache.getRecordByUID("a")
+	record.creationTime = 100
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 4}))
+	infos = cache.ListAllRecordInfos()
+	aTest.MustBeEqual(infos[0].UID, "a")
+	aTest.MustBeEqual(infos[0].CreationTime, time.Unix(100, 0))
+	aTest.MustBeEqual(infos[0].Version, uint64(4))
+}
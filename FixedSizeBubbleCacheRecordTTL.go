@@ -0,0 +1,127 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Checks the Record's Parameters and adds it to the Cache with an individual
+// Time-To-Live, measured in Seconds. Zero TTL selects the Cache's Record TTL.
+// As with the Cache's Record TTL, the individual TTL is counted from the last
+// Access to the Record. The Record itself is not changed.
+func (c *FixedSizeBubbleCache) AddRecordWithTTL(
+	record *FixedSizeBubbleCacheRecord,
+	ttl uint,
+) (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// Checks.
+	if record == nil {
+		return errors.New(ErrRecordIsNotSet)
+	}
+	err = record.Check()
+	if err != nil {
+		return
+	}
+
+	// Addition.
+	var recordWithTTL = *record
+	recordWithTTL.ttl = ttl
+	c.addRecord(&recordWithTTL)
+	return
+}
+
+// Sets an individual Time-To-Live of an existing Record, measured in Seconds.
+// Zero TTL selects the Cache's Record TTL. The absolute Expiration Time of
+// the Record is removed. The Record is moved to the Top and its LAT is
+// refreshed. If the Record is outdated, deletes it and returns an Error.
+func (c *FixedSizeBubbleCache) SetRecordTTL(
+	uid FixedSizeBubbleCacheRecordUID,
+	ttl uint,
+) (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.setRecordExpiration(uid, ttl, 0)
+}
+
+// Sets an absolute Expiration Time of an existing Record, which is not
+// extended by Requests. Zero Time removes the absolute Expiration Time, so
+// that the Record expires by the Cache's Record TTL. The individual TTL of the
+// Record is removed. The Record is moved to the Top and its LAT is refreshed.
+// If the Record is outdated, deletes it and returns an Error.
+func (c *FixedSizeBubbleCache) SetRecordExpirationTime(
+	uid FixedSizeBubbleCacheRecordUID,
+	expirationTime time.Time,
+) (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.setRecordExpiration(uid, 0, unixTimeOfExpiration(expirationTime))
+}
+
+// Sets the individual TTL and the absolute Expiration Time of an existing
+// Record.
+func (c *FixedSizeBubbleCache) setRecordExpiration(
+	uid FixedSizeBubbleCacheRecordUID,
+	ttl uint,
+	expirationTime uint,
+) (err error) {
+	// Get the Record.
+	var record *FixedSizeBubbleCacheRecord
+	record, err = c.getRecordByUID(uid)
+	if err != nil {
+		return
+	}
+
+	// Check the TTL. Is the Record Outdated ?
+	if !c.isRecordActual(record) {
+		err = c.deleteRecord(record, true)
+		if err != nil {
+			return
+		}
+		c.statistics.Expirations++
+		err = fmt.Errorf(ErrfRecordWithUidIsOutdated, uid)
+		return
+	}
+
+	// Move the Record to the Top.
+	if record != c.top {
+		c.moveExistingRecordToTop(record)
+	}
+	c.top.ttl = ttl
+	c.top.expirationTime = expirationTime
+	c.top.lastAccessTime = c.now()
+	c.journalRecordTouched(c.top)
+	return
+}
+
+// Returns the remaining Time-To-Live of an actual Record, measured in Seconds.
+// The Record is neither moved nor refreshed. If the Record is outdated,
+// returns an Error.
+func (c *FixedSizeBubbleCache) GetRecordRemainingTTL(
+	uid FixedSizeBubbleCacheRecordUID,
+) (remainingTTL uint, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// Get the Record.
+	var record *FixedSizeBubbleCacheRecord
+	record, err = c.getRecordByUID(uid)
+	if err != nil {
+		return
+	}
+
+	var now = c.now()
+	var expirationTime = c.expirationTimeOfRecord(record)
+	if now >= expirationTime {
+		err = fmt.Errorf(ErrfRecordWithUidIsOutdated, uid)
+		return
+	}
+	remainingTTL = expirationTime - now
+	return
+}
@@ -0,0 +1,147 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/tester"
+)
+
+func Test_AddRecordWithTTL(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(2, 60)
+	var err error
+
+	// Test #1. Null Record.
+	err = cache.AddRecordWithTTL(nil, 5)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrRecordIsNotSet)
+
+	// Test #2. Normal.
+	err = cache.AddRecordWithTTL(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1}, 5)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(cache.getTTLOfRecord(cache.top), uint(5))
+	cache.top.lastAccessTime -= 5
+	var isActive bool
+	isActive, err = cache.IsRecordUIDActive("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(isActive, false)
+
+	// Test #3. An Update without TTL restores the Cache's Record TTL.
+	err = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 2})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(cache.getTTLOfRecord(cache.top), uint(60))
+
+	// Test #4. The Record of the Caller is not changed.
+	var record = &FixedSizeBubbleCacheRecord{UID: "b", Data: 3}
+	err = cache.AddRecordWithTTL(record, 7)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(record.ttl, uint(0))
+	aTest.MustBeEqual(cache.getTTLOfRecord(cache.top), uint(7))
+}
+
+func Test_SetRecordExpirationTime(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(2, 60)
+	var now = time.Unix(1000000000, 0)
+	cache.SetEarlyExpiration(&FixedSizeBubbleCacheEarlyExpiration{
+		Beta:   1,
+		Random: func() float64 { return 1 },
+		Clock:  func() time.Time { return now },
+	})
+	var err error
+
+	// Test #1. Missing Record.
+	err = cache.SetRecordExpirationTime("a", now.Add(time.Second*10))
+	aTest.MustBeAnError(err)
+
+	// Test #2. Requests do not extend the absolute Expiration Time.
+	var record = &FixedSizeBubbleCacheRecord{UID: "a", Data: 1}
+	record.SetExpirationTime(now.Add(time.Second * 100))
+	aTest.MustBeNoError(cache.AddRecordWithTTL(record, 5))
+	err = cache.SetRecordExpirationTime("a", now.Add(time.Second*10))
+	aTest.MustBeNoError(err)
+	now = now.Add(time.Second * 9)
+	var data interface{}
+	data, err = cache.GetActualRecordDataByUID("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, 1)
+	var remainingTTL uint
+	remainingTTL, err = cache.GetRecordRemainingTTL("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(remainingTTL, uint(1))
+	now = now.Add(time.Second)
+	_, err = cache.GetActualRecordDataByUID("a")
+	aTest.MustBeAnError(err)
+
+	// Test #3. The TTL removes the absolute Expiration Time.
+	record = &FixedSizeBubbleCacheRecord{UID: "b", Data: 2}
+	record.SetExpirationTime(now.Add(time.Second * 10))
+	aTest.MustBeNoError(cache.AddRecord(record))
+	aTest.MustBeNoError(cache.SetRecordTTL("b", 30))
+	now = now.Add(time.Second * 20)
+	_, err = cache.GetActualRecordDataByUID("b")
+	aTest.MustBeNoError(err)
+
+	// Test #4. Zero Time selects the Cache's Record TTL.
+	aTest.MustBeNoError(cache.SetRecordExpirationTime("b", time.Time{}))
+	remainingTTL, err = cache.GetRecordRemainingTTL("b")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(remainingTTL, uint(60))
+}
+
+func Test_SetRecordTTL(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(2, 60)
+	var err error
+
+	// Test #1. Missing Record.
+	err = cache.SetRecordTTL("a", 5)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfRecordWithUidIsNotFound, "a"))
+
+	// Test #2. Normal.
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1})
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2})
+	cache.bottom.lastAccessTime -= 30
+	err = cache.SetRecordTTL("a", 5)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(cache.top.UID, "a")
+	aTest.MustBeEqual(cache.getTTLOfRecord(cache.top), uint(5))
+	aTest.MustBeEqual(cache.isRecordActual(cache.top), true)
+
+	// Test #3. Outdated Record.
+	cache.top.lastAccessTime -= 5
+	err = cache.SetRecordTTL("a", 60)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfRecordWithUidIsOutdated, "a"))
+	aTest.MustBeEqual(cache.RecordUIDExists("a"), false)
+	aTest.MustBeEqual(cache.Stats().Expirations, uint64(1))
+}
+
+func Test_GetRecordRemainingTTL(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(2, 60)
+	var remainingTTL uint
+	var err error
+
+	// Test #1. Missing Record.
+	_, err = cache.GetRecordRemainingTTL("a")
+	aTest.MustBeAnError(err)
+
+	// Test #2. Normal.
+	_ = cache.AddRecordWithTTL(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1}, 10)
+	cache.top.lastAccessTime -= 4
+	remainingTTL, err = cache.GetRecordRemainingTTL("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(remainingTTL, uint(6))
+
+	// Test #3. Outdated Record.
+	cache.top.lastAccessTime -= 6
+	_, err = cache.GetRecordRemainingTTL("a")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfRecordWithUidIsOutdated, "a"))
+}
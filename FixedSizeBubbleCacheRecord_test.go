@@ -31,7 +31,17 @@ func Test_Check(t *testing.T) {
 	aTest.MustBeAnError(err)
 	aTest.MustBeEqual(err.Error(), ErrUIDIsEmpty)
 
-	// Test #3. Normal.
+	// Test #3. Record of a Cache.
+	record = &FixedSizeBubbleCacheRecord{
+		UID:      "123",
+		Data:     123,
+		isStored: true,
+	}
+	err = record.Check()
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrRecordIsStoredInCache)
+
+	// Test #4. Normal.
 	record = &FixedSizeBubbleCacheRecord{
 		UID:  "123",
 		Data: 123,
@@ -84,14 +94,28 @@ func Test_updateLATWithCurrentTime(t *testing.T) {
 	aTest.MustBeEqual(record.lastAccessTime-tsNow, uint(1))
 }
 
-func Test_isActual(t *testing.T) {
+func Test_unlinkedCopy(t *testing.T) {
 	var aTest *tester.Test = tester.New(t)
-	var record *FixedSizeBubbleCacheRecord
+	var cache = NewFixedSizeBubbleCache(3, 60)
+	var expirationTime = time.Now().Add(time.Hour)
+	var record = &FixedSizeBubbleCacheRecord{UID: "a", Data: 1}
+	record.SetExpirationTime(expirationTime)
+	aTest.MustBeNoError(cache.AddRecord(record))
+	aTest.MustBeNoError(cache.Pin("a"))
 
-	// Test #1. Positive.
-	record = &FixedSizeBubbleCacheRecord{}
-	record.UpdateLAT()
-	time.Sleep(time.Second * 2)
-	aTest.MustBeEqual(record.isActual(3), true)
-	aTest.MustBeEqual(record.isActual(1), false)
+	// Test #1. Copies keep the absolute Expiration Time and the Pin.
+	var records = cache.ListAllRecords()
+	aTest.MustBeEqual(records[0].expirationTime, uint(expirationTime.Unix()))
+	aTest.MustBeEqual(records[0].isPinned, true)
+
+	// Test #2. Round Trip of a popped Record.
+	aTest.MustBeNoError(cache.Unpin("a"))
+	var popped, err = cache.PopBottom()
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(popped.expirationTime, uint(expirationTime.Unix()))
+	aTest.MustBeNoError(cache.AddRecord(popped))
+	var info RecordInfo
+	info, err = cache.GetRecordInfo("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(info.ExpirationTime.Unix(), expirationTime.Unix())
 }
@@ -0,0 +1,528 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// Snapshot Format.
+//
+// A Snapshot is a binary Stream. All Integers are written in the Big-Endian
+// Byte Order. Checksums are CRC-32 (IEEE) Sums.
+//
+// The Stream starts with a Header Block:
+//
+//	Magic (4 Bytes, 'FSBC'),
+//	Format Version (uint16),
+//	Capacity of the Cache (uint64),
+//	Record TTL of the Cache (uint64),
+//	Records Count (uint64),
+//	Checksum of all the previous Header Bytes (uint32).
+//
+// The Header is followed by the Records Blocks, in the Order from the Top to
+// the Bottom of the Cache. Each Record Block is:
+//
+//	Payload Size (uint32),
+//	Payload,
+//	Checksum of the Payload Size and of the Payload (uint32).
+//
+// The Payload of a Record is:
+//
+//	Flags (1 Byte; Bit #0 is set for negative Records, Bit #1 is set for
+//	Records with an individual TTL, Bit #2 is set for pinned Records, Bit #3
+//	is set for Records with an absolute Expiration Time, Bit #4 is set for
+//	Records whose Update Time differs from their Last Access Time),
+//	Last Access Time (uint64),
+//	Individual TTL (uint64; only when Bit #1 of the Flags is set),
+//	Absolute Expiration Time (uint64; only when Bit #3 of the Flags is set),
+//	Update Time (uint64; only when Bit #4 of the Flags is set),
+//	UID Size (uint32), UID Bytes,
+//	Data Size (uint32), Data Bytes encoded by the Cache's Codec.
+//
+// Readers of newer Versions must be able to read all older Versions.
+// Version 2 has added individual TTLs of Records. Version 3 has added the Flag
+// of pinned Records. Version 4 has added absolute Expiration Times of Records.
+// Version 5 has added Update Times, which fix the Expiration of negative
+// Records; older Versions use the Last Access Time instead.
+const (
+	SnapshotMagic         = "FSBC"
+	SnapshotFormatVersion = uint16(5)
+
+	// Maximum Size of a Record's Payload accepted by the Reader.
+	SnapshotRecordPayloadSizeLimit = 256 * 1024 * 1024
+)
+
+// Sizes of Snapshot Parts.
+const (
+	snapshotHeaderSize           = 4 + 2 + 8 + 8 + 8
+	snapshotChecksumSize         = 4
+	snapshotPayloadSizeSize      = 4
+	snapshotRecordFixedSize      = 1 + 8 + 4 + 4
+	snapshotRecordFlagIsNegative = byte(1)
+	snapshotRecordFlagHasTTL     = byte(2)
+	snapshotRecordFlagIsPinned   = byte(4)
+	snapshotRecordFlagHasExpiry  = byte(8)
+	snapshotRecordFlagHasUpdate  = byte(16)
+	snapshotRecordTTLSize        = 8
+	snapshotRecordExpirySize     = 8
+	snapshotRecordUpdateSize     = 8
+)
+
+// Header of a Snapshot.
+type snapshotHeader struct {
+	Version      uint16
+	Capacity     uint64
+	RecordTTL    uint64
+	RecordsCount uint64
+}
+
+// Image of the Cache for a Snapshot: the Header and the Copies of the Records
+// in the Order from the Top to the Bottom of the Cache.
+type snapshotImage struct {
+	header  snapshotHeader
+	records []*FixedSizeBubbleCacheRecord
+	codec   Codec
+}
+
+// Writes a Snapshot of all Records of the Cache.
+//
+// The Snapshot stores the UID, the Data encoded by the Cache's Codec, and the
+// Last Access Time of each Record, in the Order from the Top to the Bottom of
+// the Cache. The Records are copied while the Cache is locked, they are
+// encoded and written after the Cache is unlocked, so that a slow Writer does
+// not block the Cache. The Data itself is not copied, so Data of reference
+// Types must not be modified meanwhile.
+func (c *FixedSizeBubbleCache) WriteSnapshot(
+	w io.Writer,
+) (err error) {
+	c.lock.Lock()
+	var image = c.takeSnapshotImage()
+	c.lock.Unlock()
+
+	return image.write(w)
+}
+
+// Takes the Image of the Cache for a Snapshot. The Cache must be locked.
+func (c *FixedSizeBubbleCache) takeSnapshotImage() (image snapshotImage) {
+	image = snapshotImage{
+		header: snapshotHeader{
+			Version:      SnapshotFormatVersion,
+			Capacity:     uint64(c.capacity),
+			RecordTTL:    uint64(c.recordTTL),
+			RecordsCount: uint64(c.size),
+		},
+		records: make([]*FixedSizeBubbleCacheRecord, 0, c.size),
+		codec:   c.getCodec(),
+	}
+	for record := c.top; record != nil; record = c.lowerOf(record) {
+		image.records = append(image.records, record.unlinkedCopy())
+	}
+	return
+}
+
+// Writes a Snapshot of the Image.
+func (image snapshotImage) write(
+	w io.Writer,
+) (err error) {
+	var bw = bufio.NewWriter(w)
+	err = writeSnapshotHeader(bw, image.header)
+	if err != nil {
+		return
+	}
+
+	var payload []byte
+	for _, record := range image.records {
+		payload, err = encodeSnapshotRecordPayload(payload[:0], record, image.codec)
+		if err != nil {
+			return
+		}
+
+		err = writeSnapshotBlock(bw, payload)
+		if err != nil {
+			return
+		}
+	}
+
+	return bw.Flush()
+}
+
+// Writes the Header Block of a Snapshot.
+func writeSnapshotHeader(
+	w io.Writer,
+	header snapshotHeader,
+) (err error) {
+	var buf = make([]byte, snapshotHeaderSize+snapshotChecksumSize)
+	copy(buf[0:4], SnapshotMagic)
+	binary.BigEndian.PutUint16(buf[4:6], header.Version)
+	binary.BigEndian.PutUint64(buf[6:14], header.Capacity)
+	binary.BigEndian.PutUint64(buf[14:22], header.RecordTTL)
+	binary.BigEndian.PutUint64(buf[22:30], header.RecordsCount)
+	binary.BigEndian.PutUint32(
+		buf[snapshotHeaderSize:],
+		crc32.ChecksumIEEE(buf[:snapshotHeaderSize]),
+	)
+
+	_, err = w.Write(buf)
+	return
+}
+
+// Appends the Payload of a Record to the Buffer.
+// The Data of the Record is encoded by the Codec.
+func encodeSnapshotRecordPayload(
+	buf []byte,
+	record *FixedSizeBubbleCacheRecord,
+	codec Codec,
+) (payload []byte, err error) {
+	var data []byte
+	if !record.isNegative {
+		data, err = codec.Encode(record.Data)
+		if err != nil {
+			return
+		}
+	}
+
+	return appendSnapshotRecordPayload(buf, record, data)
+}
+
+// Appends the Payload of a Record with the encoded Data to the Buffer.
+func appendSnapshotRecordPayload(
+	buf []byte,
+	record *FixedSizeBubbleCacheRecord,
+	data []byte,
+) (payload []byte, err error) {
+	var flags byte
+	if record.isNegative {
+		flags |= snapshotRecordFlagIsNegative
+	}
+	if record.ttl > 0 {
+		flags |= snapshotRecordFlagHasTTL
+	}
+	if record.isPinned {
+		flags |= snapshotRecordFlagIsPinned
+	}
+	if record.expirationTime > 0 {
+		flags |= snapshotRecordFlagHasExpiry
+	}
+	if record.updateTime != record.lastAccessTime {
+		flags |= snapshotRecordFlagHasUpdate
+	}
+	var fixed [snapshotRecordFixedSize]byte
+	payload = append(buf, flags)
+	binary.BigEndian.PutUint64(fixed[:8], uint64(record.lastAccessTime))
+	payload = append(payload, fixed[:8]...)
+	if record.ttl > 0 {
+		binary.BigEndian.PutUint64(fixed[:8], uint64(record.ttl))
+		payload = append(payload, fixed[:8]...)
+	}
+	if record.expirationTime > 0 {
+		binary.BigEndian.PutUint64(fixed[:8], uint64(record.expirationTime))
+		payload = append(payload, fixed[:8]...)
+	}
+	if record.updateTime != record.lastAccessTime {
+		binary.BigEndian.PutUint64(fixed[:8], uint64(record.updateTime))
+		payload = append(payload, fixed[:8]...)
+	}
+	binary.BigEndian.PutUint32(fixed[:4], uint32(len(record.UID)))
+	payload = append(payload, fixed[:4]...)
+	payload = append(payload, record.UID...)
+	binary.BigEndian.PutUint32(fixed[:4], uint32(len(data)))
+	payload = append(payload, fixed[:4]...)
+	payload = append(payload, data...)
+
+	if len(payload)-len(buf) > SnapshotRecordPayloadSizeLimit {
+		err = fmt.Errorf(ErrfSnapshotRecordIsTooLarge, record.UID)
+		return
+	}
+	return
+}
+
+// Writes a Block: the Payload Size, the Payload and the Checksum.
+func writeSnapshotBlock(
+	w io.Writer,
+	payload []byte,
+) (err error) {
+	var sizeBuf [snapshotPayloadSizeSize]byte
+	binary.BigEndian.PutUint32(sizeBuf[:], uint32(len(payload)))
+	var checksum = crc32.Update(
+		crc32.ChecksumIEEE(sizeBuf[:]),
+		crc32.IEEETable,
+		payload,
+	)
+	var checksumBuf [snapshotChecksumSize]byte
+	binary.BigEndian.PutUint32(checksumBuf[:], checksum)
+
+	_, err = w.Write(sizeBuf[:])
+	if err != nil {
+		return
+	}
+	_, err = w.Write(payload)
+	if err != nil {
+		return
+	}
+	_, err = w.Write(checksumBuf[:])
+	return
+}
+
+// Reads a Snapshot and replaces all Records of the Cache with the Records of
+// the Snapshot.
+//
+// The Order of Records and their Last Access Times are preserved, so the
+// Records keep their remaining TTL. Records which became outdated are skipped.
+// Pinned Records are pinned again while the Limit of pinned Records allows it.
+// If the Snapshot has more Records than the Cache's Capacity, the Records
+// closest to the Bottom are skipped. If the Snapshot is truncated or broken,
+// the Cache is not changed.
+func (c *FixedSizeBubbleCache) ReadSnapshot(
+	r io.Reader,
+) (err error) {
+	var records []*FixedSizeBubbleCacheRecord
+	records, err = c.readSnapshot(r, c.GetCodec())
+	if err != nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.restoreRecords(records)
+}
+
+// Reads all Records of a Snapshot, from the Top to the Bottom.
+// The Cache is neither locked nor changed.
+func (c *FixedSizeBubbleCache) readSnapshot(
+	r io.Reader,
+	codec Codec,
+) (records []*FixedSizeBubbleCacheRecord, err error) {
+	var br = bufio.NewReader(r)
+	var header snapshotHeader
+	header, err = readSnapshotHeader(br)
+	if err != nil {
+		return
+	}
+
+	// The Header is not trusted to allocate Memory.
+	var sizeHint = header.RecordsCount
+	if sizeHint > uint64(c.capacity) {
+		sizeHint = uint64(c.capacity)
+	}
+
+	records = make([]*FixedSizeBubbleCacheRecord, 0, sizeHint)
+	var uids = make(map[FixedSizeBubbleCacheRecordUID]bool, sizeHint)
+	var record *FixedSizeBubbleCacheRecord
+	var data []byte
+	var i uint64
+	for i = 0; i < header.RecordsCount; i++ {
+		record, data, err = readSnapshotRecord(br)
+		if err != nil {
+			return nil, fmt.Errorf(ErrfSnapshotRecordIsBroken, i, err)
+		}
+		err = decodeSnapshotRecordData(record, data, codec)
+		if err != nil {
+			return nil, fmt.Errorf(ErrfSnapshotRecordIsBroken, i, err)
+		}
+		if uids[record.UID] {
+			return nil, errors.New(ErrSnapshotHasDuplicateUIDs)
+		}
+		uids[record.UID] = true
+
+		records = append(records, record)
+	}
+	return
+}
+
+// Decodes the Data of a parsed Record and checks the Record.
+func decodeSnapshotRecordData(
+	record *FixedSizeBubbleCacheRecord,
+	data []byte,
+	codec Codec,
+) (err error) {
+	if !record.isNegative {
+		record.Data, err = codec.Decode(data)
+		if err != nil {
+			return
+		}
+	}
+	return record.Check()
+}
+
+// Reads and verifies the Header Block of a Snapshot.
+func readSnapshotHeader(
+	r io.Reader,
+) (header snapshotHeader, err error) {
+	var buf = make([]byte, snapshotHeaderSize+snapshotChecksumSize)
+	_, err = io.ReadFull(r, buf)
+	if err != nil {
+		err = fmt.Errorf(ErrfSnapshotHeaderIsBroken, err)
+		return
+	}
+
+	if string(buf[0:4]) != SnapshotMagic {
+		err = errors.New(ErrSnapshotMagicIsWrong)
+		return
+	}
+	if crc32.ChecksumIEEE(buf[:snapshotHeaderSize]) !=
+		binary.BigEndian.Uint32(buf[snapshotHeaderSize:]) {
+		err = fmt.Errorf(ErrfSnapshotHeaderIsBroken, ErrChecksumMismatch)
+		return
+	}
+
+	header.Version = binary.BigEndian.Uint16(buf[4:6])
+	if (header.Version == 0) || (header.Version > SnapshotFormatVersion) {
+		err = fmt.Errorf(ErrfSnapshotVersionIsNotSupported, header.Version)
+		return
+	}
+	header.Capacity = binary.BigEndian.Uint64(buf[6:14])
+	header.RecordTTL = binary.BigEndian.Uint64(buf[14:22])
+	header.RecordsCount = binary.BigEndian.Uint64(buf[22:30])
+	return
+}
+
+// Reads and verifies a Block. Returns its Payload.
+func readSnapshotBlock(
+	r io.Reader,
+	minPayloadSize uint32,
+) (payload []byte, err error) {
+	var sizeBuf [snapshotPayloadSizeSize]byte
+	_, err = io.ReadFull(r, sizeBuf[:])
+	if err != nil {
+		return
+	}
+	var payloadSize = binary.BigEndian.Uint32(sizeBuf[:])
+	if (payloadSize < minPayloadSize) ||
+		(payloadSize > SnapshotRecordPayloadSizeLimit) {
+		err = errors.New(ErrSnapshotRecordSizeIsWrong)
+		return
+	}
+
+	var buf = make([]byte, payloadSize+snapshotChecksumSize)
+	_, err = io.ReadFull(r, buf)
+	if err != nil {
+		return
+	}
+	var checksum = crc32.Update(
+		crc32.ChecksumIEEE(sizeBuf[:]),
+		crc32.IEEETable,
+		buf[:payloadSize],
+	)
+	if checksum != binary.BigEndian.Uint32(buf[payloadSize:]) {
+		err = errors.New(ErrChecksumMismatch)
+		return
+	}
+
+	payload = buf[:payloadSize]
+	return
+}
+
+// Reads and verifies a Record Block of a Snapshot.
+// Returns the Record without Data and the encoded Data.
+func readSnapshotRecord(
+	r io.Reader,
+) (record *FixedSizeBubbleCacheRecord, data []byte, err error) {
+	var payload []byte
+	payload, err = readSnapshotBlock(r, snapshotRecordFixedSize)
+	if err != nil {
+		return
+	}
+
+	return parseSnapshotRecordPayload(payload)
+}
+
+// Parses the verified Payload of a Record.
+// Returns the Record without Data and the encoded Data.
+func parseSnapshotRecordPayload(
+	p []byte,
+) (record *FixedSizeBubbleCacheRecord, data []byte, err error) {
+	if len(p) < snapshotRecordFixedSize {
+		err = errors.New(ErrSnapshotRecordSizeIsWrong)
+		return
+	}
+
+	var flags = p[0]
+	record = &FixedSizeBubbleCacheRecord{
+		isNegative:     flags&snapshotRecordFlagIsNegative != 0,
+		isPinned:       flags&snapshotRecordFlagIsPinned != 0,
+		lastAccessTime: uint(binary.BigEndian.Uint64(p[1:9])),
+	}
+	// The Time of the last Change of the Data is stored when it differs.
+	record.updateTime = record.lastAccessTime
+	p = p[9:]
+	if flags&snapshotRecordFlagHasTTL != 0 {
+		if len(p) < snapshotRecordTTLSize+4+4 {
+			err = errors.New(ErrSnapshotRecordSizeIsWrong)
+			return
+		}
+		record.ttl = uint(binary.BigEndian.Uint64(p[0:8]))
+		p = p[snapshotRecordTTLSize:]
+	}
+	if flags&snapshotRecordFlagHasExpiry != 0 {
+		if len(p) < snapshotRecordExpirySize+4+4 {
+			err = errors.New(ErrSnapshotRecordSizeIsWrong)
+			return
+		}
+		record.expirationTime = uint(binary.BigEndian.Uint64(p[0:8]))
+		p = p[snapshotRecordExpirySize:]
+	}
+	if flags&snapshotRecordFlagHasUpdate != 0 {
+		if len(p) < snapshotRecordUpdateSize+4+4 {
+			err = errors.New(ErrSnapshotRecordSizeIsWrong)
+			return
+		}
+		record.updateTime = uint(binary.BigEndian.Uint64(p[0:8]))
+		p = p[snapshotRecordUpdateSize:]
+	}
+	var uidSize = binary.BigEndian.Uint32(p[0:4])
+	p = p[4:]
+	if uint64(uidSize)+4 > uint64(len(p)) {
+		err = errors.New(ErrSnapshotRecordSizeIsWrong)
+		return
+	}
+	record.UID = string(p[:uidSize])
+	p = p[uidSize:]
+	var dataSize = binary.BigEndian.Uint32(p[0:4])
+	p = p[4:]
+	if uint64(dataSize) != uint64(len(p)) {
+		err = errors.New(ErrSnapshotRecordSizeIsWrong)
+		return
+	}
+	data = p
+	return
+}
+
+// Replaces all Records of the Cache with the Records listed from the Top to
+// the Bottom. UIDs of the Records must be unique.
+func (c *FixedSizeBubbleCache) restoreRecords(
+	records []*FixedSizeBubbleCacheRecord,
+) (err error) {
+	err = c.clear()
+	if err != nil {
+		return
+	}
+
+	for _, restoredRecord := range records {
+		if c.size == c.capacity {
+			break
+		}
+		if !c.isRecordActual(restoredRecord) {
+			continue
+		}
+		var record = c.storeRecord(restoredRecord)
+		record.creationTime = record.lastAccessTime
+		c.linkBottomRecord(record)
+		c.recordsByUID.insert(record.UID, record.index)
+		c.countNegativeRecord(record, 1)
+		c.size++
+		c.restorePin(record, restoredRecord.isPinned)
+	}
+
+	// The Journal receives the Records as if they were added to the Top.
+	for record := c.bottom; record != nil; record = c.upperOf(record) {
+		c.journalRecordAdded(record)
+	}
+	return
+}
@@ -0,0 +1,205 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/tester"
+)
+
+func Test_WriteSnapshot_ReadSnapshot(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var err error
+	var buffer bytes.Buffer
+
+	var source = NewFixedSizeBubbleCache(4, 60)
+	source.SetNegativeRecordTTL(60)
+	_ = source.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1})
+	_ = source.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: "two"})
+	_ = source.AddNegativeRecord("c")
+	_ = source.AddRecord(&FixedSizeBubbleCacheRecord{UID: "d", Data: 4.5})
+	_, _ = source.GetActualRecordDataByUID("a")
+	var lat = source.top.lastAccessTime - 7
+	source.top.lastAccessTime = lat
+
+	// Test #1. Full Restore.
+	err = source.WriteSnapshot(&buffer)
+	aTest.MustBeNoError(err)
+	var snapshot = buffer.Bytes()
+	var target = NewFixedSizeBubbleCache(4, 60)
+	target.SetNegativeRecordTTL(60)
+	_ = target.AddRecord(&FixedSizeBubbleCacheRecord{UID: "x", Data: 0})
+	err = target.ReadSnapshot(bytes.NewReader(snapshot))
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(target.ListAllRecordValues(), []interface{}{1, 4.5, nil, "two"})
+	aTest.MustBeEqual(target.top.lastAccessTime, lat)
+	aTest.MustBeEqual(target.RecordUIDExists("x"), false)
+	aTest.MustBeEqual(target.isIntegral(), true)
+	aTest.MustBeEqual(target.Stats().NegativeRecords, uint(1))
+
+	// Test #2. Smaller Capacity keeps the Top Records.
+	target = NewFixedSizeBubbleCache(2, 60)
+	err = target.ReadSnapshot(bytes.NewReader(snapshot))
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(target.ListAllRecordValues(), []interface{}{1, 4.5})
+	aTest.MustBeEqual(target.isIntegral(), true)
+
+	// Test #3. Outdated Records are skipped.
+	target = NewFixedSizeBubbleCache(4, 5)
+	target.SetNegativeRecordTTL(60)
+	err = target.ReadSnapshot(bytes.NewReader(snapshot))
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(target.ListAllRecordValues(), []interface{}{4.5, nil, "two"})
+
+	// Test #4. Truncated Snapshot does not change the Cache.
+	target = NewFixedSizeBubbleCache(4, 60)
+	_ = target.AddRecord(&FixedSizeBubbleCacheRecord{UID: "x", Data: 0})
+	err = target.ReadSnapshot(bytes.NewReader(snapshot[:len(snapshot)-3]))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(target.ListAllRecordValues(), []interface{}{0})
+
+	// Test #5. Individual TTLs are preserved.
+	source = NewFixedSizeBubbleCache(2, 60)
+	_ = source.AddRecordWithTTL(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1}, 600)
+	_ = source.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2})
+	buffer.Reset()
+	err = source.WriteSnapshot(&buffer)
+	aTest.MustBeNoError(err)
+	target = NewFixedSizeBubbleCache(2, 60)
+	err = target.ReadSnapshot(&buffer)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(target.bottom.ttl, uint(600))
+	aTest.MustBeEqual(target.top.ttl, uint(0))
+
+	// Test #6. Absolute Expiration Times are preserved.
+	source = NewFixedSizeBubbleCache(2, 60)
+	var record = &FixedSizeBubbleCacheRecord{UID: "a", Data: 1}
+	record.SetExpirationTime(time.Now().Add(time.Hour))
+	_ = source.AddRecordWithTTL(record, 600)
+	buffer.Reset()
+	err = source.WriteSnapshot(&buffer)
+	aTest.MustBeNoError(err)
+	target = NewFixedSizeBubbleCache(2, 60)
+	err = target.ReadSnapshot(&buffer)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(target.top.expirationTime, record.expirationTime)
+	aTest.MustBeEqual(target.top.ttl, uint(600))
+
+	// Test #7. Negative Records keep their fixed Expiration.
+	source = NewFixedSizeBubbleCache(2, 60)
+	source.SetNegativeRecordTTL(60)
+	_ = source.AddNegativeRecord("n")
+	_ = source.AddNegativeRecord("o")
+	var now = uint(time.Now().Unix())
+	source.top.updateTime = now - 50
+	source.bottom.updateTime = now - 70
+	buffer.Reset()
+	err = source.WriteSnapshot(&buffer)
+	aTest.MustBeNoError(err)
+	target = NewFixedSizeBubbleCache(2, 60)
+	target.SetNegativeRecordTTL(60)
+	err = target.ReadSnapshot(&buffer)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(target.size, uint(1))
+	aTest.MustBeEqual(target.top.UID, "o")
+	aTest.MustBeEqual(target.top.updateTime, now-50)
+}
+
+// A Writer which changes the Cache while the Snapshot is written.
+type cacheChangingWriter struct {
+	cache  *FixedSizeBubbleCache
+	buffer bytes.Buffer
+}
+
+func (w *cacheChangingWriter) Write(p []byte) (n int, err error) {
+	err = w.cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "w", Data: 0})
+	if err != nil {
+		return
+	}
+	return w.buffer.Write(p)
+}
+
+func Test_WriteSnapshot_Unlocked(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var err error
+
+	var source = NewFixedSizeBubbleCache(4, 60)
+	_ = source.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1})
+	_ = source.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2})
+
+	// Test #1. The Writer is used while the Cache is unlocked, the Snapshot
+	// has the Records which existed when it was started.
+	var w = &cacheChangingWriter{cache: source}
+	err = source.WriteSnapshot(w)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(source.RecordUIDExists("w"), true)
+	var target = NewFixedSizeBubbleCache(4, 60)
+	err = target.ReadSnapshot(&w.buffer)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(target.ListAllRecordValues(), []interface{}{2, 1})
+}
+
+func Test_ReadSnapshot_BrokenSnapshot(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var err error
+	var buffer bytes.Buffer
+
+	var source = NewFixedSizeBubbleCache(4, 60)
+	_ = source.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1})
+	_ = source.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2})
+	err = source.WriteSnapshot(&buffer)
+	aTest.MustBeNoError(err)
+	var snapshot = buffer.Bytes()
+	var broken []byte
+	var target = NewFixedSizeBubbleCache(4, 60)
+	_ = target.AddRecord(&FixedSizeBubbleCacheRecord{UID: "x", Data: 0})
+
+	// Test #1. Header.
+	aTest.MustBeEqual(string(snapshot[:4]), SnapshotMagic)
+	var header snapshotHeader
+	header, err = readSnapshotHeader(bytes.NewReader(snapshot))
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(header, snapshotHeader{
+		Version:      SnapshotFormatVersion,
+		Capacity:     4,
+		RecordTTL:    60,
+		RecordsCount: 2,
+	})
+
+	// Test #2. Wrong Magic.
+	broken = append([]byte{}, snapshot...)
+	broken[0] = 'X'
+	err = target.ReadSnapshot(bytes.NewReader(broken))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrSnapshotMagicIsWrong)
+
+	// Test #3. Broken Header.
+	broken = append([]byte{}, snapshot...)
+	broken[10]++
+	err = target.ReadSnapshot(bytes.NewReader(broken))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfSnapshotHeaderIsBroken, ErrChecksumMismatch))
+
+	// Test #4. Unsupported Version.
+	var headerBuffer bytes.Buffer
+	_ = writeSnapshotHeader(&headerBuffer, snapshotHeader{Version: SnapshotFormatVersion + 1})
+	err = target.ReadSnapshot(&headerBuffer)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfSnapshotVersionIsNotSupported, SnapshotFormatVersion+1))
+
+	// Test #5. Broken Record.
+	broken = append([]byte{}, snapshot...)
+	broken[len(broken)-6]++
+	err = target.ReadSnapshot(bytes.NewReader(broken))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfSnapshotRecordIsBroken, 1, ErrChecksumMismatch))
+
+	// Test #6. Truncated Snapshot.
+	err = target.ReadSnapshot(bytes.NewReader(snapshot[:len(snapshot)-1]))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(target.ListAllRecordValues(), []interface{}{0})
+}
@@ -0,0 +1,38 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+// Statistics of the Cache's Usage.
+type FixedSizeBubbleCacheStatistics struct {
+
+	// Count of Requests which have found an actual Record.
+	Hits uint64
+
+	// Count of Requests which have found an actual negative Record.
+	NegativeHits uint64
+
+	// Count of Requests which have not found an actual Record.
+	Misses uint64
+
+	// Count of Records deleted because they were outdated.
+	Expirations uint64
+
+	// Count of Records removed from the Bottom of the full Cache.
+	Evictions uint64
+
+	// Current Count of Records in the Cache.
+	Records uint
+
+	// Current Count of negative Records in the Cache.
+	NegativeRecords uint
+}
+
+// Returns the Statistics of the Cache's Usage.
+func (c *FixedSizeBubbleCache) Stats() (stats FixedSizeBubbleCacheStatistics) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	stats = c.statistics
+	stats.Records = c.size
+	return
+}
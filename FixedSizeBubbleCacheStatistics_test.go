@@ -0,0 +1,34 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"testing"
+
+	"github.com/vault-thirteen/tester"
+)
+
+func Test_Stats(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(2, 60)
+	var stats FixedSizeBubbleCacheStatistics
+
+	// Test #1. Empty Cache.
+	stats = cache.Stats()
+	aTest.MustBeEqual(stats, FixedSizeBubbleCacheStatistics{})
+
+	// Test #2. Hits, Misses, Expirations and Evictions.
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1})
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2})
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "c", Data: 3})
+	_, _ = cache.GetActualRecordDataByUID("b")
+	_, _ = cache.GetActualRecordDataByUID("a")
+	cache.top.lastAccessTime = 0
+	_, _ = cache.GetActualRecordDataByUID("b")
+	stats = cache.Stats()
+	aTest.MustBeEqual(stats.Hits, uint64(1))
+	aTest.MustBeEqual(stats.Misses, uint64(2))
+	aTest.MustBeEqual(stats.Expirations, uint64(1))
+	aTest.MustBeEqual(stats.Evictions, uint64(1))
+	aTest.MustBeEqual(stats.Records, uint(1))
+}
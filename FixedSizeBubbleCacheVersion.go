@@ -0,0 +1,184 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"errors"
+	"fmt"
+)
+
+// A Function which calculates new Data of a Record from its current Data.
+//
+// The Function is called while the Cache is locked, so it must not call any
+// locking Methods of the Cache. It must not modify the current Data in Place
+// when the Data is shared with other Users of the Cache.
+type FixedSizeBubbleCacheUpdateFunction func(
+	data interface{},
+) (newData interface{})
+
+// Returns a new Version for the Data of a Record. Versions increase across
+// the whole Cache, so a Record which has been deleted and added again never
+// gets a Version it had before.
+func (c *FixedSizeBubbleCache) nextVersion() uint64 {
+	c.lastVersion++
+	return c.lastVersion
+}
+
+// Gets the Record's Data and its Version by the Record's UID. The Record is
+// moved to the Top and its LAT is refreshed, as with the
+// 'GetActualRecordDataByUID' Method.
+func (c *FixedSizeBubbleCache) GetActualRecordDataAndVersionByUID(
+	uid FixedSizeBubbleCacheRecordUID,
+) (data interface{}, version uint64, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	data, err = c.getActualRecordDataByUID(uid)
+	if err != nil {
+		return
+	}
+	return data, c.top.version, nil
+}
+
+// Replaces the Record's Data if the Record has the expected Version. Returns
+// the new Version of the Record. The Record is moved to the Top and its LAT
+// is refreshed, its individual TTL is kept.
+func (c *FixedSizeBubbleCache) CompareAndSwap(
+	uid FixedSizeBubbleCacheRecordUID,
+	expectedVersion uint64,
+	newData interface{},
+) (newVersion uint64, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if newData == nil {
+		return 0, errors.New(ErrDataIsEmpty)
+	}
+
+	var record *FixedSizeBubbleCacheRecord
+	record, err = c.getUpdatableRecord(uid)
+	if err != nil {
+		return
+	}
+	if record.version != expectedVersion {
+		err = fmt.Errorf(ErrfRecordVersionMismatch, uid, record.version, expectedVersion)
+		return
+	}
+
+	return c.updateRecordData(record, newData), nil
+}
+
+// Calculates new Data of the Record with the Function and stores it. Returns
+// the new Version of the Record. The Record is moved to the Top and its LAT
+// is refreshed, its individual TTL is kept. If the Function returns null
+// Data, the Record is not changed and an Error is returned.
+func (c *FixedSizeBubbleCache) Update(
+	uid FixedSizeBubbleCacheRecordUID,
+	updateFunction FixedSizeBubbleCacheUpdateFunction,
+) (newVersion uint64, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var record *FixedSizeBubbleCacheRecord
+	record, err = c.getUpdatableRecord(uid)
+	if err != nil {
+		return
+	}
+
+	var newData = updateFunction(record.Data)
+	if newData == nil {
+		return 0, errors.New(ErrDataIsEmpty)
+	}
+
+	return c.updateRecordData(record, newData), nil
+}
+
+// Checks the Record's Parameters and adds it to the Cache if the Cache has no
+// actual Record with the same UID. Outdated and negative Records are
+// considered absent and are replaced.
+func (c *FixedSizeBubbleCache) AddIfAbsent(
+	record *FixedSizeBubbleCacheRecord,
+) (recordIsAdded bool, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if record == nil {
+		return false, errors.New(ErrRecordIsNotSet)
+	}
+	err = record.Check()
+	if err != nil {
+		return
+	}
+
+	_, err = c.getUpdatableRecord(record.UID)
+	if err == nil {
+		return false, nil
+	}
+
+	c.addRecord(record)
+	return true, nil
+}
+
+// Checks the Record's Parameters and adds it to the Cache if the Cache has an
+// actual Record with the same UID. Outdated and negative Records are
+// considered absent and are not replaced.
+func (c *FixedSizeBubbleCache) ReplaceIfPresent(
+	record *FixedSizeBubbleCacheRecord,
+) (recordIsReplaced bool, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if record == nil {
+		return false, errors.New(ErrRecordIsNotSet)
+	}
+	err = record.Check()
+	if err != nil {
+		return
+	}
+
+	_, err = c.getUpdatableRecord(record.UID)
+	if err != nil {
+		return false, nil
+	}
+
+	c.addRecord(record)
+	return true, nil
+}
+
+// Gets an actual positive Record which may be updated. An outdated Record is
+// deleted.
+func (c *FixedSizeBubbleCache) getUpdatableRecord(
+	uid FixedSizeBubbleCacheRecordUID,
+) (record *FixedSizeBubbleCacheRecord, err error) {
+	record, err = c.getRecordByUID(uid)
+	if err != nil {
+		return
+	}
+
+	if !c.isRecordActual(record) {
+		err = c.deleteRecord(record, true)
+		if err != nil {
+			return
+		}
+		c.statistics.Expirations++
+		return nil, fmt.Errorf(ErrfRecordWithUidIsOutdated, uid)
+	}
+	if record.isNegative {
+		return nil, fmt.Errorf(ErrfRecordWithUidIsNegative, uid)
+	}
+	return
+}
+
+// Stores new Data of the Record and moves it to the Top.
+func (c *FixedSizeBubbleCache) updateRecordData(
+	record *FixedSizeBubbleCacheRecord,
+	newData interface{},
+) (newVersion uint64) {
+	if record != c.top {
+		c.moveExistingRecordToTop(record)
+	}
+	c.top.updateData(newData, c.now())
+	c.top.version = c.nextVersion()
+	c.journalRecordAdded(c.top)
+	return c.top.version
+}
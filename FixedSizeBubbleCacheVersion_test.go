@@ -0,0 +1,225 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/vault-thirteen/tester"
+)
+
+func Test_GetActualRecordDataAndVersionByUID(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(2, 60)
+
+	// Test #1. Versions increase with every Change of the Data.
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1}))
+	var data, version, err = cache.GetActualRecordDataAndVersionByUID("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, 1)
+	aTest.MustBeEqual(version, uint64(1))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 3}))
+	_, version, err = cache.GetActualRecordDataAndVersionByUID("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(version, uint64(3))
+
+	// Test #2. Requests do not change the Version.
+	_, err = cache.GetActualRecordDataByUID("a")
+	aTest.MustBeNoError(err)
+	_, version, _ = cache.GetActualRecordDataAndVersionByUID("a")
+	aTest.MustBeEqual(version, uint64(3))
+
+	// Test #3. A Record added again gets a new Version.
+	aTest.MustBeNoError(cache.DeleteRecordByUID("a"))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1}))
+	_, version, _ = cache.GetActualRecordDataAndVersionByUID("a")
+	aTest.MustBeEqual(version, uint64(4))
+
+	// Test #4. Missing Record.
+	_, version, err = cache.GetActualRecordDataAndVersionByUID("x")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(version, uint64(0))
+}
+
+func Test_CompareAndSwap(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(3, 60)
+	cache.SetNegativeRecordTTL(60)
+	aTest.MustBeNoError(cache.AddRecordWithTTL(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1}, 30))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2}))
+	aTest.MustBeNoError(cache.AddNegativeRecord("n"))
+
+	// Test #1. Expected Version.
+	var newVersion, err = cache.CompareAndSwap("a", 1, 10)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(newVersion, uint64(4))
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"a", "n", "b"})
+	var remainingTTL uint
+	remainingTTL, err = cache.GetRecordRemainingTTL("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(remainingTTL <= 30, true)
+
+	// Test #2. Unexpected Version.
+	newVersion, err = cache.CompareAndSwap("a", 1, 20)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(newVersion, uint64(0))
+	var data interface{}
+	data, _ = cache.GetActualRecordDataByUID("a")
+	aTest.MustBeEqual(data, 10)
+
+	// Test #3. Missing, negative Records and null Data.
+	_, err = cache.CompareAndSwap("x", 1, 20)
+	aTest.MustBeAnError(err)
+	_, err = cache.CompareAndSwap("n", 3, 20)
+	aTest.MustBeAnError(err)
+	_, err = cache.CompareAndSwap("b", 2, nil)
+	aTest.MustBeAnError(err)
+
+	// Test #4. Outdated Record is deleted.
+	var outdatedRecord, _ = cache.getRecordByUID("b")
+	outdatedRecord.lastAccessTime = 0
+	_, err = cache.CompareAndSwap("b", 2, 20)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(cache.RecordUIDExists("b"), false)
+	aTest.MustBeEqual(cache.Stats().Expirations, uint64(1))
+}
+
+func Test_Update(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(2, 60)
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2}))
+
+	// Test #1. New Data.
+	var newVersion, err = cache.Update("a", func(data interface{}) interface{} {
+		return data.(int) + 1
+	})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(newVersion, uint64(3))
+	aTest.MustBeEqual(cache.ListAllRecordValues(), []interface{}{2, 2})
+
+	// Test #2. Null Data keeps the Record.
+	_, err = cache.Update("b", func(data interface{}) interface{} {
+		return nil
+	})
+	aTest.MustBeAnError(err)
+	var version uint64
+	_, version, _ = cache.GetActualRecordDataAndVersionByUID("b")
+	aTest.MustBeEqual(version, uint64(2))
+
+	// Test #3. Missing Record.
+	_, err = cache.Update("x", func(data interface{}) interface{} {
+		return data
+	})
+	aTest.MustBeAnError(err)
+
+	// Test #4. Concurrent Updates are not lost.
+	const workers = 8
+	const iterations = 500
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				_, _ = cache.Update("a", func(data interface{}) interface{} {
+					return data.(int) + 1
+				})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; {
+				var data, version, err = cache.GetActualRecordDataAndVersionByUID("a")
+				if err != nil {
+					continue
+				}
+				_, err = cache.CompareAndSwap("a", version, data.(int)+1)
+				if err == nil {
+					i++
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	var data interface{}
+	data, err = cache.GetActualRecordDataByUID("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, 2+2*workers*iterations)
+}
+
+func Test_AddIfAbsent(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(3, 60)
+	cache.SetNegativeRecordTTL(60)
+
+	// Test #1. Absent Record.
+	var recordIsAdded, err = cache.AddIfAbsent(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(recordIsAdded, true)
+
+	// Test #2. Present Record.
+	recordIsAdded, err = cache.AddIfAbsent(&FixedSizeBubbleCacheRecord{UID: "a", Data: 2})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(recordIsAdded, false)
+	var data interface{}
+	data, _ = cache.GetActualRecordDataByUID("a")
+	aTest.MustBeEqual(data, 1)
+
+	// Test #3. Negative and outdated Records are replaced.
+	aTest.MustBeNoError(cache.AddNegativeRecord("n"))
+	recordIsAdded, err = cache.AddIfAbsent(&FixedSizeBubbleCacheRecord{UID: "n", Data: 3})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(recordIsAdded, true)
+	aTest.MustBeEqual(cache.Stats().NegativeRecords, uint(0))
+	var outdatedRecord, _ = cache.getRecordByUID("a")
+	outdatedRecord.lastAccessTime = 0
+	recordIsAdded, err = cache.AddIfAbsent(&FixedSizeBubbleCacheRecord{UID: "a", Data: 4})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(recordIsAdded, true)
+	aTest.MustBeEqual(cache.ListAllRecordValues(), []interface{}{4, 3})
+
+	// Test #4. Bad Records.
+	_, err = cache.AddIfAbsent(nil)
+	aTest.MustBeAnError(err)
+	_, err = cache.AddIfAbsent(&FixedSizeBubbleCacheRecord{UID: "b"})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(cache.isIntegral(), true)
+}
+
+func Test_ReplaceIfPresent(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(3, 60)
+	cache.SetNegativeRecordTTL(60)
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1}))
+	aTest.MustBeNoError(cache.AddNegativeRecord("n"))
+
+	// Test #1. Present Record.
+	var recordIsReplaced, err = cache.ReplaceIfPresent(&FixedSizeBubbleCacheRecord{UID: "a", Data: 2})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(recordIsReplaced, true)
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"a", "n"})
+
+	// Test #2. Absent and negative Records.
+	recordIsReplaced, err = cache.ReplaceIfPresent(&FixedSizeBubbleCacheRecord{UID: "x", Data: 3})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(recordIsReplaced, false)
+	recordIsReplaced, err = cache.ReplaceIfPresent(&FixedSizeBubbleCacheRecord{UID: "n", Data: 3})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(recordIsReplaced, false)
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"a", "n"})
+
+	// Test #3. Outdated Record.
+	var outdatedRecord, _ = cache.getRecordByUID("a")
+	outdatedRecord.lastAccessTime = 0
+	recordIsReplaced, err = cache.ReplaceIfPresent(&FixedSizeBubbleCacheRecord{UID: "a", Data: 3})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(recordIsReplaced, false)
+	aTest.MustBeEqual(listCacheUIDs(cache), []FixedSizeBubbleCacheRecordUID{"n"})
+
+	// Test #4. Bad Records.
+	_, err = cache.ReplaceIfPresent(nil)
+	aTest.MustBeAnError(err)
+}
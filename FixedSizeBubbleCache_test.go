@@ -23,6 +23,30 @@ func Test_NewFixedSizeBubbleCache(t *testing.T) {
 	aTest.MustBeEqual(cache.capacity, uint(1))
 }
 
+func Test_NewCheckedFixedSizeBubbleCache(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache *FixedSizeBubbleCache
+	var err error
+
+	// Test #1. Normal Capacity.
+	cache, err = NewCheckedFixedSizeBubbleCache(2, 60)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(cache.capacity, uint(2))
+
+	// Test #2. Too large Capacity.
+	cache, err = NewCheckedFixedSizeBubbleCache(MaxCapacity+1, 60)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(cache == nil, true)
+	var isPanicking = func() (isPanicking bool) {
+		defer func() {
+			isPanicking = recover() != nil
+		}()
+		NewFixedSizeBubbleCache(MaxCapacity+1, 60)
+		return
+	}()
+	aTest.MustBeEqual(isPanicking, true)
+}
+
 func Test_initialize(t *testing.T) {
 	var aTest *tester.Test = tester.New(t)
 	var cache *FixedSizeBubbleCache = new(FixedSizeBubbleCache)
@@ -33,14 +57,11 @@ func Test_initialize(t *testing.T) {
 	aTest.MustBeEqual(cache.bottom, (*FixedSizeBubbleCacheRecord)(nil))
 	aTest.MustBeEqual(cache.size, uint(0))
 	aTest.MustBeEqual(cache.capacity, uint(10))
-	var mapLen int
-	var lenCounter = func(key, value interface{}) bool {
-		mapLen++
-		return true
-	}
-	cache.recordsByUID.Range(lenCounter)
-	aTest.MustBeEqual(mapLen, int(0))
+	aTest.MustBeEqual(cache.recordsByUID.len(), uint(0))
 	aTest.MustBeEqual(cache.recordTTL, uint(60))
+	aTest.MustBeEqual(len(cache.records), 10)
+	aTest.MustBeEqual(cache.freeRecord, int32(0))
+	aTest.MustBeEqual(cache.records[9].lower, noRecord)
 }
 
 func Test_AddRecord(t *testing.T) {
@@ -130,6 +151,25 @@ func Test_addRecord(t *testing.T) {
 	aTest.MustBeEqual(cache.size, uint(2))
 	aTest.MustBeEqual(cache.top.UID, "300")
 	aTest.MustBeEqual(cache.top.Data, 300)
+
+	// Test #6. A new Record and a full single-Record Cache.
+	cache = NewFixedSizeBubbleCache(1, 60)
+	cache.addRecord(
+		&FixedSizeBubbleCacheRecord{
+			Data: 1,
+			UID:  "1",
+		},
+	)
+	cache.addRecord(
+		&FixedSizeBubbleCacheRecord{
+			Data: 2,
+			UID:  "2",
+		},
+	)
+	aTest.MustBeEqual(cache.size, uint(1))
+	aTest.MustBeEqual(cache.top.UID, "2")
+	aTest.MustBeEqual(cache.bottom.UID, "2")
+	aTest.MustBeEqual(cache.RecordUIDExists("1"), false)
 }
 
 func Test_moveExistingRecordToTop(t *testing.T) {
@@ -169,11 +209,11 @@ func Test_moveExistingRecordToTop(t *testing.T) {
 	aTest.MustBeEqual(cache.bottom.Data, 1)
 
 	// Test #3. Size > 2, Not a Bottom.
-	cache.moveExistingRecordToTop(cache.bottom.upperRecord)
+	cache.moveExistingRecordToTop(cache.upperOf(cache.bottom))
 	aTest.MustBeEqual(cache.top.UID, "Third")
 	aTest.MustBeEqual(cache.top.Data, 3)
-	aTest.MustBeEqual(cache.top.lowerRecord.UID, "Second")
-	aTest.MustBeEqual(cache.top.lowerRecord.Data, 2)
+	aTest.MustBeEqual(cache.lowerOf(cache.top).UID, "Second")
+	aTest.MustBeEqual(cache.lowerOf(cache.top).Data, 2)
 	aTest.MustBeEqual(cache.bottom.UID, "First")
 	aTest.MustBeEqual(cache.bottom.Data, 1)
 }
@@ -206,18 +246,18 @@ func Test_unlinkTopRecord(t *testing.T) {
 	//
 	aTest.MustBeEqual(oldTop.UID, "Third")
 	aTest.MustBeEqual(oldTop.Data, 3)
-	aTest.MustBeEqual(oldTop.upperRecord, (*FixedSizeBubbleCacheRecord)(nil))
-	aTest.MustBeEqual(oldTop.lowerRecord, (*FixedSizeBubbleCacheRecord)(nil))
+	aTest.MustBeEqual(oldTop.upper, noRecord)
+	aTest.MustBeEqual(oldTop.lower, noRecord)
 	//
 	aTest.MustBeEqual(cache.top.UID, "Second")
 	aTest.MustBeEqual(cache.top.Data, 2)
 	aTest.MustBeEqual(cache.bottom.UID, "First")
 	aTest.MustBeEqual(cache.bottom.Data, 1)
 	//
-	aTest.MustBeEqual(cache.top.lowerRecord, cache.bottom)
-	aTest.MustBeEqual(cache.bottom.upperRecord, cache.top)
-	aTest.MustBeEqual(cache.top.upperRecord, (*FixedSizeBubbleCacheRecord)(nil))
-	aTest.MustBeEqual(cache.bottom.lowerRecord, (*FixedSizeBubbleCacheRecord)(nil))
+	aTest.MustBeEqual(cache.top.lower, cache.bottom.index)
+	aTest.MustBeEqual(cache.bottom.upper, cache.top.index)
+	aTest.MustBeEqual(cache.top.upper, noRecord)
+	aTest.MustBeEqual(cache.bottom.lower, noRecord)
 }
 
 func Test_unlinkMiddleRecord(t *testing.T) {
@@ -243,23 +283,23 @@ func Test_unlinkMiddleRecord(t *testing.T) {
 			UID:  "Third",
 		},
 	)
-	var oldMiddle *FixedSizeBubbleCacheRecord = cache.top.lowerRecord
+	var oldMiddle *FixedSizeBubbleCacheRecord = cache.lowerOf(cache.top)
 	cache.unlinkMiddleRecord(oldMiddle)
 	//
 	aTest.MustBeEqual(oldMiddle.UID, "Second")
 	aTest.MustBeEqual(oldMiddle.Data, 2)
-	aTest.MustBeEqual(oldMiddle.upperRecord, (*FixedSizeBubbleCacheRecord)(nil))
-	aTest.MustBeEqual(oldMiddle.lowerRecord, (*FixedSizeBubbleCacheRecord)(nil))
+	aTest.MustBeEqual(oldMiddle.upper, noRecord)
+	aTest.MustBeEqual(oldMiddle.lower, noRecord)
 	//
 	aTest.MustBeEqual(cache.top.UID, "Third")
 	aTest.MustBeEqual(cache.top.Data, 3)
 	aTest.MustBeEqual(cache.bottom.UID, "First")
 	aTest.MustBeEqual(cache.bottom.Data, 1)
 	//
-	aTest.MustBeEqual(cache.top.lowerRecord, cache.bottom)
-	aTest.MustBeEqual(cache.bottom.upperRecord, cache.top)
-	aTest.MustBeEqual(cache.top.upperRecord, (*FixedSizeBubbleCacheRecord)(nil))
-	aTest.MustBeEqual(cache.bottom.lowerRecord, (*FixedSizeBubbleCacheRecord)(nil))
+	aTest.MustBeEqual(cache.top.lower, cache.bottom.index)
+	aTest.MustBeEqual(cache.bottom.upper, cache.top.index)
+	aTest.MustBeEqual(cache.top.upper, noRecord)
+	aTest.MustBeEqual(cache.bottom.lower, noRecord)
 }
 
 func Test_unlinkBottomRecord(t *testing.T) {
@@ -290,18 +330,18 @@ func Test_unlinkBottomRecord(t *testing.T) {
 	//
 	aTest.MustBeEqual(oldBottom.UID, "First")
 	aTest.MustBeEqual(oldBottom.Data, 1)
-	aTest.MustBeEqual(oldBottom.upperRecord, (*FixedSizeBubbleCacheRecord)(nil))
-	aTest.MustBeEqual(oldBottom.lowerRecord, (*FixedSizeBubbleCacheRecord)(nil))
+	aTest.MustBeEqual(oldBottom.upper, noRecord)
+	aTest.MustBeEqual(oldBottom.lower, noRecord)
 	//
 	aTest.MustBeEqual(cache.top.UID, "Third")
 	aTest.MustBeEqual(cache.top.Data, 3)
 	aTest.MustBeEqual(cache.bottom.UID, "Second")
 	aTest.MustBeEqual(cache.bottom.Data, 2)
 	//
-	aTest.MustBeEqual(cache.top.lowerRecord, cache.bottom)
-	aTest.MustBeEqual(cache.bottom.upperRecord, cache.top)
-	aTest.MustBeEqual(cache.top.upperRecord, (*FixedSizeBubbleCacheRecord)(nil))
-	aTest.MustBeEqual(cache.bottom.lowerRecord, (*FixedSizeBubbleCacheRecord)(nil))
+	aTest.MustBeEqual(cache.top.lower, cache.bottom.index)
+	aTest.MustBeEqual(cache.bottom.upper, cache.top.index)
+	aTest.MustBeEqual(cache.top.upper, noRecord)
+	aTest.MustBeEqual(cache.bottom.lower, noRecord)
 }
 
 func Test_linkTopRecord(t *testing.T) {
@@ -314,7 +354,7 @@ func Test_linkTopRecord(t *testing.T) {
 		UID:  "First",
 		Data: 1,
 	}
-	cache.linkTopRecord(record)
+	cache.linkTopRecord(cache.storeRecord(record))
 	cache.size++
 	//
 	aTest.MustBeEqual(cache.top.UID, "First")
@@ -322,27 +362,27 @@ func Test_linkTopRecord(t *testing.T) {
 	aTest.MustBeEqual(cache.bottom.UID, "First")
 	aTest.MustBeEqual(cache.bottom.Data, 1)
 	//
-	aTest.MustBeEqual(cache.top.lowerRecord, (*FixedSizeBubbleCacheRecord)(nil))
-	aTest.MustBeEqual(cache.bottom.upperRecord, (*FixedSizeBubbleCacheRecord)(nil))
-	aTest.MustBeEqual(cache.top.upperRecord, (*FixedSizeBubbleCacheRecord)(nil))
-	aTest.MustBeEqual(cache.bottom.lowerRecord, (*FixedSizeBubbleCacheRecord)(nil))
+	aTest.MustBeEqual(cache.top.lower, noRecord)
+	aTest.MustBeEqual(cache.bottom.upper, noRecord)
+	aTest.MustBeEqual(cache.top.upper, noRecord)
+	aTest.MustBeEqual(cache.bottom.lower, noRecord)
 
 	// Test #2. Non-empty Cache.
 	record = &FixedSizeBubbleCacheRecord{
 		UID:  "Second",
 		Data: 2,
 	}
-	cache.linkTopRecord(record)
+	cache.linkTopRecord(cache.storeRecord(record))
 	//
 	aTest.MustBeEqual(cache.top.UID, "Second")
 	aTest.MustBeEqual(cache.top.Data, 2)
 	aTest.MustBeEqual(cache.bottom.UID, "First")
 	aTest.MustBeEqual(cache.bottom.Data, 1)
 	//
-	aTest.MustBeEqual(cache.top.lowerRecord, cache.bottom)
-	aTest.MustBeEqual(cache.bottom.upperRecord, cache.top)
-	aTest.MustBeEqual(cache.top.upperRecord, (*FixedSizeBubbleCacheRecord)(nil))
-	aTest.MustBeEqual(cache.bottom.lowerRecord, (*FixedSizeBubbleCacheRecord)(nil))
+	aTest.MustBeEqual(cache.top.lower, cache.bottom.index)
+	aTest.MustBeEqual(cache.bottom.upper, cache.top.index)
+	aTest.MustBeEqual(cache.top.upper, noRecord)
+	aTest.MustBeEqual(cache.bottom.lower, noRecord)
 }
 
 func Test_linkBottomRecord(t *testing.T) {
@@ -355,7 +395,7 @@ func Test_linkBottomRecord(t *testing.T) {
 		UID:  "First",
 		Data: 1,
 	}
-	cache.linkBottomRecord(record)
+	cache.linkBottomRecord(cache.storeRecord(record))
 	cache.size++
 	//
 	aTest.MustBeEqual(cache.top.UID, "First")
@@ -363,27 +403,27 @@ func Test_linkBottomRecord(t *testing.T) {
 	aTest.MustBeEqual(cache.bottom.UID, "First")
 	aTest.MustBeEqual(cache.bottom.Data, 1)
 	//
-	aTest.MustBeEqual(cache.top.lowerRecord, (*FixedSizeBubbleCacheRecord)(nil))
-	aTest.MustBeEqual(cache.bottom.upperRecord, (*FixedSizeBubbleCacheRecord)(nil))
-	aTest.MustBeEqual(cache.top.upperRecord, (*FixedSizeBubbleCacheRecord)(nil))
-	aTest.MustBeEqual(cache.bottom.lowerRecord, (*FixedSizeBubbleCacheRecord)(nil))
+	aTest.MustBeEqual(cache.top.lower, noRecord)
+	aTest.MustBeEqual(cache.bottom.upper, noRecord)
+	aTest.MustBeEqual(cache.top.upper, noRecord)
+	aTest.MustBeEqual(cache.bottom.lower, noRecord)
 
 	// Test #2. Non-empty Cache.
 	record = &FixedSizeBubbleCacheRecord{
 		UID:  "Second",
 		Data: 2,
 	}
-	cache.linkBottomRecord(record)
+	cache.linkBottomRecord(cache.storeRecord(record))
 	//
 	aTest.MustBeEqual(cache.top.UID, "First")
 	aTest.MustBeEqual(cache.top.Data, 1)
 	aTest.MustBeEqual(cache.bottom.UID, "Second")
 	aTest.MustBeEqual(cache.bottom.Data, 2)
 	//
-	aTest.MustBeEqual(cache.top.lowerRecord, cache.bottom)
-	aTest.MustBeEqual(cache.bottom.upperRecord, cache.top)
-	aTest.MustBeEqual(cache.top.upperRecord, (*FixedSizeBubbleCacheRecord)(nil))
-	aTest.MustBeEqual(cache.bottom.lowerRecord, (*FixedSizeBubbleCacheRecord)(nil))
+	aTest.MustBeEqual(cache.top.lower, cache.bottom.index)
+	aTest.MustBeEqual(cache.bottom.upper, cache.top.index)
+	aTest.MustBeEqual(cache.top.upper, noRecord)
+	aTest.MustBeEqual(cache.bottom.lower, noRecord)
 }
 
 func Test_Clear(t *testing.T) {
@@ -404,7 +444,7 @@ func Test_Clear(t *testing.T) {
 			Data: 2,
 		},
 	)
-	cache.top.lowerRecord = nil
+	cache.top.lower = noRecord
 	err = cache.Clear()
 	aTest.MustBeAnError(err)
 
@@ -447,7 +487,9 @@ func Test_isIntegral(t *testing.T) {
 			Data: 1,
 		},
 	)
-	cache.recordsByUID.Store("1", nil)
+	cache.recordsByUID.insert("1", 2)
+	aTest.MustBeEqual(cache.isIntegral(), false)
+	cache.recordsByUID.remove("1")
 	aTest.MustBeEqual(cache.isIntegral(), false)
 
 	// Test #2. Size > Capacity.
@@ -474,6 +516,7 @@ func Test_isIntegral(t *testing.T) {
 		},
 	)
 	cache.size = 0
+	cache.recordsByUID.remove("1")
 	aTest.MustBeEqual(cache.isIntegral(), false)
 	cache.top = nil
 	aTest.MustBeEqual(cache.isIntegral(), false)
@@ -523,10 +566,7 @@ func Test_isIntegral(t *testing.T) {
 			Data: 1,
 		},
 	)
-	cache.top.upperRecord = &FixedSizeBubbleCacheRecord{
-		UID:  "Fake",
-		Data: 999,
-	}
+	cache.top.upper = 2
 	aTest.MustBeEqual(cache.isIntegral(), false)
 	//
 	cache = NewFixedSizeBubbleCache(3, 1)
@@ -536,10 +576,7 @@ func Test_isIntegral(t *testing.T) {
 			Data: 1,
 		},
 	)
-	cache.bottom.lowerRecord = &FixedSizeBubbleCacheRecord{
-		UID:  "Fake",
-		Data: 999,
-	}
+	cache.bottom.lower = 2
 	aTest.MustBeEqual(cache.isIntegral(), false)
 	//
 	cache = NewFixedSizeBubbleCache(3, 1)
@@ -573,18 +610,12 @@ func Test_isIntegral(t *testing.T) {
 			Data: 3,
 		},
 	)
-	cache.top.upperRecord = &FixedSizeBubbleCacheRecord{
-		UID:  "Fake",
-		Data: 999,
-	}
+	cache.top.upper = cache.bottom.index
 	aTest.MustBeEqual(cache.isIntegral(), false)
-	cache.top.upperRecord = nil
-	cache.bottom.lowerRecord = &FixedSizeBubbleCacheRecord{
-		UID:  "Fake",
-		Data: 999,
-	}
+	cache.top.upper = noRecord
+	cache.bottom.lower = cache.top.index
 	aTest.MustBeEqual(cache.isIntegral(), false)
-	cache.bottom.lowerRecord = nil
+	cache.bottom.lower = noRecord
 
 	// Test #5. Forward Loop.
 	cache = NewFixedSizeBubbleCache(3, 1)
@@ -606,16 +637,12 @@ func Test_isIntegral(t *testing.T) {
 			Data: 3,
 		},
 	)
-	var tmp = &FixedSizeBubbleCacheRecord{
-		UID:         "Fake",
-		Data:        999,
-		lowerRecord: cache.bottom,
-	}
-	cache.bottom.upperRecord.lowerRecord = tmp
+	var middle = cache.lowerOf(cache.top)
+	middle.lower = cache.top.index
 	aTest.MustBeEqual(cache.isIntegral(), false)
-	tmp.lowerRecord = nil
+	middle.lower = 999
 	aTest.MustBeEqual(cache.isIntegral(), false)
-	cache.top.lowerRecord = nil
+	cache.top.lower = noRecord
 	aTest.MustBeEqual(cache.isIntegral(), false)
 
 	// Test #6. Reverse Loop.
@@ -640,16 +667,12 @@ func Test_isIntegral(t *testing.T) {
 			Data: 3,
 		},
 	)
-	tmp = &FixedSizeBubbleCacheRecord{
-		UID:         "Fake",
-		Data:        999,
-		upperRecord: cache.top,
-	}
-	cache.top.lowerRecord.upperRecord = tmp
+	middle = cache.upperOf(cache.bottom)
+	middle.upper = cache.bottom.index
 	aTest.MustBeEqual(cache.isIntegral(), false)
-	tmp.upperRecord = nil
+	middle.upper = 999
 	aTest.MustBeEqual(cache.isIntegral(), false)
-	cache.bottom.upperRecord = nil
+	cache.bottom.upper = noRecord
 	aTest.MustBeEqual(cache.isIntegral(), false)
 
 	// Test #7. OK.
@@ -740,10 +763,10 @@ func Test_deleteRecord(t *testing.T) {
 	aTest.MustBeEqual(cache.bottom.UID, "1")
 	aTest.MustBeEqual(cache.bottom.Data, 1)
 	//
-	aTest.MustBeEqual(cache.top.upperRecord, (*FixedSizeBubbleCacheRecord)(nil))
-	aTest.MustBeEqual(cache.top.lowerRecord, (*FixedSizeBubbleCacheRecord)(nil))
-	aTest.MustBeEqual(cache.bottom.upperRecord, (*FixedSizeBubbleCacheRecord)(nil))
-	aTest.MustBeEqual(cache.bottom.lowerRecord, (*FixedSizeBubbleCacheRecord)(nil))
+	aTest.MustBeEqual(cache.top.upper, noRecord)
+	aTest.MustBeEqual(cache.top.lower, noRecord)
+	aTest.MustBeEqual(cache.bottom.upper, noRecord)
+	aTest.MustBeEqual(cache.bottom.lower, noRecord)
 	//
 	aTest.MustBeEqual(cache.size, uint(1))
 
@@ -769,10 +792,10 @@ func Test_deleteRecord(t *testing.T) {
 	aTest.MustBeEqual(cache.bottom.UID, "2")
 	aTest.MustBeEqual(cache.bottom.Data, 2)
 	//
-	aTest.MustBeEqual(cache.top.upperRecord, (*FixedSizeBubbleCacheRecord)(nil))
-	aTest.MustBeEqual(cache.top.lowerRecord, (*FixedSizeBubbleCacheRecord)(nil))
-	aTest.MustBeEqual(cache.bottom.upperRecord, (*FixedSizeBubbleCacheRecord)(nil))
-	aTest.MustBeEqual(cache.bottom.lowerRecord, (*FixedSizeBubbleCacheRecord)(nil))
+	aTest.MustBeEqual(cache.top.upper, noRecord)
+	aTest.MustBeEqual(cache.top.lower, noRecord)
+	aTest.MustBeEqual(cache.bottom.upper, noRecord)
+	aTest.MustBeEqual(cache.bottom.lower, noRecord)
 	//
 	aTest.MustBeEqual(cache.size, uint(1))
 
@@ -796,7 +819,7 @@ func Test_deleteRecord(t *testing.T) {
 			Data: 3,
 		},
 	)
-	err = cache.deleteRecord(cache.top.lowerRecord, false)
+	err = cache.deleteRecord(cache.lowerOf(cache.top), false)
 	aTest.MustBeNoError(err)
 	//
 	aTest.MustBeEqual(cache.top.UID, "3")
@@ -804,10 +827,10 @@ func Test_deleteRecord(t *testing.T) {
 	aTest.MustBeEqual(cache.bottom.UID, "1")
 	aTest.MustBeEqual(cache.bottom.Data, 1)
 	//
-	aTest.MustBeEqual(cache.top.upperRecord, (*FixedSizeBubbleCacheRecord)(nil))
-	aTest.MustBeEqual(cache.top.lowerRecord, cache.bottom)
-	aTest.MustBeEqual(cache.bottom.upperRecord, cache.top)
-	aTest.MustBeEqual(cache.bottom.lowerRecord, (*FixedSizeBubbleCacheRecord)(nil))
+	aTest.MustBeEqual(cache.top.upper, noRecord)
+	aTest.MustBeEqual(cache.top.lower, cache.bottom.index)
+	aTest.MustBeEqual(cache.bottom.upper, cache.top.index)
+	aTest.MustBeEqual(cache.bottom.lower, noRecord)
 	//
 	aTest.MustBeEqual(cache.size, uint(2))
 }
@@ -900,8 +923,8 @@ func Test_getRecordByUID(t *testing.T) {
 	aTest.MustBeEqual(record.UID, "2")
 	aTest.MustBeEqual(record.Data, 2)
 	aTest.MustBeEqual(record.lastAccessTime, now)
-	aTest.MustBeEqual(record.upperRecord, cache.top)
-	aTest.MustBeEqual(record.lowerRecord, cache.bottom)
+	aTest.MustBeEqual(record.upper, cache.top.index)
+	aTest.MustBeEqual(record.lower, cache.bottom.index)
 
 	// Test #2.
 	record, err = cache.getRecordByUID("999")
@@ -1014,18 +1037,33 @@ func Test_ListAllRecords(t *testing.T) {
 	//
 	aTest.MustBeEqual(records[0].UID, "3")
 	aTest.MustBeEqual(records[0].Data, 3)
-	aTest.MustBeEqual(records[0].upperRecord, (*FixedSizeBubbleCacheRecord)(nil))
-	aTest.MustBeEqual(records[0].lowerRecord, cache.top.lowerRecord)
+	aTest.MustBeEqual(records[0].lastAccessTime, cache.top.lastAccessTime)
 	//
 	aTest.MustBeEqual(records[1].UID, "2")
 	aTest.MustBeEqual(records[1].Data, 2)
-	aTest.MustBeEqual(records[1].upperRecord, cache.top)
-	aTest.MustBeEqual(records[1].lowerRecord, cache.bottom)
 	//
 	aTest.MustBeEqual(records[2].UID, "1")
 	aTest.MustBeEqual(records[2].Data, 1)
-	aTest.MustBeEqual(records[2].upperRecord, cache.bottom.upperRecord)
-	aTest.MustBeEqual(records[2].lowerRecord, (*FixedSizeBubbleCacheRecord)(nil))
+
+	// Test #3. Records are unlinked Copies.
+	for _, record := range records {
+		aTest.MustBeEqual(record.index, noRecord)
+		aTest.MustBeEqual(record.upper, noRecord)
+		aTest.MustBeEqual(record.lower, noRecord)
+	}
+	records[0].Data = 999
+	aTest.MustBeEqual(cache.top.Data, 3)
+
+	// Test #4. Records of the Cache are rejected, their Copies are accepted.
+	var otherCache = NewFixedSizeBubbleCache(3, 60)
+	aTest.MustBeAnError(cache.AddRecord(cache.top))
+	aTest.MustBeAnError(otherCache.AddRecord(cache.bottom))
+	aTest.MustBeAnError(otherCache.AddRecordWithTTL(cache.bottom, 10))
+	aTest.MustBeNoError(otherCache.AddRecord(records[0]))
+	aTest.MustBeNoError(cache.AddRecord(records[2]))
+	aTest.MustBeEqual(cache.isIntegral(), true)
+	aTest.MustBeEqual(otherCache.isIntegral(), true)
+	aTest.MustBeEqual(otherCache.ListAllRecordValues(), []interface{}{999})
 }
 
 func Test_GetActualRecordDataByUID(t *testing.T) {
@@ -1091,6 +1129,14 @@ func Test_GetRecordTTL(t *testing.T) {
 	aTest.MustBeEqual(cache.GetRecordTTL(), uint(60))
 }
 
+func Test_GetCapacity(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = NewFixedSizeBubbleCache(10, 60)
+
+	// Test #1.
+	aTest.MustBeEqual(cache.GetCapacity(), uint(10))
+}
+
 func Test_IsRecordUIDActive(t *testing.T) {
 	var aTest *tester.Test = tester.New(t)
 	var recordIsActive bool
@@ -1114,3 +1160,65 @@ func Test_IsRecordUIDActive(t *testing.T) {
 	aTest.MustBeNoError(err)
 	aTest.MustBeEqual(recordIsActive, false)
 }
+
+// Capacity of the Caches used in Benchmarks.
+const benchmarkCapacity = 10000
+
+// Returns distinct UIDs for Benchmarks.
+func makeBenchmarkUIDs(
+	count int,
+) (uids []FixedSizeBubbleCacheRecordUID) {
+	uids = make([]FixedSizeBubbleCacheRecordUID, count)
+	for i := range uids {
+		uids[i] = fmt.Sprintf("record-%v", i)
+	}
+	return
+}
+
+// Adds new Records to a full Cache, so each Addition evicts the Bottom.
+func Benchmark_AddRecord_Eviction(b *testing.B) {
+	var cache = NewFixedSizeBubbleCache(benchmarkCapacity, 60)
+	var uids = makeBenchmarkUIDs(benchmarkCapacity * 2)
+	var data interface{} = "data"
+	for _, uid := range uids {
+		_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: uid, Data: data})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: uids[i%len(uids)], Data: data})
+	}
+}
+
+// Updates existing Records.
+func Benchmark_AddRecord_Update(b *testing.B) {
+	var cache = NewFixedSizeBubbleCache(benchmarkCapacity, 60)
+	var uids = makeBenchmarkUIDs(benchmarkCapacity)
+	var data interface{} = "data"
+	for _, uid := range uids {
+		_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: uid, Data: data})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: uids[i%len(uids)], Data: data})
+	}
+}
+
+// Requests existing Records.
+func Benchmark_GetActualRecordDataByUID(b *testing.B) {
+	var cache = NewFixedSizeBubbleCache(benchmarkCapacity, 60)
+	var uids = makeBenchmarkUIDs(benchmarkCapacity)
+	var data interface{} = "data"
+	for _, uid := range uids {
+		_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: uid, Data: data})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = cache.GetActualRecordDataByUID(uids[i%len(uids)])
+	}
+}
@@ -0,0 +1,33 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+// A backing Store of the Cache, e.g. a Database.
+//
+// If the Data with the specified UID does not exist in the Store, the 'Get'
+// Method must return null Data without an Error.
+type Store interface {
+	Get(uid FixedSizeBubbleCacheRecordUID) (data interface{}, err error)
+	Put(uid FixedSizeBubbleCacheRecordUID, data interface{}) (err error)
+	Delete(uid FixedSizeBubbleCacheRecordUID) (err error)
+}
+
+// Write Mode of the Cache backed by a Store.
+type WriteMode byte
+
+const (
+	// Each Change is written to the Store synchronously, before it is
+	// applied to the Cache.
+	WriteModeThrough = WriteMode(1)
+
+	// Each Change is applied to the Cache and is queued. Queued Changes are
+	// written to the Store periodically, in Batches.
+	WriteModeBehind = WriteMode(2)
+)
+
+// A pending Change of the Store.
+type storeChange struct {
+
+	// Null Data means Deletion.
+	data interface{}
+}
@@ -0,0 +1,477 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Default Settings of the Write-Behind Mode.
+const (
+	WriteBehindIntervalDefault     = time.Second
+	WriteBehindMaxBatchSizeDefault = 100
+	WriteBehindMaxRetriesDefault   = 3
+	WriteBehindRetryDelayDefault   = time.Millisecond * 100
+)
+
+// Settings of the Write-Behind Mode.
+// Zero Values select the default Settings.
+type WriteBehindSettings struct {
+
+	// Period of Time between periodic Flushes of queued Changes.
+	Interval time.Duration
+
+	// Maximum Count of Changes written to the Store in a single Batch.
+	// When the Queue reaches this Size, a Flush is started without waiting
+	// for the Interval to pass.
+	MaxBatchSize int
+
+	// Maximum Count of Retries of a failed Write.
+	// A negative Value disables Retries.
+	MaxRetries int
+
+	// Delay before the first Retry. Each next Retry doubles the Delay.
+	RetryDelay time.Duration
+}
+
+// A fixed-Size Bubble Cache wrapped around a backing Store.
+//
+// Additions and Deletions are written to the Store either synchronously
+// (Write-Through) or asynchronously (Write-Behind). Requests of missing Records
+// are read from the Store.
+type StoreBackedCache struct {
+	cache *FixedSizeBubbleCache
+	store Store
+	mode  WriteMode
+
+	// Write-Behind State.
+	settings      WriteBehindSettings
+	lock          sync.Mutex
+	pending       map[FixedSizeBubbleCacheRecordUID]*storeChange
+	pendingOrder  []FixedSizeBubbleCacheRecordUID
+	inFlight      map[FixedSizeBubbleCacheRecordUID]*storeChange
+	flushLock     sync.Mutex
+	flushRequests chan struct{}
+	stop          chan struct{}
+	flusherIsDone chan struct{}
+	isClosed      bool
+}
+
+// Creates a new Cache which writes all Changes to the Store synchronously.
+//
+// The Cache's Loader is replaced with a Loader reading from the Store.
+func NewWriteThroughCache(
+	cache *FixedSizeBubbleCache,
+	store Store,
+) (sbc *StoreBackedCache, err error) {
+	sbc, err = newStoreBackedCache(cache, store, WriteModeThrough)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// Creates a new Cache which queues Changes and writes them to the Store
+// periodically. The Cache must be closed after Use to write all the queued
+// Changes.
+//
+// The Cache's Loader is replaced with a Loader reading from the Store.
+func NewWriteBehindCache(
+	cache *FixedSizeBubbleCache,
+	store Store,
+	settings WriteBehindSettings,
+) (sbc *StoreBackedCache, err error) {
+	sbc, err = newStoreBackedCache(cache, store, WriteModeBehind)
+	if err != nil {
+		return
+	}
+
+	if settings.Interval <= 0 {
+		settings.Interval = WriteBehindIntervalDefault
+	}
+	if settings.MaxBatchSize <= 0 {
+		settings.MaxBatchSize = WriteBehindMaxBatchSizeDefault
+	}
+	if settings.MaxRetries == 0 {
+		settings.MaxRetries = WriteBehindMaxRetriesDefault
+	} else if settings.MaxRetries < 0 {
+		settings.MaxRetries = 0
+	}
+	if settings.RetryDelay <= 0 {
+		settings.RetryDelay = WriteBehindRetryDelayDefault
+	}
+	sbc.settings = settings
+	sbc.pending = make(map[FixedSizeBubbleCacheRecordUID]*storeChange)
+	sbc.pendingOrder = make([]FixedSizeBubbleCacheRecordUID, 0, settings.MaxBatchSize)
+	sbc.inFlight = make(map[FixedSizeBubbleCacheRecordUID]*storeChange)
+	sbc.flushRequests = make(chan struct{}, 1)
+	sbc.stop = make(chan struct{})
+	sbc.flusherIsDone = make(chan struct{})
+
+	go sbc.runFlusher()
+	return
+}
+
+// Creates a new Cache backed by a Store.
+func newStoreBackedCache(
+	cache *FixedSizeBubbleCache,
+	store Store,
+	mode WriteMode,
+) (sbc *StoreBackedCache, err error) {
+	if cache == nil {
+		err = errors.New(ErrCacheIsNotSet)
+		return
+	}
+	if store == nil {
+		err = errors.New(ErrStoreIsNotSet)
+		return
+	}
+
+	sbc = &StoreBackedCache{
+		cache: cache,
+		store: store,
+		mode:  mode,
+	}
+	cache.SetLoader(sbc.load)
+	return
+}
+
+// Returns the wrapped Cache.
+func (sbc *StoreBackedCache) Cache() *FixedSizeBubbleCache {
+	return sbc.cache
+}
+
+// Checks the Record's Parameters, adds it to the Cache and writes it to the
+// Store.
+func (sbc *StoreBackedCache) AddRecord(
+	record *FixedSizeBubbleCacheRecord,
+) (err error) {
+
+	// Checks.
+	if record == nil {
+		return errors.New(ErrRecordIsNotSet)
+	}
+	err = record.Check()
+	if err != nil {
+		return
+	}
+
+	if sbc.mode == WriteModeThrough {
+		err = sbc.store.Put(record.UID, record.Data)
+		if err != nil {
+			return
+		}
+		return sbc.cache.AddRecord(record)
+	}
+
+	return sbc.enqueue(
+		record.UID,
+		record.Data,
+		func() error { return sbc.cache.AddRecord(record) },
+	)
+}
+
+// Deletes a Record specified by its UID from the Cache and from the Store.
+func (sbc *StoreBackedCache) DeleteRecordByUID(
+	uid FixedSizeBubbleCacheRecordUID,
+) (err error) {
+	// The Record may be absent in the Cache while it exists in the Store.
+	var deleteFromCache = func() error {
+		_ = sbc.cache.DeleteRecordByUID(uid)
+		return nil
+	}
+
+	if sbc.mode == WriteModeThrough {
+		err = sbc.store.Delete(uid)
+		if err != nil {
+			return
+		}
+		return deleteFromCache()
+	}
+
+	return sbc.enqueue(uid, nil, deleteFromCache)
+}
+
+// Gets the Record's Data by its UID. Missing Records are read from the Store.
+func (sbc *StoreBackedCache) GetActualRecordDataByUID(
+	uid FixedSizeBubbleCacheRecordUID,
+) (data interface{}, err error) {
+	return sbc.cache.GetOrLoadRecordDataByUID(uid)
+}
+
+// Reads the Record's Data from the Store. Queued Changes and Changes which
+// are being written are newer than the Store's Contents, so they are used
+// first. A queued Deletion means that the Record does not exist.
+func (sbc *StoreBackedCache) load(
+	uid FixedSizeBubbleCacheRecordUID,
+) (data interface{}, err error) {
+	if sbc.mode == WriteModeBehind {
+		var change, changeExists = sbc.unwrittenChange(uid)
+		if changeExists {
+			if change.data == nil {
+				return nil, fmt.Errorf(ErrfRecordWithUidIsNotFound, uid)
+			}
+			return change.data, nil
+		}
+	}
+
+	return sbc.store.Get(uid)
+}
+
+// Returns the newest Change of the Record which is not written to the Store
+// yet: either a queued Change or a Change which is being written.
+func (sbc *StoreBackedCache) unwrittenChange(
+	uid FixedSizeBubbleCacheRecordUID,
+) (change *storeChange, changeExists bool) {
+	sbc.lock.Lock()
+	defer sbc.lock.Unlock()
+
+	change, changeExists = sbc.pending[uid]
+	if changeExists {
+		return
+	}
+	change, changeExists = sbc.inFlight[uid]
+	return
+}
+
+// Applies a Change to the Cache and queues it. A previously queued Change of
+// the same Record is replaced. Null Data means Deletion. Both Steps are made
+// under the Lock, so that a concurrent Flush or Close can not come between
+// them, and a closed Cache is not changed at all.
+func (sbc *StoreBackedCache) enqueue(
+	uid FixedSizeBubbleCacheRecordUID,
+	data interface{},
+	applyToCache func() error,
+) (err error) {
+	sbc.lock.Lock()
+
+	if sbc.isClosed {
+		sbc.lock.Unlock()
+		return errors.New(ErrCacheIsClosed)
+	}
+
+	err = applyToCache()
+	if err != nil {
+		sbc.lock.Unlock()
+		return
+	}
+
+	var change, changeExists = sbc.pending[uid]
+	if changeExists {
+		change.data = data
+	} else {
+		sbc.pending[uid] = &storeChange{data: data}
+		sbc.pendingOrder = append(sbc.pendingOrder, uid)
+	}
+	var batchIsFull = len(sbc.pendingOrder) >= sbc.settings.MaxBatchSize
+	sbc.lock.Unlock()
+
+	if batchIsFull {
+		select {
+		case sbc.flushRequests <- struct{}{}:
+		default:
+		}
+	}
+	return
+}
+
+// Writes all the queued Changes to the Store.
+// Changes which could not be written are kept in the Queue.
+func (sbc *StoreBackedCache) Flush() (err error) {
+	if sbc.mode != WriteModeBehind {
+		return
+	}
+
+	sbc.flushLock.Lock()
+	defer sbc.flushLock.Unlock()
+
+	return sbc.flush()
+}
+
+// Stops the periodic Flushes and writes all the queued Changes to the Store.
+func (sbc *StoreBackedCache) Close() (err error) {
+	if sbc.mode != WriteModeBehind {
+		return
+	}
+
+	sbc.lock.Lock()
+	if sbc.isClosed {
+		sbc.lock.Unlock()
+		return errors.New(ErrCacheIsClosed)
+	}
+	sbc.isClosed = true
+	sbc.lock.Unlock()
+
+	close(sbc.stop)
+	<-sbc.flusherIsDone
+
+	return sbc.Flush()
+}
+
+// Flushes the queued Changes periodically and on Demand.
+func (sbc *StoreBackedCache) runFlusher() {
+	defer close(sbc.flusherIsDone)
+
+	var ticker = time.NewTicker(sbc.settings.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sbc.stop:
+			return
+		case <-ticker.C:
+		case <-sbc.flushRequests:
+		}
+
+		// Failed Changes stay in the Queue until the next Flush.
+		_ = sbc.Flush()
+	}
+}
+
+// Writes the queued Changes to the Store Batch by Batch.
+// The Flush Lock must be held by the Caller.
+func (sbc *StoreBackedCache) flush() (err error) {
+	var failuresCount int
+	for {
+		var batch = sbc.takeBatch()
+		if batch.Len() == 0 {
+			break
+		}
+
+		for _, uid := range batch.uids {
+			var change = batch.changes[uid]
+			var writeErr = sbc.write(uid, change)
+			if writeErr == nil {
+				sbc.release(uid, change)
+				continue
+			}
+
+			failuresCount++
+			err = writeErr
+			sbc.requeue(uid, change)
+		}
+
+		// Failed Changes are not retried within the same Flush.
+		if failuresCount > 0 {
+			break
+		}
+	}
+
+	if failuresCount > 0 {
+		err = fmt.Errorf(ErrfStoreFlushFailure, failuresCount, err)
+	}
+	return
+}
+
+// A Batch of Changes taken from the Queue.
+type storeChangeBatch struct {
+	uids    []FixedSizeBubbleCacheRecordUID
+	changes map[FixedSizeBubbleCacheRecordUID]*storeChange
+}
+
+// Returns the Count of Changes in the Batch.
+func (b storeChangeBatch) Len() int {
+	return len(b.uids)
+}
+
+// Takes the oldest queued Changes out of the Queue. The Changes stay visible
+// to the Loader as being written, until they are released or requeued.
+func (sbc *StoreBackedCache) takeBatch() (batch storeChangeBatch) {
+	sbc.lock.Lock()
+	defer sbc.lock.Unlock()
+
+	var n = len(sbc.pendingOrder)
+	if n > sbc.settings.MaxBatchSize {
+		n = sbc.settings.MaxBatchSize
+	}
+
+	batch.uids = make([]FixedSizeBubbleCacheRecordUID, n)
+	copy(batch.uids, sbc.pendingOrder[:n])
+	batch.changes = make(map[FixedSizeBubbleCacheRecordUID]*storeChange, n)
+	for _, uid := range batch.uids {
+		batch.changes[uid] = sbc.pending[uid]
+		sbc.inFlight[uid] = sbc.pending[uid]
+		delete(sbc.pending, uid)
+	}
+	sbc.pendingOrder = append(sbc.pendingOrder[:0], sbc.pendingOrder[n:]...)
+	return
+}
+
+// Forgets the written Change, unless a newer Change of the same Record is
+// being written.
+func (sbc *StoreBackedCache) release(
+	uid FixedSizeBubbleCacheRecordUID,
+	change *storeChange,
+) {
+	sbc.lock.Lock()
+	defer sbc.lock.Unlock()
+
+	sbc.releaseChange(uid, change)
+}
+
+// Forgets the written Change. The Lock must be held by the Caller.
+func (sbc *StoreBackedCache) releaseChange(
+	uid FixedSizeBubbleCacheRecordUID,
+	change *storeChange,
+) {
+	if sbc.inFlight[uid] == change {
+		delete(sbc.inFlight, uid)
+	}
+}
+
+// Returns a failed Change into the Queue, unless a newer Change of the same
+// Record has been queued already.
+func (sbc *StoreBackedCache) requeue(
+	uid FixedSizeBubbleCacheRecordUID,
+	change *storeChange,
+) {
+	sbc.lock.Lock()
+	defer sbc.lock.Unlock()
+
+	sbc.releaseChange(uid, change)
+	var _, newerChangeExists = sbc.pending[uid]
+	if newerChangeExists {
+		return
+	}
+	sbc.pending[uid] = change
+	sbc.pendingOrder = append(sbc.pendingOrder, uid)
+}
+
+// Writes a single Change to the Store, retrying with an exponential Backoff.
+func (sbc *StoreBackedCache) write(
+	uid FixedSizeBubbleCacheRecordUID,
+	change *storeChange,
+) (err error) {
+	var delay = sbc.settings.RetryDelay
+	for attempt := 0; attempt <= sbc.settings.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+
+		if change.data == nil {
+			err = sbc.store.Delete(uid)
+		} else {
+			err = sbc.store.Put(uid, change.data)
+		}
+		if err == nil {
+			return
+		}
+	}
+	return
+}
+
+// Returns the Count of queued Changes.
+func (sbc *StoreBackedCache) PendingChangesCount() int {
+	if sbc.mode != WriteModeBehind {
+		return 0
+	}
+
+	sbc.lock.Lock()
+	defer sbc.lock.Unlock()
+
+	return len(sbc.pendingOrder)
+}
@@ -0,0 +1,260 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/tester"
+)
+
+// A Store kept in Memory, used in Tests.
+type testStore struct {
+	lock     sync.Mutex
+	data     map[FixedSizeBubbleCacheRecordUID]interface{}
+	failures int
+	puts     int
+	deletes  int
+}
+
+func newTestStore() *testStore {
+	return &testStore{data: make(map[FixedSizeBubbleCacheRecordUID]interface{})}
+}
+
+func (s *testStore) Get(uid FixedSizeBubbleCacheRecordUID) (interface{}, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.data[uid], nil
+}
+
+func (s *testStore) Put(uid FixedSizeBubbleCacheRecordUID, data interface{}) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("store failure")
+	}
+	s.puts++
+	s.data[uid] = data
+	return nil
+}
+
+func (s *testStore) Delete(uid FixedSizeBubbleCacheRecordUID) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("store failure")
+	}
+	s.deletes++
+	delete(s.data, uid)
+	return nil
+}
+
+func Test_NewWriteThroughCache(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var err error
+
+	// Test #1. No Cache.
+	_, err = NewWriteThroughCache(nil, newTestStore())
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrCacheIsNotSet)
+
+	// Test #2. No Store.
+	_, err = NewWriteThroughCache(NewFixedSizeBubbleCache(2, 60), nil)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrStoreIsNotSet)
+}
+
+func Test_StoreBackedCache_WriteThrough(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var store = newTestStore()
+	var sbc *StoreBackedCache
+	var data interface{}
+	var err error
+	sbc, err = NewWriteThroughCache(NewFixedSizeBubbleCache(1, 60), store)
+	aTest.MustBeNoError(err)
+
+	// Test #1. Addition is written to the Store.
+	err = sbc.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(store.data["a"], 1)
+
+	// Test #2. Failed Write is not cached.
+	store.failures = 1
+	err = sbc.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(sbc.Cache().RecordUIDExists("b"), false)
+
+	// Test #3. Evicted Record is read from the Store.
+	err = sbc.AddRecord(&FixedSizeBubbleCacheRecord{UID: "c", Data: 3})
+	aTest.MustBeNoError(err)
+	data, err = sbc.GetActualRecordDataByUID("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, 1)
+
+	// Test #4. Deletion.
+	err = sbc.DeleteRecordByUID("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(sbc.Cache().RecordUIDExists("a"), false)
+	_, ok := store.data["a"]
+	aTest.MustBeEqual(ok, false)
+}
+
+func Test_StoreBackedCache_WriteBehind(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var store = newTestStore()
+	var sbc *StoreBackedCache
+	var data interface{}
+	var err error
+	sbc, err = NewWriteBehindCache(
+		NewFixedSizeBubbleCache(1, 60),
+		store,
+		WriteBehindSettings{
+			Interval:     time.Hour,
+			MaxBatchSize: 10,
+			MaxRetries:   1,
+			RetryDelay:   time.Millisecond,
+		},
+	)
+	aTest.MustBeNoError(err)
+
+	// Test #1. Changes are queued and coalesced.
+	err = sbc.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1})
+	aTest.MustBeNoError(err)
+	err = sbc.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 2})
+	aTest.MustBeNoError(err)
+	err = sbc.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 3})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(sbc.PendingChangesCount(), 2)
+	aTest.MustBeEqual(len(store.data), 0)
+
+	// Test #2. Evicted Record is read from the Queue.
+	data, err = sbc.GetActualRecordDataByUID("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, 2)
+
+	// Test #3. Flush.
+	err = sbc.Flush()
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(sbc.PendingChangesCount(), 0)
+	aTest.MustBeEqual(store.data["a"], 2)
+	aTest.MustBeEqual(store.data["b"], 3)
+	aTest.MustBeEqual(store.puts, 2)
+
+	// Test #4. Retry succeeds.
+	err = sbc.DeleteRecordByUID("a")
+	aTest.MustBeNoError(err)
+	store.failures = 1
+	err = sbc.Flush()
+	aTest.MustBeNoError(err)
+	_, ok := store.data["a"]
+	aTest.MustBeEqual(ok, false)
+
+	// Test #5. Retries are exhausted, the Change stays queued.
+	err = sbc.AddRecord(&FixedSizeBubbleCacheRecord{UID: "c", Data: 4})
+	aTest.MustBeNoError(err)
+	store.failures = 2
+	err = sbc.Flush()
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(sbc.PendingChangesCount(), 1)
+
+	// Test #6. Close writes the Rest.
+	err = sbc.Close()
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(store.data["c"], 4)
+	err = sbc.AddRecord(&FixedSizeBubbleCacheRecord{UID: "d", Data: 5})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrCacheIsClosed)
+
+	// Test #7. A closed Cache is not changed.
+	aTest.MustBeEqual(sbc.Cache().RecordUIDExists("d"), false)
+	aTest.MustBeEqual(sbc.Cache().RecordUIDExists("c"), true)
+	err = sbc.DeleteRecordByUID("c")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrCacheIsClosed)
+	aTest.MustBeEqual(sbc.Cache().RecordUIDExists("c"), true)
+}
+
+func Test_StoreBackedCache_WriteBehindBatch(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var store = newTestStore()
+	var sbc *StoreBackedCache
+	var err error
+	sbc, err = NewWriteBehindCache(
+		NewFixedSizeBubbleCache(10, 60),
+		store,
+		WriteBehindSettings{
+			Interval:     time.Hour,
+			MaxBatchSize: 2,
+		},
+	)
+	aTest.MustBeNoError(err)
+
+	// Test #1. A full Batch is flushed without waiting for the Interval.
+	_ = sbc.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1})
+	_ = sbc.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2})
+	for i := 0; (i < 100) && (sbc.PendingChangesCount() > 0); i++ {
+		time.Sleep(time.Millisecond * 10)
+	}
+	aTest.MustBeEqual(sbc.PendingChangesCount(), 0)
+	store.lock.Lock()
+	aTest.MustBeEqual(store.puts, 2)
+	store.lock.Unlock()
+
+	err = sbc.Close()
+	aTest.MustBeNoError(err)
+}
+
+// A Store which holds Deletions until they are released, used in Tests.
+type blockingTestStore struct {
+	*testStore
+	deletionIsStarted  chan struct{}
+	deletionIsReleased chan struct{}
+}
+
+func (s *blockingTestStore) Delete(uid FixedSizeBubbleCacheRecordUID) error {
+	s.deletionIsStarted <- struct{}{}
+	<-s.deletionIsReleased
+	return s.testStore.Delete(uid)
+}
+
+func Test_StoreBackedCache_WriteBehindUnwrittenChanges(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var store = &blockingTestStore{
+		testStore:          newTestStore(),
+		deletionIsStarted:  make(chan struct{}),
+		deletionIsReleased: make(chan struct{}),
+	}
+	store.data["a"] = 1
+	var cache = NewFixedSizeBubbleCache(2, 60)
+	cache.SetNegativeRecordTTL(60)
+	var sbc, err = NewWriteBehindCache(cache, store, WriteBehindSettings{Interval: time.Hour})
+	aTest.MustBeNoError(err)
+
+	// Test #1. A queued Deletion is not loaded as a Record.
+	aTest.MustBeNoError(sbc.DeleteRecordByUID("a"))
+	_, err = sbc.GetActualRecordDataByUID("a")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(cache.RecordUIDExists("a"), false)
+
+	// Test #2. A Deletion which is being written hides the old Data.
+	var flushErr = make(chan error)
+	go func() {
+		flushErr <- sbc.Flush()
+	}()
+	<-store.deletionIsStarted
+	aTest.MustBeEqual(sbc.PendingChangesCount(), 0)
+	_, err = sbc.GetActualRecordDataByUID("a")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(cache.RecordUIDExists("a"), false)
+	close(store.deletionIsReleased)
+	aTest.MustBeNoError(<-flushErr)
+
+	// Test #3. The written Deletion is forgotten.
+	aTest.MustBeEqual(len(sbc.inFlight), 0)
+	aTest.MustBeNoError(sbc.Close())
+}
@@ -0,0 +1,317 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// A fixed-Size Bubble Cache with a second Tier on the local Disk.
+//
+// Records evicted from the Bottom of the Cache are written to the Disk Tier.
+// Requests of Records missing in the Cache check the Disk Tier, and the found
+// Records are promoted back to the Top of the Cache.
+//
+// The Eviction Handler runs under the Lock of the Cache, so evicted Records
+// are only queued there. They are written to the Disk by a background Writer
+// and by the Methods of the two-Tier Cache after the Lock is released.
+type TwoTierCache struct {
+	cache     *FixedSizeBubbleCache
+	tier      *DiskTier
+	queueSize int
+
+	// Queue of evicted Records.
+	lock          sync.Mutex
+	spilled       []spilledRecord
+	dropsCount    uint64
+	isClosed      bool
+	writeLock     sync.Mutex
+	writeRequests chan struct{}
+	stop          chan struct{}
+	writerIsDone  chan struct{}
+}
+
+// A Record evicted from the Cache which waits to be written to the Disk Tier.
+type spilledRecord struct {
+	uid            FixedSizeBubbleCacheRecordUID
+	data           interface{}
+	ttl            uint
+	expirationTime time.Time
+}
+
+// Creates a new two-Tier Cache. The Disk Tier uses the Codec of the Cache.
+//
+// The Cache's Eviction Handler is replaced with a Handler writing to the Disk
+// Tier. Records popped or drained from the Cache are written there as well.
+// The two-Tier Cache must be closed after Use to stop its Writer.
+func NewTwoTierCache(
+	cache *FixedSizeBubbleCache,
+	settings DiskTierSettings,
+) (ttc *TwoTierCache, err error) {
+	if cache == nil {
+		err = errors.New(ErrCacheIsNotSet)
+		return
+	}
+
+	var tier *DiskTier
+	tier, err = NewDiskTier(cache.GetCodec(), settings)
+	if err != nil {
+		return
+	}
+
+	if settings.QueueSize <= 0 {
+		settings.QueueSize = DiskTierQueueSizeDefault
+	}
+
+	ttc = &TwoTierCache{
+		cache:         cache,
+		tier:          tier,
+		queueSize:     settings.QueueSize,
+		writeRequests: make(chan struct{}, 1),
+		stop:          make(chan struct{}),
+		writerIsDone:  make(chan struct{}),
+	}
+	cache.SetEvictionHandler(ttc.spill)
+
+	go ttc.runWriter()
+	return
+}
+
+// Returns the wrapped Cache.
+func (ttc *TwoTierCache) Cache() *FixedSizeBubbleCache {
+	return ttc.cache
+}
+
+// Returns the Disk Tier.
+func (ttc *TwoTierCache) DiskTier() *DiskTier {
+	return ttc.tier
+}
+
+// Checks the Record's Parameters and adds it to the Cache. An older Copy of
+// the Record is deleted from the Disk Tier.
+func (ttc *TwoTierCache) AddRecord(
+	record *FixedSizeBubbleCacheRecord,
+) (err error) {
+	return ttc.AddRecordWithTTL(record, 0)
+}
+
+// Checks the Record's Parameters and adds it to the Cache with an individual
+// TTL. An older Copy of the Record is deleted from the Disk Tier.
+func (ttc *TwoTierCache) AddRecordWithTTL(
+	record *FixedSizeBubbleCacheRecord,
+	ttl uint,
+) (err error) {
+	err = ttc.cache.AddRecordWithTTL(record, ttl)
+	if err != nil {
+		return
+	}
+
+	ttc.writeLock.Lock()
+	defer ttc.writeLock.Unlock()
+
+	// The Record is usually absent in the Disk Tier.
+	ttc.writeSpilled()
+	_ = ttc.tier.Delete(record.UID)
+	return
+}
+
+// Gets the Record's Data by its UID. If the Record is missing in the Cache,
+// it is taken from the Disk Tier and is moved to the Top of the Cache.
+func (ttc *TwoTierCache) GetActualRecordDataByUID(
+	uid FixedSizeBubbleCacheRecordUID,
+) (data interface{}, err error) {
+	data, err = ttc.cache.GetActualRecordDataByUID(uid)
+	if (err == nil) || ttc.cache.RecordUIDExists(uid) {
+		// Actual or negative Record.
+		return
+	}
+
+	var cacheErr = err
+	var ttl uint
+	var expirationTime time.Time
+	ttc.writeLock.Lock()
+	ttc.writeSpilled()
+	data, ttl, expirationTime, err = ttc.tier.Take(uid)
+	ttc.writeLock.Unlock()
+	if err != nil {
+		return nil, cacheErr
+	}
+
+	data, err = ttc.promote(uid, data, ttl, expirationTime)
+	ttc.Flush()
+	return
+}
+
+// Moves the Record taken from the Disk Tier to the Top of the Cache. If the
+// Record has been added to the Cache in the Meantime, the Cache's Record is
+// newer, so it is used.
+func (ttc *TwoTierCache) promote(
+	uid FixedSizeBubbleCacheRecordUID,
+	data interface{},
+	ttl uint,
+	expirationTime time.Time,
+) (promotedData interface{}, err error) {
+	var c = ttc.cache
+	c.lock.Lock()
+
+	if c.recordUIDExists(uid) {
+		c.lock.Unlock()
+		return c.GetActualRecordDataByUID(uid)
+	}
+
+	var record = &FixedSizeBubbleCacheRecord{
+		UID:  uid,
+		Data: data,
+		ttl:  ttl,
+	}
+	record.SetExpirationTime(expirationTime)
+	c.addRecord(record)
+	c.lock.Unlock()
+
+	return data, nil
+}
+
+// Deletes a Record specified by its UID from both Tiers.
+func (ttc *TwoTierCache) DeleteRecordByUID(
+	uid FixedSizeBubbleCacheRecordUID,
+) (err error) {
+	var cacheErr = ttc.cache.DeleteRecordByUID(uid)
+
+	ttc.writeLock.Lock()
+	ttc.writeSpilled()
+	var tierErr = ttc.tier.Delete(uid)
+	ttc.writeLock.Unlock()
+	if (cacheErr == nil) || (tierErr == nil) {
+		return nil
+	}
+	return cacheErr
+}
+
+// Checks whether the specified Record's UID exists in any Tier.
+func (ttc *TwoTierCache) RecordUIDExists(
+	uid FixedSizeBubbleCacheRecordUID,
+) (uidExists bool) {
+	if ttc.cache.RecordUIDExists(uid) {
+		return true
+	}
+
+	ttc.writeLock.Lock()
+	defer ttc.writeLock.Unlock()
+
+	ttc.writeSpilled()
+	return ttc.tier.RecordUIDExists(uid)
+}
+
+// Deletes all Records from both Tiers.
+func (ttc *TwoTierCache) Clear() (err error) {
+	err = ttc.cache.Clear()
+	if err != nil {
+		return
+	}
+
+	ttc.writeLock.Lock()
+	defer ttc.writeLock.Unlock()
+
+	ttc.takeSpilled()
+	return ttc.tier.Clear()
+}
+
+// Writes the queued evicted Records to the Disk Tier. Failures are counted in
+// the Statistics of the Disk Tier.
+func (ttc *TwoTierCache) Flush() {
+	ttc.writeLock.Lock()
+	defer ttc.writeLock.Unlock()
+
+	ttc.writeSpilled()
+}
+
+// Detaches the Disk Tier from the Cache, stops the Writer and deletes the
+// Files of Records. Queued Records are discarded.
+func (ttc *TwoTierCache) Close() (err error) {
+	ttc.lock.Lock()
+	if ttc.isClosed {
+		ttc.lock.Unlock()
+		return errors.New(ErrCacheIsClosed)
+	}
+	ttc.isClosed = true
+	ttc.lock.Unlock()
+
+	ttc.cache.SetEvictionHandler(nil)
+	close(ttc.stop)
+	<-ttc.writerIsDone
+
+	ttc.writeLock.Lock()
+	defer ttc.writeLock.Unlock()
+
+	ttc.takeSpilled()
+	return ttc.tier.Clear()
+}
+
+// Queues a Record evicted from the Cache. The Handler runs under the Lock of
+// the Cache, so the Record is written later, outside of that Lock. When the
+// Queue is full, the Record is dropped.
+func (ttc *TwoTierCache) spill(
+	uid FixedSizeBubbleCacheRecordUID,
+	data interface{},
+	ttl uint,
+	expirationTime time.Time,
+) {
+	ttc.lock.Lock()
+	if len(ttc.spilled) >= ttc.queueSize {
+		ttc.dropsCount++
+		ttc.lock.Unlock()
+		return
+	}
+	ttc.spilled = append(ttc.spilled, spilledRecord{
+		uid:            uid,
+		data:           data,
+		ttl:            ttl,
+		expirationTime: expirationTime,
+	})
+	ttc.lock.Unlock()
+
+	select {
+	case ttc.writeRequests <- struct{}{}:
+	default:
+	}
+}
+
+// Takes the queued Records and the Count of dropped Records from the Queue.
+func (ttc *TwoTierCache) takeSpilled() (records []spilledRecord, dropsCount uint64) {
+	ttc.lock.Lock()
+	defer ttc.lock.Unlock()
+
+	records, dropsCount = ttc.spilled, ttc.dropsCount
+	ttc.spilled, ttc.dropsCount = nil, 0
+	return
+}
+
+// Writes the queued Records to the Disk Tier in the Order of their Eviction.
+// The Write Lock must be held by the Caller, so that the Disk Tier is not
+// changed by other Methods between taking the Records and writing them.
+func (ttc *TwoTierCache) writeSpilled() {
+	var records, dropsCount = ttc.takeSpilled()
+	if dropsCount > 0 {
+		ttc.tier.countDrops(dropsCount)
+	}
+	for _, record := range records {
+		_ = ttc.tier.Put(record.uid, record.data, record.ttl, record.expirationTime)
+	}
+}
+
+// Writes the queued Records on Demand until the two-Tier Cache is closed.
+func (ttc *TwoTierCache) runWriter() {
+	defer close(ttc.writerIsDone)
+
+	for {
+		select {
+		case <-ttc.stop:
+			return
+		case <-ttc.writeRequests:
+		}
+
+		ttc.Flush()
+	}
+}
@@ -0,0 +1,158 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/tester"
+)
+
+func Test_NewTwoTierCache(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var err error
+
+	// Test #1. No Cache.
+	_, err = NewTwoTierCache(nil, DiskTierSettings{Directory: t.TempDir()})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrCacheIsNotSet)
+
+	// Test #2. No Directory.
+	_, err = NewTwoTierCache(NewFixedSizeBubbleCache(2, 60), DiskTierSettings{})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrDiskTierDirectoryIsNotSet)
+}
+
+func Test_TwoTierCache(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var ttc, err = NewTwoTierCache(NewFixedSizeBubbleCache(2, 60), DiskTierSettings{Directory: t.TempDir()})
+	aTest.MustBeNoError(err)
+	var data interface{}
+
+	// Test #1. Evicted Records are written to the Disk Tier.
+	aTest.MustBeNoError(ttc.AddRecordWithTTL(&FixedSizeBubbleCacheRecord{UID: "a", Data: "A"}, 30))
+	aTest.MustBeNoError(ttc.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: "B"}))
+	aTest.MustBeNoError(ttc.AddRecord(&FixedSizeBubbleCacheRecord{UID: "c", Data: "C"}))
+	aTest.MustBeEqual(ttc.Cache().RecordUIDExists("a"), false)
+	aTest.MustBeEqual(ttc.DiskTier().RecordUIDExists("a"), true)
+	aTest.MustBeEqual(ttc.RecordUIDExists("a"), true)
+
+	// Test #2. Records are promoted back to the Top with their TTL.
+	data, err = ttc.GetActualRecordDataByUID("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "A")
+	aTest.MustBeEqual(ttc.Cache().top.UID, "a")
+	aTest.MustBeEqual(ttc.Cache().top.ttl, uint(30))
+	aTest.MustBeEqual(ttc.DiskTier().RecordUIDExists("a"), false)
+	aTest.MustBeEqual(ttc.DiskTier().RecordUIDExists("b"), true)
+
+	// Test #3. Records missing in both Tiers.
+	_, err = ttc.GetActualRecordDataByUID("x")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfRecordWithUidIsNotFound, "x"))
+
+	// Test #4. Update deletes the old Copy from the Disk Tier.
+	aTest.MustBeNoError(ttc.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: "B2"}))
+	aTest.MustBeEqual(ttc.DiskTier().RecordUIDExists("b"), false)
+	aTest.MustBeEqual(ttc.DiskTier().RecordUIDExists("c"), true)
+
+	// Test #5. Deletion from any Tier.
+	aTest.MustBeNoError(ttc.DeleteRecordByUID("c"))
+	aTest.MustBeNoError(ttc.DeleteRecordByUID("b"))
+	aTest.MustBeEqual(ttc.RecordUIDExists("b"), false)
+	aTest.MustBeEqual(ttc.RecordUIDExists("c"), false)
+	err = ttc.DeleteRecordByUID("c")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfRecordWithUidIsNotFound, "c"))
+
+	// Test #6. Clear and Close.
+	aTest.MustBeNoError(ttc.AddRecord(&FixedSizeBubbleCacheRecord{UID: "d", Data: "D"}))
+	aTest.MustBeNoError(ttc.AddRecord(&FixedSizeBubbleCacheRecord{UID: "e", Data: "E"}))
+	aTest.MustBeNoError(ttc.Clear())
+	aTest.MustBeEqual(ttc.RecordUIDExists("a"), false)
+	aTest.MustBeEqual(ttc.RecordUIDExists("d"), false)
+	aTest.MustBeNoError(ttc.Close())
+	aTest.MustBeNoError(ttc.Cache().AddRecord(&FixedSizeBubbleCacheRecord{UID: "f", Data: "F"}))
+	aTest.MustBeNoError(ttc.Cache().AddRecord(&FixedSizeBubbleCacheRecord{UID: "g", Data: "G"}))
+	aTest.MustBeNoError(ttc.Cache().AddRecord(&FixedSizeBubbleCacheRecord{UID: "h", Data: "H"}))
+	aTest.MustBeEqual(ttc.DiskTier().Stats().Records, uint(0))
+}
+
+func Test_TwoTierCache_ExpirationTime(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var ttc, err = NewTwoTierCache(NewFixedSizeBubbleCache(1, 60), DiskTierSettings{Directory: t.TempDir()})
+	aTest.MustBeNoError(err)
+	var expirationTime = time.Unix(time.Now().Unix()+600, 0)
+
+	// Test #1. Promoted Records keep their absolute Expiration Time.
+	var record = &FixedSizeBubbleCacheRecord{UID: "a", Data: "A"}
+	record.SetExpirationTime(expirationTime)
+	aTest.MustBeNoError(ttc.AddRecord(record))
+	aTest.MustBeNoError(ttc.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: "B"}))
+	aTest.MustBeEqual(ttc.DiskTier().records["a"].expirationTime, expirationTime)
+	var data interface{}
+	data, err = ttc.GetActualRecordDataByUID("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "A")
+	aTest.MustBeEqual(ttc.Cache().top.expirationTime, uint(expirationTime.Unix()))
+
+	// Test #2. Records are not promoted after their Expiration.
+	expirationTime = time.Unix(time.Now().Unix()+1, 0)
+	record = &FixedSizeBubbleCacheRecord{UID: "c", Data: "C"}
+	record.SetExpirationTime(expirationTime)
+	aTest.MustBeNoError(ttc.AddRecord(record))
+	aTest.MustBeNoError(ttc.AddRecord(&FixedSizeBubbleCacheRecord{UID: "d", Data: "D"}))
+	aTest.MustBeEqual(ttc.DiskTier().RecordUIDExists("c"), true)
+	time.Sleep(time.Until(expirationTime))
+	_, err = ttc.GetActualRecordDataByUID("c")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfRecordWithUidIsNotFound, "c"))
+	aTest.MustBeEqual(ttc.Cache().RecordUIDExists("c"), false)
+	aTest.MustBeNoError(ttc.Close())
+}
+
+func Test_TwoTierCache_Queue(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var ttc, err = NewTwoTierCache(NewFixedSizeBubbleCache(2, 60), DiskTierSettings{Directory: t.TempDir(), QueueSize: 1})
+	aTest.MustBeNoError(err)
+	var data interface{}
+
+	// Test #1. Records evicted by the wrapped Cache are written by the Writer.
+	aTest.MustBeNoError(ttc.Cache().AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: "A"}))
+	aTest.MustBeNoError(ttc.Cache().AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: "B"}))
+	aTest.MustBeNoError(ttc.Cache().AddRecord(&FixedSizeBubbleCacheRecord{UID: "c", Data: "C"}))
+	for i := 0; (i < 100) && (ttc.DiskTier().Stats().Records == 0); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	aTest.MustBeEqual(ttc.DiskTier().RecordUIDExists("a"), true)
+
+	// Test #2. Queued Records are found before they are written.
+	ttc.writeLock.Lock()
+	_, err = ttc.Cache().PopBottom()
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(len(ttc.spilled), 1)
+	ttc.writeLock.Unlock()
+	data, err = ttc.GetActualRecordDataByUID("b")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "B")
+
+	// Test #3. Records evicted while the Queue is full are dropped.
+	ttc.writeLock.Lock()
+	aTest.MustBeNoError(ttc.Cache().AddRecord(&FixedSizeBubbleCacheRecord{UID: "d", Data: "D"}))
+	aTest.MustBeNoError(ttc.Cache().AddRecord(&FixedSizeBubbleCacheRecord{UID: "e", Data: "E"}))
+	ttc.writeLock.Unlock()
+	ttc.Flush()
+	var stats = ttc.DiskTier().Stats()
+	aTest.MustBeEqual(stats.Drops, uint64(1))
+	aTest.MustBeEqual(ttc.DiskTier().RecordUIDExists("c"), true)
+	aTest.MustBeEqual(ttc.DiskTier().RecordUIDExists("b"), false)
+
+	// Test #4. Close.
+	aTest.MustBeNoError(ttc.Close())
+	err = ttc.Close()
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrCacheIsClosed)
+	aTest.MustBeEqual(ttc.DiskTier().Stats().Records, uint(0))
+}
@@ -0,0 +1,697 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Write-Ahead Log Format.
+//
+// The Log starts with a Header: Magic (4 Bytes, 'FSBW') and Format Version
+// (uint16). The Header is followed by Entries. Each Entry is written as a
+// Snapshot Block (Payload Size, Payload, Checksum), where the Payload is an
+// Operation Code (1 Byte) followed by a Snapshot Record Payload. Entries of
+// Requests, Deletions and Pinnings have no Data. Version 2 has added individual
+// TTLs of Records, as in the Snapshot Format. Version 3 has added Entries of
+// Additions to the Bottom and Entries of Pinnings and Unpinnings, which carry
+// the Flag of pinned Records. Version 4 has added absolute Expiration Times
+// of Records. Version 5 has added Update Times of Records. Logs of unknown
+// Versions are rejected.
+const (
+	WriteAheadLogMagic         = "FSBW"
+	WriteAheadLogFormatVersion = uint16(5)
+
+	WriteAheadLogFileName         = "cache.wal"
+	WriteAheadLogSnapshotFileName = "cache.snapshot"
+)
+
+// Default Settings of the Write-Ahead Log.
+const (
+	WriteAheadLogSyncIntervalDefault       = time.Second
+	WriteAheadLogCompactionIntervalDefault = time.Minute * 10
+	WriteAheadLogMaxSizeDefault            = 64 * 1024 * 1024
+)
+
+// Operation Codes of the Write-Ahead Log.
+const (
+	walOperationAdd    = byte(1)
+	walOperationTouch  = byte(2)
+	walOperationDelete = byte(3)
+
+	walOperationAddAtBottom = byte(4)
+	walOperationPin         = byte(5)
+)
+
+// Size of the Header of the Write-Ahead Log.
+const walHeaderSize = 4 + 2
+
+// Settings of the Write-Ahead Log.
+// Zero Values select the default Settings.
+type WriteAheadLogSettings struct {
+
+	// Period of Time between Synchronizations of the Log with the Disk.
+	// This is the Window of Changes which may be lost in a Crash.
+	SyncInterval time.Duration
+
+	// Period of Time between periodic Compactions of the Log into a Snapshot.
+	CompactionInterval time.Duration
+
+	// Size of the Log in Bytes which starts a Compaction without waiting for
+	// the Compaction Interval to pass.
+	MaxSize int64
+}
+
+// An append-only Write-Ahead Log of a Cache.
+//
+// The Log records every Addition, Update, Request and Deletion applied to
+// the Cache. It is periodically compacted into a Snapshot. When the Log is
+// opened, the Snapshot and the Log are replayed, so that a restarted Process
+// recovers the Cache's Contents including the Order of Records.
+//
+// The Cache's Codec must not be changed while the Log is open.
+type WriteAheadLog struct {
+	cache         *FixedSizeBubbleCache
+	codec         Codec
+	logPath       string
+	snapshotPath  string
+	settings      WriteAheadLogSettings
+	lock          sync.Mutex
+	file          *os.File
+	writer        *bufio.Writer
+	size          int64
+	payload       []byte
+	journalingErr error
+	isClosed      bool
+
+	compactionLock     sync.Mutex
+	compactionRequests chan struct{}
+	stop               chan struct{}
+	workerIsDone       chan struct{}
+}
+
+// Opens the Write-Ahead Log stored in the Directory, replaces the Cache's
+// Contents with the Contents restored from the Snapshot and the Log, and
+// starts recording the Changes of the Cache.
+//
+// A broken Tail of the Log, e.g. an Entry partially written during a Crash,
+// is discarded. An intact Entry which can not be applied to the Cache is
+// reported as an Error and the Log is left unchanged.
+func OpenWriteAheadLog(
+	cache *FixedSizeBubbleCache,
+	directory string,
+	settings WriteAheadLogSettings,
+) (wal *WriteAheadLog, err error) {
+	if cache == nil {
+		err = errors.New(ErrCacheIsNotSet)
+		return
+	}
+
+	if settings.SyncInterval <= 0 {
+		settings.SyncInterval = WriteAheadLogSyncIntervalDefault
+	}
+	if settings.CompactionInterval <= 0 {
+		settings.CompactionInterval = WriteAheadLogCompactionIntervalDefault
+	}
+	if settings.MaxSize <= 0 {
+		settings.MaxSize = WriteAheadLogMaxSizeDefault
+	}
+
+	err = os.MkdirAll(directory, 0755)
+	if err != nil {
+		return
+	}
+
+	wal = &WriteAheadLog{
+		cache:              cache,
+		codec:              cache.GetCodec(),
+		logPath:            filepath.Join(directory, WriteAheadLogFileName),
+		snapshotPath:       filepath.Join(directory, WriteAheadLogSnapshotFileName),
+		settings:           settings,
+		compactionRequests: make(chan struct{}, 1),
+		stop:               make(chan struct{}),
+		workerIsDone:       make(chan struct{}),
+	}
+
+	err = wal.restoreSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	err = wal.openLog()
+	if err != nil {
+		return nil, err
+	}
+
+	cache.setJournal(wal)
+	go wal.runWorker()
+	return
+}
+
+// Replaces the Cache's Contents with the Contents of the Snapshot.
+// If the Snapshot does not exist, the Cache is emptied.
+func (wal *WriteAheadLog) restoreSnapshot() (err error) {
+	var file *os.File
+	file, err = os.Open(wal.snapshotPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return
+		}
+
+		wal.cache.lock.Lock()
+		defer wal.cache.lock.Unlock()
+
+		return wal.cache.restoreRecords(nil)
+	}
+	defer func() {
+		var closeErr = file.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	var records []*FixedSizeBubbleCacheRecord
+	records, err = wal.cache.readSnapshot(file, wal.codec)
+	if err != nil {
+		return
+	}
+
+	wal.cache.lock.Lock()
+	defer wal.cache.lock.Unlock()
+
+	return wal.cache.restoreRecords(records)
+}
+
+// Opens the Log, replays its Entries and prepares it for Appending.
+func (wal *WriteAheadLog) openLog() (err error) {
+	wal.file, err = os.OpenFile(wal.logPath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return
+	}
+
+	var validSize int64
+	validSize, err = wal.replay(wal.file)
+	if err != nil {
+		_ = wal.file.Close()
+		return
+	}
+
+	// Discard the broken Tail and a missing Header.
+	err = wal.file.Truncate(validSize)
+	if err != nil {
+		_ = wal.file.Close()
+		return
+	}
+	_, err = wal.file.Seek(validSize, io.SeekStart)
+	if err != nil {
+		_ = wal.file.Close()
+		return
+	}
+	wal.writer = bufio.NewWriter(wal.file)
+	wal.size = validSize
+
+	if validSize == 0 {
+		err = wal.writeHeader()
+		if err != nil {
+			_ = wal.file.Close()
+			return
+		}
+	}
+	return
+}
+
+// Replays the Entries of the Log. Returns the Size of the valid Part of the
+// Log, which is Zero if the Log has no valid Header. Only a short Read, a
+// wrong Size or a broken Checksum marks the broken Tail, other Errors are
+// returned.
+func (wal *WriteAheadLog) replay(
+	r io.Reader,
+) (validSize int64, err error) {
+	var reader = bufio.NewReader(r)
+	var header = make([]byte, walHeaderSize)
+	_, err = io.ReadFull(reader, header)
+	if err != nil {
+		if isBrokenTailError(err) {
+			// An empty Log or a Log with a partially written Header.
+			return 0, nil
+		}
+		return 0, err
+	}
+	if string(header[0:4]) != WriteAheadLogMagic {
+		return 0, errors.New(ErrWriteAheadLogMagicIsWrong)
+	}
+	var version = binary.BigEndian.Uint16(header[4:6])
+	if (version == 0) || (version > WriteAheadLogFormatVersion) {
+		return 0, fmt.Errorf(ErrfWriteAheadLogVersionIsNotSupported, version)
+	}
+	validSize = walHeaderSize
+
+	wal.cache.lock.Lock()
+	defer wal.cache.lock.Unlock()
+
+	var payload []byte
+	for {
+		payload, err = readSnapshotBlock(reader, 1+snapshotRecordFixedSize)
+		if err != nil {
+			if isBrokenTailError(err) {
+				// The End of the Log or its broken Tail.
+				return validSize, nil
+			}
+			return 0, err
+		}
+
+		err = wal.applyEntry(payload)
+		if err != nil {
+			return 0, err
+		}
+		validSize += int64(snapshotPayloadSizeSize + len(payload) + snapshotChecksumSize)
+	}
+}
+
+// Checks whether the Error of reading the Log marks its End or its broken
+// Tail, rather than a Failure of the Reader.
+func isBrokenTailError(
+	err error,
+) bool {
+	return (err == io.EOF) ||
+		(err == io.ErrUnexpectedEOF) ||
+		(err.Error() == ErrChecksumMismatch) ||
+		(err.Error() == ErrSnapshotRecordSizeIsWrong)
+}
+
+// Applies an Entry of the Log to the Cache. The Cache must be locked.
+func (wal *WriteAheadLog) applyEntry(
+	payload []byte,
+) (err error) {
+	var record *FixedSizeBubbleCacheRecord
+	var data []byte
+	record, data, err = parseSnapshotRecordPayload(payload[1:])
+	if err != nil {
+		return
+	}
+
+	var c = wal.cache
+	switch payload[0] {
+	case walOperationAdd:
+		err = decodeSnapshotRecordData(record, data, wal.codec)
+		if err != nil {
+			return
+		}
+		c.addRecord(record)
+		c.top.lastAccessTime = record.lastAccessTime
+		c.top.updateTime = record.updateTime
+		c.restorePin(c.top, record.isPinned)
+
+	case walOperationAddAtBottom:
+		err = decodeSnapshotRecordData(record, data, wal.codec)
+		if err != nil {
+			return
+		}
+		c.addRecordAtBottom(record)
+		c.bottom.lastAccessTime = record.lastAccessTime
+		c.bottom.updateTime = record.updateTime
+		c.restorePin(c.bottom, record.isPinned)
+
+	case walOperationTouch:
+		var existingRecord *FixedSizeBubbleCacheRecord
+		existingRecord, err = c.getRecordByUID(record.UID)
+		if err != nil {
+			return nil
+		}
+		if existingRecord != c.top {
+			c.moveExistingRecordToTop(existingRecord)
+		}
+		c.top.lastAccessTime = record.lastAccessTime
+		c.top.ttl = record.ttl
+		c.top.expirationTime = record.expirationTime
+
+	case walOperationDelete:
+		var existingRecord *FixedSizeBubbleCacheRecord
+		existingRecord, err = c.getRecordByUID(record.UID)
+		if err != nil {
+			return nil
+		}
+		return c.deleteRecord(existingRecord, true)
+
+	case walOperationPin:
+		var existingRecord *FixedSizeBubbleCacheRecord
+		existingRecord, err = c.getRecordByUID(record.UID)
+		if err != nil {
+			return nil
+		}
+		c.restorePin(existingRecord, record.isPinned)
+
+	default:
+		return fmt.Errorf(ErrfWriteAheadLogOperationIsUnknown, payload[0])
+	}
+	return
+}
+
+// Writes the Header of the Log.
+func (wal *WriteAheadLog) writeHeader() (err error) {
+	var header = make([]byte, walHeaderSize)
+	copy(header[0:4], WriteAheadLogMagic)
+	binary.BigEndian.PutUint16(header[4:6], WriteAheadLogFormatVersion)
+
+	_, err = wal.writer.Write(header)
+	if err != nil {
+		return
+	}
+	wal.size = walHeaderSize
+	return wal.writer.Flush()
+}
+
+// Records the Addition or the Update of a Record.
+func (wal *WriteAheadLog) recordAdded(
+	record *FixedSizeBubbleCacheRecord,
+) {
+	wal.lock.Lock()
+	defer wal.lock.Unlock()
+
+	var err error
+	wal.payload, err = encodeSnapshotRecordPayload(
+		append(wal.payload[:0], walOperationAdd),
+		record,
+		wal.codec,
+	)
+	wal.writeEntry(err)
+}
+
+// Records the Addition, the Update or the Demotion of a Record at the Bottom.
+func (wal *WriteAheadLog) recordAddedAtBottom(
+	record *FixedSizeBubbleCacheRecord,
+) {
+	wal.lock.Lock()
+	defer wal.lock.Unlock()
+
+	var err error
+	wal.payload, err = encodeSnapshotRecordPayload(
+		append(wal.payload[:0], walOperationAddAtBottom),
+		record,
+		wal.codec,
+	)
+	wal.writeEntry(err)
+}
+
+// Records the Request of a Record.
+func (wal *WriteAheadLog) recordTouched(
+	record *FixedSizeBubbleCacheRecord,
+) {
+	wal.lock.Lock()
+	defer wal.lock.Unlock()
+
+	var err error
+	wal.payload, err = appendSnapshotRecordPayload(
+		append(wal.payload[:0], walOperationTouch),
+		record,
+		nil,
+	)
+	wal.writeEntry(err)
+}
+
+// Records the Deletion of a Record.
+func (wal *WriteAheadLog) recordDeleted(
+	record *FixedSizeBubbleCacheRecord,
+) {
+	wal.lock.Lock()
+	defer wal.lock.Unlock()
+
+	var err error
+	wal.payload, err = appendSnapshotRecordPayload(
+		append(wal.payload[:0], walOperationDelete),
+		record,
+		nil,
+	)
+	wal.writeEntry(err)
+}
+
+// Records the Pinning or the Unpinning of a Record.
+func (wal *WriteAheadLog) recordPinChanged(
+	record *FixedSizeBubbleCacheRecord,
+) {
+	wal.lock.Lock()
+	defer wal.lock.Unlock()
+
+	var err error
+	wal.payload, err = appendSnapshotRecordPayload(
+		append(wal.payload[:0], walOperationPin),
+		record,
+		nil,
+	)
+	wal.writeEntry(err)
+}
+
+// Writes the prepared Entry into the Log. The Log must be locked.
+// Journaling can not stop the Cache, so the first Error is kept and is
+// reported by the 'Err', 'Sync' and 'Close' Methods.
+func (wal *WriteAheadLog) writeEntry(
+	encodingErr error,
+) {
+	var err = encodingErr
+	if err == nil {
+		err = writeSnapshotBlock(wal.writer, wal.payload)
+	}
+	if err != nil {
+		if wal.journalingErr == nil {
+			wal.journalingErr = err
+		}
+		return
+	}
+
+	wal.size += int64(snapshotPayloadSizeSize + len(wal.payload) + snapshotChecksumSize)
+	if wal.size >= wal.settings.MaxSize {
+		select {
+		case wal.compactionRequests <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Returns the first Error of Journaling, if any.
+func (wal *WriteAheadLog) Err() error {
+	wal.lock.Lock()
+	defer wal.lock.Unlock()
+
+	return wal.journalingErr
+}
+
+// Writes all the recorded Changes to the Disk.
+func (wal *WriteAheadLog) Sync() (err error) {
+	wal.lock.Lock()
+	defer wal.lock.Unlock()
+
+	return wal.sync()
+}
+
+// Writes all the recorded Changes to the Disk. The Log must be locked.
+func (wal *WriteAheadLog) sync() (err error) {
+	if wal.journalingErr != nil {
+		return wal.journalingErr
+	}
+	err = wal.writer.Flush()
+	if err != nil {
+		return
+	}
+	return wal.file.Sync()
+}
+
+// Writes a Snapshot of the Cache and removes the Entries it covers from the
+// Log. The Image of the Cache is taken while the Cache is locked, the Disk is
+// written after the Cache is unlocked.
+func (wal *WriteAheadLog) Compact() (err error) {
+	wal.compactionLock.Lock()
+	defer wal.compactionLock.Unlock()
+
+	var image []byte
+	var coveredSize int64
+	var coveredErr error
+	image, coveredSize, coveredErr, err = wal.takeImage()
+	if err != nil {
+		return
+	}
+
+	// Write a new Snapshot into a temporary File and replace the old One.
+	var tmpPath = wal.snapshotPath + ".tmp"
+	var file *os.File
+	file, err = os.Create(tmpPath)
+	if err != nil {
+		return
+	}
+	_, err = file.Write(image)
+	if err == nil {
+		err = file.Sync()
+	}
+	var closeErr = file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return
+	}
+	err = os.Rename(tmpPath, wal.snapshotPath)
+	if err != nil {
+		return
+	}
+
+	return wal.dropCoveredEntries(coveredSize, coveredErr)
+}
+
+// Takes the Image of the Cache for a Snapshot. Returns the Size of the Log
+// covered by the Image and the Journaling Error covered by it.
+func (wal *WriteAheadLog) takeImage() (
+	image []byte,
+	coveredSize int64,
+	coveredErr error,
+	err error,
+) {
+	wal.cache.lock.Lock()
+	defer wal.cache.lock.Unlock()
+
+	wal.lock.Lock()
+	defer wal.lock.Unlock()
+
+	if wal.isClosed {
+		err = errors.New(ErrWriteAheadLogIsClosed)
+		return
+	}
+
+	var buffer bytes.Buffer
+	err = wal.cache.takeSnapshotImage().write(&buffer)
+	if err != nil {
+		return
+	}
+
+	// After a Journaling Error the Log misses some Changes, so it is
+	// restarted and the Image covers all the Changes.
+	if wal.journalingErr != nil {
+		err = wal.restartLog(nil)
+		if err != nil {
+			return
+		}
+	}
+
+	return buffer.Bytes(), wal.size, wal.journalingErr, nil
+}
+
+// Removes the Entries covered by the Snapshot from the Log. The Entries
+// recorded after the Image of the Cache has been taken are kept.
+func (wal *WriteAheadLog) dropCoveredEntries(
+	coveredSize int64,
+	coveredErr error,
+) (err error) {
+	wal.lock.Lock()
+	defer wal.lock.Unlock()
+
+	if wal.isClosed {
+		return errors.New(ErrWriteAheadLogIsClosed)
+	}
+
+	err = wal.writer.Flush()
+	if err != nil {
+		return
+	}
+	var newerEntries = make([]byte, wal.size-coveredSize)
+	_, err = wal.file.ReadAt(newerEntries, coveredSize)
+	if err != nil {
+		return
+	}
+
+	err = wal.restartLog(newerEntries)
+	if err != nil {
+		return
+	}
+	if wal.journalingErr == coveredErr {
+		wal.journalingErr = nil
+	}
+	return wal.file.Sync()
+}
+
+// Empties the Log and writes the Header and the Entries into it. The Log must
+// be locked.
+func (wal *WriteAheadLog) restartLog(
+	entries []byte,
+) (err error) {
+	wal.writer.Reset(wal.file)
+	err = wal.file.Truncate(0)
+	if err != nil {
+		return
+	}
+	_, err = wal.file.Seek(0, io.SeekStart)
+	if err != nil {
+		return
+	}
+	err = wal.writeHeader()
+	if err != nil {
+		return
+	}
+
+	_, err = wal.writer.Write(entries)
+	if err != nil {
+		return
+	}
+	wal.size += int64(len(entries))
+	return wal.writer.Flush()
+}
+
+// Synchronizes and compacts the Log periodically.
+func (wal *WriteAheadLog) runWorker() {
+	defer close(wal.workerIsDone)
+
+	var syncTicker = time.NewTicker(wal.settings.SyncInterval)
+	defer syncTicker.Stop()
+	var compactionTicker = time.NewTicker(wal.settings.CompactionInterval)
+	defer compactionTicker.Stop()
+
+	for {
+		select {
+		case <-wal.stop:
+			return
+		case <-syncTicker.C:
+			// Errors are kept and reported by the 'Err' Method.
+			_ = wal.Sync()
+		case <-compactionTicker.C:
+			_ = wal.Compact()
+		case <-wal.compactionRequests:
+			_ = wal.Compact()
+		}
+	}
+}
+
+// Stops recording the Changes of the Cache, writes all the recorded Changes
+// to the Disk and closes the Log. A running Compaction is finished first.
+// Closing a closed Log returns an Error.
+func (wal *WriteAheadLog) Close() (err error) {
+	wal.lock.Lock()
+	if wal.isClosed {
+		wal.lock.Unlock()
+		return errors.New(ErrWriteAheadLogIsClosed)
+	}
+	wal.isClosed = true
+	wal.lock.Unlock()
+
+	close(wal.stop)
+	<-wal.workerIsDone
+	wal.cache.setJournal(nil)
+
+	wal.compactionLock.Lock()
+	defer wal.compactionLock.Unlock()
+
+	wal.lock.Lock()
+	defer wal.lock.Unlock()
+
+	err = wal.sync()
+	var closeErr = wal.file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	return
+}
@@ -0,0 +1,344 @@
+// Fixed Size Bubble Cache.
+
+package fsbcache
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/tester"
+)
+
+// Settings which leave Synchronization and Compaction to the Test.
+var walTestSettings = WriteAheadLogSettings{
+	SyncInterval:       time.Hour,
+	CompactionInterval: time.Hour,
+}
+
+func Test_OpenWriteAheadLog(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var directory = t.TempDir()
+	var cache *FixedSizeBubbleCache
+	var wal *WriteAheadLog
+	var err error
+
+	// Test #1. No Cache.
+	_, err = OpenWriteAheadLog(nil, directory, walTestSettings)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrCacheIsNotSet)
+
+	// Test #2. A new Log.
+	cache = NewFixedSizeBubbleCache(3, 60)
+	cache.SetNegativeRecordTTL(60)
+	wal, err = OpenWriteAheadLog(cache, directory, walTestSettings)
+	aTest.MustBeNoError(err)
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1})
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2})
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "c", Data: 3})
+	_ = cache.AddNegativeRecord("d") // Evicts 'a'.
+	_, _ = cache.GetActualRecordDataByUID("b")
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "c", Data: 33})
+	_ = cache.DeleteRecordByUID("d")
+	err = wal.Close()
+	aTest.MustBeNoError(err)
+	var expectedValues = []interface{}{33, 2}
+
+	// Test #3. Replay of the Log.
+	cache = NewFixedSizeBubbleCache(3, 60)
+	cache.SetNegativeRecordTTL(60)
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "x", Data: 0})
+	wal, err = OpenWriteAheadLog(cache, directory, walTestSettings)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(cache.ListAllRecordValues(), expectedValues)
+	aTest.MustBeEqual(cache.isIntegral(), true)
+
+	// Test #4. Compaction.
+	err = wal.Compact()
+	aTest.MustBeNoError(err)
+	var info os.FileInfo
+	info, err = os.Stat(filepath.Join(directory, WriteAheadLogFileName))
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(info.Size(), int64(walHeaderSize))
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "e", Data: 5})
+	err = wal.Close()
+	aTest.MustBeNoError(err)
+
+	// Test #5. Replay of the Snapshot and of the Log.
+	cache = NewFixedSizeBubbleCache(3, 60)
+	wal, err = OpenWriteAheadLog(cache, directory, walTestSettings)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(cache.ListAllRecordValues(), []interface{}{5, 33, 2})
+	err = wal.Close()
+	aTest.MustBeNoError(err)
+}
+
+func Test_OpenWriteAheadLog_BrokenTail(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var directory = t.TempDir()
+	var logPath = filepath.Join(directory, WriteAheadLogFileName)
+	var cache *FixedSizeBubbleCache
+	var wal *WriteAheadLog
+	var err error
+
+	cache = NewFixedSizeBubbleCache(3, 60)
+	wal, err = OpenWriteAheadLog(cache, directory, walTestSettings)
+	aTest.MustBeNoError(err)
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1})
+	err = wal.Sync()
+	aTest.MustBeNoError(err)
+	var info os.FileInfo
+	info, err = os.Stat(logPath)
+	aTest.MustBeNoError(err)
+	var validSize = info.Size()
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2})
+	err = wal.Close()
+	aTest.MustBeNoError(err)
+
+	// Test #1. A partially written Entry is discarded.
+	err = os.Truncate(logPath, validSize+5)
+	aTest.MustBeNoError(err)
+	cache = NewFixedSizeBubbleCache(3, 60)
+	wal, err = OpenWriteAheadLog(cache, directory, walTestSettings)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(cache.ListAllRecordValues(), []interface{}{1})
+	info, err = os.Stat(logPath)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(info.Size(), validSize)
+	err = wal.Close()
+	aTest.MustBeNoError(err)
+
+	// Test #2. A foreign File.
+	var file *os.File
+	file, err = os.Create(logPath)
+	aTest.MustBeNoError(err)
+	_, _ = file.WriteString("something else")
+	_ = file.Close()
+	_, err = OpenWriteAheadLog(NewFixedSizeBubbleCache(3, 60), directory, walTestSettings)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrWriteAheadLogMagicIsWrong)
+}
+
+// A Reader which fails after the Bytes are read.
+type failingReader struct {
+	data []byte
+	err  error
+}
+
+func (r *failingReader) Read(p []byte) (n int, err error) {
+	if len(r.data) == 0 {
+		return 0, r.err
+	}
+	n = copy(p, r.data)
+	r.data = r.data[n:]
+	return
+}
+
+func Test_WriteAheadLog_ReadError(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var directory = t.TempDir()
+	var logPath = filepath.Join(directory, WriteAheadLogFileName)
+	var cache = NewFixedSizeBubbleCache(3, 60)
+	var wal, err = OpenWriteAheadLog(cache, directory, walTestSettings)
+	aTest.MustBeNoError(err)
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1})
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2})
+	err = wal.Close()
+	aTest.MustBeNoError(err)
+	var contents []byte
+	contents, err = ioutil.ReadFile(logPath)
+	aTest.MustBeNoError(err)
+	var readErr = errors.New("read error")
+
+	// Test #1. A Failure of the Reader in the Middle of the Log is returned.
+	wal = &WriteAheadLog{cache: NewFixedSizeBubbleCache(3, 60), codec: GobCodec{}}
+	_, err = wal.replay(&failingReader{data: contents[:len(contents)-5], err: readErr})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err, readErr)
+
+	// Test #2. A Failure of the Reader in the Header is returned.
+	wal = &WriteAheadLog{cache: NewFixedSizeBubbleCache(3, 60), codec: GobCodec{}}
+	_, err = wal.replay(&failingReader{data: contents[:2], err: readErr})
+	aTest.MustBeEqual(err, readErr)
+
+	// Test #3. The End of the Log.
+	wal = &WriteAheadLog{cache: NewFixedSizeBubbleCache(3, 60), codec: GobCodec{}}
+	var validSize int64
+	validSize, err = wal.replay(&failingReader{data: contents, err: io.EOF})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(validSize, int64(len(contents)))
+	aTest.MustBeEqual(wal.cache.ListAllRecordValues(), []interface{}{2, 1})
+}
+
+func Test_WriteAheadLog_CompactionBySize(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var directory = t.TempDir()
+	var settings = walTestSettings
+	settings.MaxSize = 200
+	var cache = NewFixedSizeBubbleCache(100, 60)
+	var wal, err = OpenWriteAheadLog(cache, directory, settings)
+	aTest.MustBeNoError(err)
+
+	// Test #1.
+	for i := 0; i < 20; i++ {
+		_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: string(rune('a' + i)), Data: i})
+	}
+	var snapshotPath = filepath.Join(directory, WriteAheadLogSnapshotFileName)
+	for i := 0; i < 100; i++ {
+		if _, err = os.Stat(snapshotPath); err == nil {
+			break
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	aTest.MustBeNoError(err)
+	err = wal.Close()
+	aTest.MustBeNoError(err)
+
+	cache = NewFixedSizeBubbleCache(100, 60)
+	wal, err = OpenWriteAheadLog(cache, directory, settings)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(cache.Stats().Records, uint(20))
+	aTest.MustBeEqual(cache.top.UID, "t")
+	err = wal.Close()
+	aTest.MustBeNoError(err)
+}
+
+func Test_OpenWriteAheadLog_BrokenEntry(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var directory = t.TempDir()
+	var logPath = filepath.Join(directory, WriteAheadLogFileName)
+
+	var cache = NewFixedSizeBubbleCache(3, 60)
+	var wal, err = OpenWriteAheadLog(cache, directory, walTestSettings)
+	aTest.MustBeNoError(err)
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1})
+	aTest.MustBeNoError(wal.Close())
+
+	// An intact Entry with an unknown Operation, followed by a valid Entry.
+	var file *os.File
+	file, err = os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND, 0644)
+	aTest.MustBeNoError(err)
+	var payload []byte
+	payload, err = appendSnapshotRecordPayload([]byte{99}, &FixedSizeBubbleCacheRecord{UID: "x"}, nil)
+	aTest.MustBeNoError(err)
+	aTest.MustBeNoError(writeSnapshotBlock(file, payload))
+	payload, err = appendSnapshotRecordPayload([]byte{walOperationDelete}, &FixedSizeBubbleCacheRecord{UID: "a"}, nil)
+	aTest.MustBeNoError(err)
+	aTest.MustBeNoError(writeSnapshotBlock(file, payload))
+	aTest.MustBeNoError(file.Close())
+	var info os.FileInfo
+	info, err = os.Stat(logPath)
+	aTest.MustBeNoError(err)
+	var logSize = info.Size()
+
+	// Test #1. The Error is reported and the Log is kept.
+	_, err = OpenWriteAheadLog(NewFixedSizeBubbleCache(3, 60), directory, walTestSettings)
+	aTest.MustBeAnError(err)
+	info, err = os.Stat(logPath)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(info.Size(), logSize)
+}
+
+func Test_WriteAheadLog_Compact(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var directory = t.TempDir()
+	var cache = NewFixedSizeBubbleCache(3, 60)
+	var wal, err = OpenWriteAheadLog(cache, directory, walTestSettings)
+	aTest.MustBeNoError(err)
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1})
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2})
+
+	// Test #1. Changes recorded after the Image are kept in the Log.
+	var image []byte
+	var coveredSize int64
+	var coveredErr error
+	image, coveredSize, coveredErr, err = wal.takeImage()
+	aTest.MustBeNoError(err)
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "c", Data: 3})
+	_ = cache.DeleteRecordByUID("a")
+	aTest.MustBeNoError(ioutil.WriteFile(wal.snapshotPath, image, 0644))
+	aTest.MustBeNoError(wal.dropCoveredEntries(coveredSize, coveredErr))
+	aTest.MustBeEqual(wal.size < coveredSize, true)
+	aTest.MustBeNoError(wal.Close())
+
+	cache = NewFixedSizeBubbleCache(3, 60)
+	wal, err = OpenWriteAheadLog(cache, directory, walTestSettings)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(cache.ListAllRecordValues(), []interface{}{3, 2})
+
+	// Test #2. A closed Log.
+	aTest.MustBeNoError(wal.Close())
+	aTest.MustBeAnError(wal.Close())
+	aTest.MustBeAnError(wal.Compact())
+}
+
+func Test_OpenWriteAheadLog_UnknownVersion(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var directory = t.TempDir()
+	var logPath = filepath.Join(directory, WriteAheadLogFileName)
+	var header = append([]byte(WriteAheadLogMagic), 0, byte(WriteAheadLogFormatVersion+1))
+	aTest.MustBeNoError(ioutil.WriteFile(logPath, header, 0644))
+
+	// Test #1. The Log of a newer Version is rejected and is kept.
+	var _, err = OpenWriteAheadLog(NewFixedSizeBubbleCache(3, 60), directory, walTestSettings)
+	aTest.MustBeAnError(err)
+	var contents []byte
+	contents, err = ioutil.ReadFile(logPath)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(contents, header)
+}
+
+func Test_WriteAheadLog_Pins(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var directory = t.TempDir()
+	var cache = NewFixedSizeBubbleCache(3, 60)
+	var wal, err = OpenWriteAheadLog(cache, directory, walTestSettings)
+	aTest.MustBeNoError(err)
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "a", Data: 1})
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "b", Data: 2})
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "c", Data: 3})
+	aTest.MustBeNoError(cache.Pin("a"))
+	aTest.MustBeNoError(cache.Pin("b"))
+	aTest.MustBeNoError(cache.Unpin("b"))
+	aTest.MustBeNoError(wal.Close())
+
+	// Test #1. Pins are replayed from the Log.
+	cache = NewFixedSizeBubbleCache(3, 60)
+	wal, err = OpenWriteAheadLog(cache, directory, walTestSettings)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(cache.isPinnedUID("a"), true)
+	aTest.MustBeEqual(cache.isPinnedUID("b"), false)
+	aTest.MustBeEqual(cache.pinnedCount, uint(1))
+
+	// Test #2. Pins are restored from the Snapshot.
+	aTest.MustBeNoError(wal.Compact())
+	aTest.MustBeNoError(wal.Close())
+	cache = NewFixedSizeBubbleCache(3, 60)
+	wal, err = OpenWriteAheadLog(cache, directory, walTestSettings)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(cache.isPinnedUID("a"), true)
+	aTest.MustBeEqual(cache.pinnedCount, uint(1))
+	_ = cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "d", Data: 4})
+	aTest.MustBeEqual(cache.RecordUIDExists("a"), true)
+	aTest.MustBeNoError(wal.Close())
+
+	// Test #3. Pins over the Limit are not restored.
+	cache = NewFixedSizeBubbleCache(3, 60)
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "x", Data: 0}))
+	aTest.MustBeNoError(cache.AddRecord(&FixedSizeBubbleCacheRecord{UID: "y", Data: 0}))
+	aTest.MustBeNoError(cache.Pin("x"))
+	aTest.MustBeNoError(cache.Pin("y"))
+	var buffer bytes.Buffer
+	aTest.MustBeNoError(cache.WriteSnapshot(&buffer))
+	cache = NewFixedSizeBubbleCache(3, 60)
+	cache.SetPinnedRecordsLimit(1)
+	aTest.MustBeNoError(cache.ReadSnapshot(&buffer))
+	aTest.MustBeEqual(cache.isPinnedUID("y"), true)
+	aTest.MustBeEqual(cache.isPinnedUID("x"), false)
+	aTest.MustBeEqual(cache.pinnedCount, uint(1))
+}
@@ -0,0 +1,337 @@
+// Fixed Size Bubble Cache Client.
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	fsbcache "github.com/vault-thirteen/FixedSizeBubbleCache"
+	"github.com/vault-thirteen/FixedSizeBubbleCache/httpserver"
+)
+
+// Default Settings of the Client.
+const (
+	TimeoutDefault            = time.Second * 5
+	MaxIdleConnectionsDefault = 16
+	MaxRetriesDefault         = 2
+	RetryDelayDefault         = time.Millisecond * 50
+)
+
+// The Methods shared by the in-process Cache and the Client, so that the
+// Users may switch between them by changing the Constructor.
+type Cache interface {
+	AddRecord(record *fsbcache.FixedSizeBubbleCacheRecord) (err error)
+	GetActualRecordDataByUID(uid fsbcache.FixedSizeBubbleCacheRecordUID) (data interface{}, err error)
+	DeleteRecordByUID(uid fsbcache.FixedSizeBubbleCacheRecordUID) (err error)
+	RecordUIDExists(uid fsbcache.FixedSizeBubbleCacheRecordUID) (uidExists bool)
+	Stats() (stats fsbcache.FixedSizeBubbleCacheStatistics)
+}
+
+var (
+	_ Cache = (*fsbcache.FixedSizeBubbleCache)(nil)
+	_ Cache = (*Client)(nil)
+)
+
+// Settings of the Client.
+// Zero Values select the default Settings.
+type Settings struct {
+
+	// Maximum Duration of a single Request, including the Reading of the
+	// Response.
+	Timeout time.Duration
+
+	// Maximum Count of idle Connections kept in the Pool.
+	MaxIdleConnections int
+
+	// Maximum Count of Retries of a Request which has failed due to a
+	// Network Error or an unavailable Server. A negative Value disables
+	// Retries.
+	MaxRetries int
+
+	// Delay before the first Retry. Each next Retry doubles the Delay.
+	RetryDelay time.Duration
+
+	// A Codec which converts the Data of Records into the Values stored by
+	// the Server. The default Codec is the raw Codec, which stores '[]byte'
+	// Data as is.
+	Codec fsbcache.Codec
+}
+
+// A Client of the Cache Server's HTTP API.
+//
+// Errors reported by the Server have the same Messages as the Errors of the
+// in-process Cache.
+type Client struct {
+	baseURL    string
+	settings   Settings
+	httpClient *http.Client
+}
+
+// Creates a new Client of the Server with the Base URL, e.g.
+// 'http://localhost:8080'.
+func New(
+	baseURL string,
+	settings Settings,
+) (c *Client, err error) {
+	var u *url.URL
+	u, err = url.Parse(baseURL)
+	if err != nil {
+		return
+	}
+	if (u.Scheme != "http") && (u.Scheme != "https") {
+		err = fmt.Errorf(ErrfURLIsNotSupported, baseURL)
+		return
+	}
+
+	if settings.Timeout <= 0 {
+		settings.Timeout = TimeoutDefault
+	}
+	if settings.MaxIdleConnections <= 0 {
+		settings.MaxIdleConnections = MaxIdleConnectionsDefault
+	}
+	if settings.MaxRetries == 0 {
+		settings.MaxRetries = MaxRetriesDefault
+	} else if settings.MaxRetries < 0 {
+		settings.MaxRetries = 0
+	}
+	if settings.RetryDelay <= 0 {
+		settings.RetryDelay = RetryDelayDefault
+	}
+	if settings.Codec == nil {
+		settings.Codec = fsbcache.RawCodec{}
+	}
+
+	var transport = http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = settings.MaxIdleConnections
+	transport.MaxIdleConnsPerHost = settings.MaxIdleConnections
+
+	c = &Client{
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		settings: settings,
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   settings.Timeout,
+		},
+	}
+	return
+}
+
+// Checks the Record's Parameters and adds it to the Cache.
+func (c *Client) AddRecord(
+	record *fsbcache.FixedSizeBubbleCacheRecord,
+) (err error) {
+
+	// Checks.
+	if record == nil {
+		return errors.New(fsbcache.ErrRecordIsNotSet)
+	}
+	err = record.Check()
+	if err != nil {
+		return
+	}
+
+	var value []byte
+	value, err = c.settings.Codec.Encode(record.Data)
+	if err != nil {
+		return
+	}
+
+	_, _, err = c.do(http.MethodPut, recordPath(record.UID), value, http.StatusNoContent)
+	return
+}
+
+// Gets the Record's Data by its UID. The Server moves the Record to the Top
+// and refreshes its LAT. If the Record is missing, outdated or negative,
+// returns an Error.
+func (c *Client) GetActualRecordDataByUID(
+	uid fsbcache.FixedSizeBubbleCacheRecordUID,
+) (data interface{}, err error) {
+	var body []byte
+	_, body, err = c.do(http.MethodGet, recordPath(uid), nil, http.StatusOK)
+	if err != nil {
+		return
+	}
+
+	return c.settings.Codec.Decode(body)
+}
+
+// Deletes a Record specified by its UID from the Cache.
+//
+// When a Request is retried after a lost Response, the Retry may report that
+// the already deleted Record is not found.
+func (c *Client) DeleteRecordByUID(
+	uid fsbcache.FixedSizeBubbleCacheRecordUID,
+) (err error) {
+	_, _, err = c.do(http.MethodDelete, recordPath(uid), nil, http.StatusNoContent)
+	return
+}
+
+// Checks whether the specified Record's UID exists in the Cache.
+// Failed Requests are reported as missing Records; use the
+// 'CheckRecordUIDExists' Method to get the Error.
+func (c *Client) RecordUIDExists(
+	uid fsbcache.FixedSizeBubbleCacheRecordUID,
+) (uidExists bool) {
+	uidExists, _ = c.CheckRecordUIDExists(uid)
+	return
+}
+
+// Checks whether the specified Record's UID exists in the Cache.
+func (c *Client) CheckRecordUIDExists(
+	uid fsbcache.FixedSizeBubbleCacheRecordUID,
+) (uidExists bool, err error) {
+	var status int
+	status, _, err = c.do(http.MethodHead, recordPath(uid), nil, http.StatusOK, http.StatusNotFound)
+	if err != nil {
+		return
+	}
+	return status == http.StatusOK, nil
+}
+
+// Returns the Statistics of the Cache's Usage.
+// Failed Requests are reported as empty Statistics; use the 'GetStats'
+// Method to get the Error.
+func (c *Client) Stats() (stats fsbcache.FixedSizeBubbleCacheStatistics) {
+	stats, _ = c.GetStats()
+	return
+}
+
+// Returns the Statistics of the Cache's Usage.
+func (c *Client) GetStats() (stats fsbcache.FixedSizeBubbleCacheStatistics, err error) {
+	var body []byte
+	_, body, err = c.do(http.MethodGet, httpserver.StatsPath, nil, http.StatusOK)
+	if err != nil {
+		return
+	}
+
+	var s httpserver.Stats
+	err = json.Unmarshal(body, &s)
+	if err != nil {
+		return
+	}
+
+	stats = fsbcache.FixedSizeBubbleCacheStatistics{
+		Hits:            s.Hits,
+		NegativeHits:    s.NegativeHits,
+		Misses:          s.Misses,
+		Expirations:     s.Expirations,
+		Evictions:       s.Evictions,
+		Records:         s.Records,
+		NegativeRecords: s.NegativeRecords,
+	}
+	return
+}
+
+// Closes the idle Connections of the Pool.
+func (c *Client) Close() {
+	c.httpClient.CloseIdleConnections()
+}
+
+// Performs a Request with Retries. Statuses other than the expected Ones are
+// converted into Errors.
+func (c *Client) do(
+	method string,
+	path string,
+	requestBody []byte,
+	expectedStatuses ...int,
+) (status int, responseBody []byte, err error) {
+	var delay = c.settings.RetryDelay
+	for attempt := 0; attempt <= c.settings.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+
+		status, responseBody, err = c.doOnce(method, path, requestBody)
+		if (err == nil) && !isStatusTemporary(status) {
+			break
+		}
+	}
+	if err != nil {
+		return
+	}
+
+	for _, expectedStatus := range expectedStatuses {
+		if status == expectedStatus {
+			return
+		}
+	}
+	err = errorOfResponse(status, responseBody)
+	return
+}
+
+// Performs a single Request.
+func (c *Client) doOnce(
+	method string,
+	path string,
+	requestBody []byte,
+) (status int, responseBody []byte, err error) {
+	var req *http.Request
+	req, err = http.NewRequest(method, c.baseURL+path, bytes.NewReader(requestBody))
+	if err != nil {
+		return
+	}
+	if requestBody != nil {
+		req.Header.Set("Content-Type", httpserver.ContentTypeBytes)
+	}
+
+	var resp *http.Response
+	resp, err = c.httpClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer func() {
+		var closeErr = resp.Body.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	responseBody, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+	return resp.StatusCode, responseBody, nil
+}
+
+// Returns the Path of a Record.
+func recordPath(
+	uid fsbcache.FixedSizeBubbleCacheRecordUID,
+) string {
+	return httpserver.RecordsPathPrefix + url.PathEscape(uid)
+}
+
+// Checks whether a Request with the Status may succeed when it is retried.
+func isStatusTemporary(
+	status int,
+) bool {
+	switch status {
+	case http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// Converts an unexpected Response into an Error. Errors of the Server keep
+// their original Messages.
+func errorOfResponse(
+	status int,
+	body []byte,
+) (err error) {
+	var apiError httpserver.Error
+	if (json.Unmarshal(body, &apiError) == nil) && (len(apiError.Message) > 0) {
+		return errors.New(apiError.Message)
+	}
+	return fmt.Errorf(ErrfUnexpectedStatus, status)
+}
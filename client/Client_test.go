@@ -0,0 +1,149 @@
+// Fixed Size Bubble Cache Client.
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/tester"
+
+	fsbcache "github.com/vault-thirteen/FixedSizeBubbleCache"
+	"github.com/vault-thirteen/FixedSizeBubbleCache/httpserver"
+)
+
+// A Handler which makes the Server unavailable for several Requests.
+type unavailableHandler struct {
+	handler                  http.Handler
+	lock                     sync.Mutex
+	unavailableRequestsCount int
+	requestsCount            int
+}
+
+// Serves an HTTP Request.
+func (h *unavailableHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.lock.Lock()
+	h.requestsCount++
+	var isUnavailable = h.unavailableRequestsCount > 0
+	if isUnavailable {
+		h.unavailableRequestsCount--
+	}
+	h.lock.Unlock()
+
+	if isUnavailable {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
+// Starts a Cache Server and returns it with its Handler.
+func startTestServer(
+	t *testing.T,
+	cache *fsbcache.FixedSizeBubbleCache,
+) (server *httptest.Server, h *unavailableHandler) {
+	var handler, err = httpserver.NewHandler(cache, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h = &unavailableHandler{handler: handler}
+	server = httptest.NewServer(h)
+	return
+}
+
+func Test_New(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var c *Client
+	var err error
+
+	// Test #1. Unsupported URL.
+	_, err = New("ftp://localhost", Settings{})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfURLIsNotSupported, "ftp://localhost"))
+
+	// Test #2. Default Settings.
+	c, err = New("http://localhost/", Settings{MaxRetries: -1})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(c.baseURL, "http://localhost")
+	aTest.MustBeEqual(c.settings.Timeout, TimeoutDefault)
+	aTest.MustBeEqual(c.settings.MaxRetries, 0)
+	aTest.MustBeEqual(c.settings.Codec, fsbcache.Codec(fsbcache.RawCodec{}))
+}
+
+func Test_Client(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = fsbcache.NewFixedSizeBubbleCache(2, 60)
+	var server, _ = startTestServer(t, cache)
+	defer server.Close()
+	var c, err = New(server.URL, Settings{Codec: fsbcache.GobCodec{}})
+	aTest.MustBeNoError(err)
+	defer c.Close()
+	var data interface{}
+
+	// Test #1. Missing Record has the Error of the Library.
+	_, err = c.GetActualRecordDataByUID("a/b")
+	aTest.MustBeAnError(err)
+	var _, libraryErr = cache.GetActualRecordDataByUID("a/b")
+	aTest.MustBeEqual(err.Error(), libraryErr.Error())
+	aTest.MustBeEqual(c.RecordUIDExists("a/b"), false)
+	err = c.DeleteRecordByUID("a/b")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(fsbcache.ErrfRecordWithUidIsNotFound, "a/b"))
+
+	// Test #2. Bad Record is rejected locally.
+	err = c.AddRecord(&fsbcache.FixedSizeBubbleCacheRecord{UID: "a"})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fsbcache.ErrDataIsEmpty)
+	err = c.AddRecord(nil)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fsbcache.ErrRecordIsNotSet)
+
+	// Test #3. Normal Usage.
+	err = c.AddRecord(&fsbcache.FixedSizeBubbleCacheRecord{UID: "a/b", Data: 12})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(c.RecordUIDExists("a/b"), true)
+	data, err = c.GetActualRecordDataByUID("a/b")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, 12)
+	aTest.MustBeEqual(c.Stats().Records, uint(1))
+	aTest.MustBeEqual(c.Stats().Hits, cache.Stats().Hits)
+	err = c.DeleteRecordByUID("a/b")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(cache.RecordUIDExists("a/b"), false)
+}
+
+func Test_Client_Retries(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = fsbcache.NewFixedSizeBubbleCache(2, 60)
+	var server, h = startTestServer(t, cache)
+	defer server.Close()
+	var c, err = New(server.URL, Settings{MaxRetries: 2, RetryDelay: time.Millisecond})
+	aTest.MustBeNoError(err)
+	defer c.Close()
+
+	// Test #1. The Server becomes available.
+	h.unavailableRequestsCount = 2
+	err = c.AddRecord(&fsbcache.FixedSizeBubbleCacheRecord{UID: "a", Data: []byte("x")})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(h.requestsCount, 3)
+
+	// Test #2. The Server stays unavailable.
+	h.unavailableRequestsCount = 3
+	_, err = c.GetActualRecordDataByUID("a")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfUnexpectedStatus, http.StatusServiceUnavailable))
+	var uidExists bool
+	uidExists, err = c.CheckRecordUIDExists("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(uidExists, true)
+
+	// Test #3. Network Error.
+	server.Close()
+	_, err = c.GetStats()
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(c.Stats(), fsbcache.FixedSizeBubbleCacheStatistics{})
+}
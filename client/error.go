@@ -0,0 +1,9 @@
+// Fixed Size Bubble Cache Client.
+
+package client
+
+// Error Messages.
+const (
+	ErrfURLIsNotSupported = `URL '%v' is not supported`
+	ErrfUnexpectedStatus  = `Unexpected Status of the Response: %v`
+)
@@ -0,0 +1,96 @@
+// Fixed Size Bubble Cache Cluster.
+
+package cluster
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+)
+
+// Default Count of virtual Nodes of each Peer on the Ring.
+const VirtualNodesDefault = 50
+
+// A Function which hashes Keys and virtual Nodes onto the Ring.
+type HashFunction func(data []byte) uint32
+
+// A consistent-Hash Ring which maps Keys onto Peers.
+//
+// Each Peer is placed onto the Ring several Times as virtual Nodes, so that
+// the Keys are distributed evenly and only a small Part of the Keys moves
+// when a Peer is added or removed.
+type HashRing struct {
+	hash         HashFunction
+	virtualNodes int
+
+	// Sorted Hashes of all virtual Nodes.
+	hashes []uint32
+
+	// Peers of virtual Nodes.
+	peersByHash map[uint32]string
+}
+
+// Creates an empty Ring. Zero Count of virtual Nodes selects the default
+// Count. Null Hash Function selects CRC-32.
+func NewHashRing(
+	virtualNodes int,
+	hash HashFunction,
+) (ring *HashRing) {
+	if virtualNodes <= 0 {
+		virtualNodes = VirtualNodesDefault
+	}
+	if hash == nil {
+		hash = crc32.ChecksumIEEE
+	}
+
+	ring = &HashRing{
+		hash:         hash,
+		virtualNodes: virtualNodes,
+		peersByHash:  make(map[uint32]string),
+	}
+	return
+}
+
+// Adds the Peers to the Ring.
+func (r *HashRing) Add(
+	peers ...string,
+) {
+	var h uint32
+	for _, peer := range peers {
+		for i := 0; i < r.virtualNodes; i++ {
+			h = r.hash([]byte(strconv.Itoa(i) + peer))
+			if _, exists := r.peersByHash[h]; !exists {
+				r.hashes = append(r.hashes, h)
+			}
+			r.peersByHash[h] = peer
+		}
+	}
+	sort.Slice(r.hashes, func(i, j int) bool {
+		return r.hashes[i] < r.hashes[j]
+	})
+}
+
+// Checks whether the Ring has no Peers.
+func (r *HashRing) IsEmpty() bool {
+	return len(r.hashes) == 0
+}
+
+// Returns the Peer which owns the Key. Returns an empty String when the Ring
+// is empty.
+func (r *HashRing) Get(
+	key string,
+) (peer string) {
+	if r.IsEmpty() {
+		return ""
+	}
+
+	var h = r.hash([]byte(key))
+	var i = sort.Search(len(r.hashes), func(i int) bool {
+		return r.hashes[i] >= h
+	})
+	if i == len(r.hashes) {
+		// The Ring is closed.
+		i = 0
+	}
+	return r.peersByHash[r.hashes[i]]
+}
@@ -0,0 +1,60 @@
+// Fixed Size Bubble Cache Cluster.
+
+package cluster
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/vault-thirteen/tester"
+)
+
+func Test_HashRing(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+
+	// A Hash Function which places virtual Nodes at predictable Positions:
+	// the Hash of "<i><peer>" and of a Key is their Number.
+	var hash = func(data []byte) uint32 {
+		var n, err = strconv.Atoi(string(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return uint32(n)
+	}
+	var ring = NewHashRing(3, hash)
+
+	// Test #1. Empty Ring.
+	aTest.MustBeEqual(ring.IsEmpty(), true)
+	aTest.MustBeEqual(ring.Get("1"), "")
+
+	// Test #2. Virtual Nodes 2, 12, 22 and 4, 14, 24.
+	ring.Add("2", "4")
+	aTest.MustBeEqual(ring.IsEmpty(), false)
+	aTest.MustBeEqual(ring.Get("1"), "2")
+	aTest.MustBeEqual(ring.Get("3"), "4")
+	aTest.MustBeEqual(ring.Get("12"), "2")
+	aTest.MustBeEqual(ring.Get("23"), "4")
+	aTest.MustBeEqual(ring.Get("25"), "2")
+
+	// Test #3. A new Peer takes over only a Part of the Keys.
+	ring.Add("8")
+	aTest.MustBeEqual(ring.Get("5"), "8")
+	aTest.MustBeEqual(ring.Get("1"), "2")
+	aTest.MustBeEqual(ring.Get("23"), "4")
+}
+
+func Test_HashRing_Distribution(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var ring = NewHashRing(0, nil)
+	ring.Add("http://a", "http://b", "http://c")
+
+	// Test #1. Each Peer owns a noticeable Part of the Keys.
+	var counts = make(map[string]int)
+	for i := 0; i < 3000; i++ {
+		counts[ring.Get("key-"+strconv.Itoa(i))]++
+	}
+	aTest.MustBeEqual(len(counts), 3)
+	for _, count := range counts {
+		aTest.MustBeEqual(count > 500, true)
+	}
+}
@@ -0,0 +1,349 @@
+// Fixed Size Bubble Cache Cluster.
+
+package cluster
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	fsbcache "github.com/vault-thirteen/FixedSizeBubbleCache"
+	"github.com/vault-thirteen/FixedSizeBubbleCache/httpserver"
+)
+
+// Path Prefix of the Peer API.
+const PeerPathPrefix = "/_fsbcache/peer/"
+
+// Default Settings of a Node.
+const (
+	HotCacheCapacityDefault = 128
+	HotCacheTTLDefault      = 10
+	PeerTimeoutDefault      = time.Second * 5
+)
+
+// Settings of a Node.
+// Zero Values select the default Settings.
+type Settings struct {
+
+	// Base URL of this Node, e.g. 'http://127.0.0.1:8001'. It must be listed
+	// among the Peers.
+	Self string
+
+	// Base URLs of all Nodes of the Cluster, including this Node.
+	Peers []string
+
+	// A Function which loads the Data of the Records owned by this Node.
+	Loader fsbcache.FixedSizeBubbleCacheLoader
+
+	// Count of virtual Nodes of each Peer on the Ring.
+	VirtualNodes int
+
+	// Hash Function of the Ring. The default Function is CRC-32.
+	Hash HashFunction
+
+	// Capacity and Record TTL of the Cache of Records owned by other Peers.
+	HotCacheCapacity uint
+	HotCacheTTL      uint
+
+	// Maximum Duration of a Request to a Peer.
+	PeerTimeout time.Duration
+
+	// A Codec which transfers the Data of Records between Peers. The
+	// default Codec is the Codec of the Node's Cache, which is also used by
+	// the hot Cache.
+	Codec fsbcache.Codec
+}
+
+// Statistics of a Node.
+type NodeStatistics struct {
+
+	// Count of Requests of Records owned by this Node.
+	LocalRequests uint64
+
+	// Count of Requests of Records owned by other Peers which have been
+	// served by the hot Cache.
+	HotHits uint64
+
+	// Count of Records fetched from other Peers.
+	PeerLoads uint64
+
+	// Count of failed Requests to other Peers. Such Records are loaded
+	// locally.
+	PeerErrors uint64
+
+	// Count of Requests served for other Peers.
+	PeerRequests uint64
+}
+
+// A Node of a Cluster of Caches.
+//
+// Each Node owns a Slice of the UID Space, which is defined by a
+// consistent-Hash Ring of all Peers. Records owned by the Node are stored in
+// its main Cache and are loaded by its Loader. Records owned by other Peers
+// are fetched from them over HTTP and are kept in a small hot Cache, so that
+// popular Records do not cause a Request to the Peer each Time.
+//
+// The Node is also an HTTP Handler of the Peer API, which must be served at
+// the Node's Base URL.
+type Node struct {
+	statistics NodeStatistics
+
+	self       string
+	cache      *fsbcache.FixedSizeBubbleCache
+	hotCache   *fsbcache.FixedSizeBubbleCache
+	loader     fsbcache.FixedSizeBubbleCacheLoader
+	codec      fsbcache.Codec
+	httpClient *http.Client
+	settings   Settings
+
+	ringLock sync.RWMutex
+	ring     *HashRing
+}
+
+// Creates a new Node. The Loader of the Cache is replaced with the Loader
+// of the Settings.
+func NewNode(
+	cache *fsbcache.FixedSizeBubbleCache,
+	settings Settings,
+) (n *Node, err error) {
+	if cache == nil {
+		err = errors.New(fsbcache.ErrCacheIsNotSet)
+		return
+	}
+	if settings.Loader == nil {
+		err = errors.New(fsbcache.ErrLoaderIsNotSet)
+		return
+	}
+	settings.Self = strings.TrimSuffix(settings.Self, "/")
+	if len(settings.Self) == 0 {
+		err = errors.New(ErrSelfIsNotSet)
+		return
+	}
+
+	if settings.HotCacheCapacity == 0 {
+		settings.HotCacheCapacity = HotCacheCapacityDefault
+	}
+	if settings.HotCacheTTL == 0 {
+		settings.HotCacheTTL = HotCacheTTLDefault
+	}
+	if settings.PeerTimeout <= 0 {
+		settings.PeerTimeout = PeerTimeoutDefault
+	}
+	if settings.Codec == nil {
+		settings.Codec = cache.GetCodec()
+	}
+
+	var hotCache *fsbcache.FixedSizeBubbleCache
+	hotCache, err = fsbcache.NewCheckedFixedSizeBubbleCache(settings.HotCacheCapacity, settings.HotCacheTTL)
+	if err != nil {
+		return
+	}
+	hotCache.SetCodec(settings.Codec)
+
+	n = &Node{
+		self:       settings.Self,
+		cache:      cache,
+		hotCache:   hotCache,
+		loader:     settings.Loader,
+		codec:      settings.Codec,
+		httpClient: &http.Client{Timeout: settings.PeerTimeout},
+		settings:   settings,
+	}
+	cache.SetLoader(settings.Loader)
+
+	err = n.SetPeers(settings.Peers...)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// Replaces the Peers of the Cluster. This Node must be listed among them.
+// Records of the hot Cache are dropped, as their Owners may have changed.
+func (n *Node) SetPeers(
+	peers ...string,
+) (err error) {
+	var ring = NewHashRing(n.settings.VirtualNodes, n.settings.Hash)
+	var selfIsListed bool
+	for _, peer := range peers {
+		peer = strings.TrimSuffix(peer, "/")
+		if peer == n.self {
+			selfIsListed = true
+		}
+		ring.Add(peer)
+	}
+	if !selfIsListed {
+		return fmt.Errorf(ErrfSelfIsNotListed, n.self)
+	}
+
+	n.ringLock.Lock()
+	n.ring = ring
+	n.ringLock.Unlock()
+
+	return n.hotCache.Clear()
+}
+
+// Returns the Base URL of the Peer which owns the Record.
+func (n *Node) Owner(
+	uid fsbcache.FixedSizeBubbleCacheRecordUID,
+) (peer string) {
+	n.ringLock.RLock()
+	defer n.ringLock.RUnlock()
+
+	return n.ring.Get(uid)
+}
+
+// Gets the Record's Data by its UID from the Cluster.
+//
+// Records owned by this Node are taken from its main Cache or are loaded.
+// Other Records are taken from the hot Cache or are fetched from their
+// Owners. If the Owner is not available, the Record is loaded locally
+// without being cached.
+func (n *Node) Get(
+	uid fsbcache.FixedSizeBubbleCacheRecordUID,
+) (data interface{}, err error) {
+	var owner = n.Owner(uid)
+	if owner == n.self {
+		atomic.AddUint64(&n.statistics.LocalRequests, 1)
+		return n.cache.GetOrLoadRecordDataByUID(uid)
+	}
+
+	data, err = n.hotCache.GetActualRecordDataByUID(uid)
+	if err == nil {
+		atomic.AddUint64(&n.statistics.HotHits, 1)
+		return
+	}
+
+	var isPeerAvailable bool
+	data, isPeerAvailable, err = n.fetch(owner, uid)
+	if !isPeerAvailable {
+		atomic.AddUint64(&n.statistics.PeerErrors, 1)
+		data, err = n.loader(uid)
+		if (err == nil) && (data == nil) {
+			err = fmt.Errorf(fsbcache.ErrfRecordWithUidIsNotFound, uid)
+		}
+		return
+	}
+	if err != nil {
+		return
+	}
+	atomic.AddUint64(&n.statistics.PeerLoads, 1)
+
+	err = n.hotCache.AddRecord(&fsbcache.FixedSizeBubbleCacheRecord{
+		UID:  uid,
+		Data: data,
+	})
+	if err != nil {
+		return
+	}
+	return
+}
+
+// Fetches the Record from its Owner. Errors of an available Peer are
+// returned with their original Messages.
+func (n *Node) fetch(
+	peer string,
+	uid fsbcache.FixedSizeBubbleCacheRecordUID,
+) (data interface{}, isPeerAvailable bool, err error) {
+	var resp *http.Response
+	resp, err = n.httpClient.Get(peer + PeerPathPrefix + url.PathEscape(uid))
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var body []byte
+	body, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		data, err = n.codec.Decode(body)
+		return data, err == nil, err
+	case http.StatusNotFound:
+		var apiError httpserver.Error
+		err = json.Unmarshal(body, &apiError)
+		if err != nil {
+			return
+		}
+		return nil, true, errors.New(apiError.Message)
+	default:
+		err = fmt.Errorf(ErrfPeerStatusIsUnexpected, resp.StatusCode)
+		return
+	}
+}
+
+// Serves the Peer API: GET {PeerPathPrefix}{uid} returns the Record's Data
+// encoded by the Codec. The Record is served from the main Cache or is loaded
+// regardless of its Owner, so Requests are never forwarded.
+func (n *Node) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, PeerPathPrefix) {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	atomic.AddUint64(&n.statistics.PeerRequests, 1)
+
+	var uid = strings.TrimPrefix(r.URL.Path, PeerPathPrefix)
+	var data, err = n.cache.GetOrLoadRecordDataByUID(uid)
+	if (err == nil) && (data == nil) {
+		err = fmt.Errorf(fsbcache.ErrfRecordWithUidIsNotFound, uid)
+	}
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	var value []byte
+	value, err = n.codec.Encode(data)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", httpserver.ContentTypeBytes)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(value)
+}
+
+// Returns the main Cache, which stores the Records owned by this Node.
+func (n *Node) Cache() *fsbcache.FixedSizeBubbleCache {
+	return n.cache
+}
+
+// Returns the hot Cache, which stores the Records owned by other Peers.
+func (n *Node) HotCache() *fsbcache.FixedSizeBubbleCache {
+	return n.hotCache
+}
+
+// Returns the Statistics of the Node.
+func (n *Node) Stats() (stats NodeStatistics) {
+	return NodeStatistics{
+		LocalRequests: atomic.LoadUint64(&n.statistics.LocalRequests),
+		HotHits:       atomic.LoadUint64(&n.statistics.HotHits),
+		PeerLoads:     atomic.LoadUint64(&n.statistics.PeerLoads),
+		PeerErrors:    atomic.LoadUint64(&n.statistics.PeerErrors),
+		PeerRequests:  atomic.LoadUint64(&n.statistics.PeerRequests),
+	}
+}
+
+// Writes an Error as JSON.
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", httpserver.ContentTypeJSON)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(httpserver.Error{Message: err.Error()})
+}
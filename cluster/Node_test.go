@@ -0,0 +1,220 @@
+// Fixed Size Bubble Cache Cluster.
+
+package cluster
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/vault-thirteen/tester"
+
+	fsbcache "github.com/vault-thirteen/FixedSizeBubbleCache"
+)
+
+// A Loader which counts its Calls. Keys starting with 'missing' do not exist.
+type testLoader struct {
+	lock       sync.Mutex
+	callsCount int
+}
+
+// Loads the Data of a Record.
+func (l *testLoader) load(
+	uid fsbcache.FixedSizeBubbleCacheRecordUID,
+) (data interface{}, err error) {
+	l.lock.Lock()
+	l.callsCount++
+	l.lock.Unlock()
+
+	if (len(uid) >= 7) && (uid[:7] == "missing") {
+		return nil, nil
+	}
+	return "value of " + uid, nil
+}
+
+// Returns the Count of Calls.
+func (l *testLoader) calls() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.callsCount
+}
+
+// A Cluster of Nodes running on the local Machine.
+type testCluster struct {
+	nodes   []*Node
+	loaders []*testLoader
+	servers []*http.Server
+}
+
+// Starts a Cluster of Nodes on loopback Listeners.
+func startTestCluster(
+	t *testing.T,
+	nodesCount int,
+) (tc *testCluster) {
+	var listeners = make([]net.Listener, nodesCount)
+	var peers = make([]string, nodesCount)
+	var err error
+	for i := range listeners {
+		listeners[i], err = net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		peers[i] = "http://" + listeners[i].Addr().String()
+	}
+
+	tc = new(testCluster)
+	var node *Node
+	for i := range listeners {
+		var loader = new(testLoader)
+		node, err = NewNode(fsbcache.NewFixedSizeBubbleCache(100, 60), Settings{
+			Self:   peers[i],
+			Peers:  peers,
+			Loader: loader.load,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var server = &http.Server{Handler: node}
+		go func(listener net.Listener) {
+			_ = server.Serve(listener)
+		}(listeners[i])
+
+		tc.nodes = append(tc.nodes, node)
+		tc.loaders = append(tc.loaders, loader)
+		tc.servers = append(tc.servers, server)
+	}
+	return
+}
+
+// Stops all Nodes.
+func (tc *testCluster) stop() {
+	for _, server := range tc.servers {
+		_ = server.Close()
+	}
+}
+
+// Returns the Index of the Node which owns the Record.
+func (tc *testCluster) ownerIndex(
+	uid fsbcache.FixedSizeBubbleCacheRecordUID,
+) int {
+	var owner = tc.nodes[0].Owner(uid)
+	for i, node := range tc.nodes {
+		if node.self == owner {
+			return i
+		}
+	}
+	return -1
+}
+
+func Test_NewNode(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = fsbcache.NewFixedSizeBubbleCache(10, 60)
+	var loader = new(testLoader)
+	var err error
+
+	// Test #1. No Cache.
+	_, err = NewNode(nil, Settings{})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fsbcache.ErrCacheIsNotSet)
+
+	// Test #2. No Loader.
+	_, err = NewNode(cache, Settings{Self: "http://a"})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fsbcache.ErrLoaderIsNotSet)
+
+	// Test #3. No Self.
+	_, err = NewNode(cache, Settings{Loader: loader.load})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrSelfIsNotSet)
+
+	// Test #4. Self is not a Peer.
+	_, err = NewNode(cache, Settings{Self: "http://a/", Peers: []string{"http://b"}, Loader: loader.load})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfSelfIsNotListed, "http://a"))
+
+	// Test #5. Default Settings.
+	var n *Node
+	n, err = NewNode(cache, Settings{Self: "http://a/", Peers: []string{"http://a", "http://b"}, Loader: loader.load})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(n.HotCache().GetCapacity(), uint(HotCacheCapacityDefault))
+	aTest.MustBeEqual(n.Owner("x") != "", true)
+
+	// Test #6. The Codec of the Cache is used by Default.
+	cache.SetCodec(fsbcache.JSONCodec{})
+	n, err = NewNode(cache, Settings{Self: "http://a", Peers: []string{"http://a"}, Loader: loader.load})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(n.codec, fsbcache.Codec(fsbcache.JSONCodec{}))
+	aTest.MustBeEqual(n.HotCache().GetCodec(), fsbcache.Codec(fsbcache.JSONCodec{}))
+}
+
+func Test_Node_Get(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var tc = startTestCluster(t, 3)
+	defer tc.stop()
+	var data interface{}
+	var err error
+
+	// Test #1. Each Record is loaded once in the whole Cluster, by its Owner.
+	var uids = make([]string, 30)
+	for i := range uids {
+		uids[i] = "key-" + strconv.Itoa(i)
+		for _, node := range tc.nodes {
+			data, err = node.Get(uids[i])
+			aTest.MustBeNoError(err)
+			aTest.MustBeEqual(data, "value of "+uids[i])
+		}
+	}
+	var loadsCount int
+	for i, loader := range tc.loaders {
+		loadsCount += loader.calls()
+		aTest.MustBeEqual(loader.calls() > 0, true)
+		aTest.MustBeEqual(tc.nodes[i].Stats().PeerErrors, uint64(0))
+	}
+	aTest.MustBeEqual(loadsCount, len(uids))
+	for _, uid := range uids {
+		var owner = tc.ownerIndex(uid)
+		aTest.MustBeEqual(tc.nodes[owner].Cache().RecordUIDExists(uid), true)
+		aTest.MustBeEqual(tc.nodes[owner].HotCache().RecordUIDExists(uid), false)
+		var other = (owner + 1) % len(tc.nodes)
+		aTest.MustBeEqual(tc.nodes[other].Cache().RecordUIDExists(uid), false)
+		aTest.MustBeEqual(tc.nodes[other].HotCache().RecordUIDExists(uid), true)
+	}
+
+	// Test #2. Hot Records are served without Requests to the Owner.
+	var uid = uids[0]
+	var other = (tc.ownerIndex(uid) + 1) % len(tc.nodes)
+	var hotHits = tc.nodes[other].Stats().HotHits
+	_, err = tc.nodes[other].Get(uid)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(tc.nodes[other].Stats().HotHits, hotHits+1)
+
+	// Test #3. Missing Record has the Owner's Error.
+	var missingUID string
+	for i := 0; ; i++ {
+		missingUID = "missing-" + strconv.Itoa(i)
+		if tc.ownerIndex(missingUID) != other {
+			break
+		}
+	}
+	_, err = tc.nodes[other].Get(missingUID)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fsbcache.ErrDataIsEmpty)
+	aTest.MustBeEqual(tc.nodes[other].HotCache().RecordUIDExists(missingUID), false)
+
+	// Test #4. Unavailable Owner.
+	var owner = tc.ownerIndex(uids[1])
+	other = (owner + 1) % len(tc.nodes)
+	_ = tc.servers[owner].Close()
+	_ = tc.nodes[other].HotCache().Clear()
+	var loaderCalls = tc.loaders[other].calls()
+	data, err = tc.nodes[other].Get(uids[1])
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "value of "+uids[1])
+	aTest.MustBeEqual(tc.loaders[other].calls(), loaderCalls+1)
+	aTest.MustBeEqual(tc.nodes[other].Stats().PeerErrors, uint64(1))
+	aTest.MustBeEqual(tc.nodes[other].HotCache().RecordUIDExists(uids[1]), false)
+}
@@ -0,0 +1,10 @@
+// Fixed Size Bubble Cache Cluster.
+
+package cluster
+
+// Error Messages.
+const (
+	ErrSelfIsNotSet            = `URL of the Node is not set`
+	ErrfSelfIsNotListed        = `URL of the Node '%v' is not listed among the Peers`
+	ErrfPeerStatusIsUnexpected = `Unexpected Status of the Peer's Response: %v`
+)
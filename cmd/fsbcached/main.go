@@ -0,0 +1,217 @@
+// Fixed Size Bubble Cache Daemon.
+//
+// The Daemon hosts a fixed-Size Bubble Cache and exposes it via an HTTP API
+// and, optionally, via the memcached Text Protocol and the Redis Protocol
+// (RESP). See the 'httpserver', 'memcached' and 'resp' Packages for the
+// Descriptions of the APIs.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	fsbcache "github.com/vault-thirteen/FixedSizeBubbleCache"
+	"github.com/vault-thirteen/FixedSizeBubbleCache/httpserver"
+	"github.com/vault-thirteen/FixedSizeBubbleCache/memcached"
+	"github.com/vault-thirteen/FixedSizeBubbleCache/resp"
+)
+
+// Settings of the Daemon.
+type settings struct {
+	httpAddress       string
+	memcachedAddress  string
+	respAddress       string
+	capacity          uint
+	recordTTL         uint
+	negativeRecordTTL uint
+	maxValueSize      int64
+	snapshotPath      string
+	shutdownTimeout   time.Duration
+}
+
+func main() {
+	var s = readSettings()
+
+	var err = run(s)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Reads the Settings from the Command Line.
+func readSettings() (s settings) {
+	flag.StringVar(&s.httpAddress, "http", ":8080", "Address of the HTTP Listener")
+	flag.StringVar(&s.memcachedAddress, "memcached", "", "Address of the memcached Listener; empty Address disables it")
+	flag.StringVar(&s.respAddress, "resp", "", "Address of the RESP Listener; empty Address disables it")
+	flag.UintVar(&s.capacity, "capacity", 1000, "Capacity of the Cache")
+	flag.UintVar(&s.recordTTL, "ttl", 60, "TTL of Records in Seconds")
+	flag.UintVar(&s.negativeRecordTTL, "negative-ttl", 0, "TTL of negative Records in Seconds")
+	flag.Int64Var(&s.maxValueSize, "max-value-size", httpserver.MaxValueSizeDefault, "Maximum Size of a Value in Bytes")
+	flag.StringVar(&s.snapshotPath, "snapshot", "", "Path to the Snapshot File which is read on Start and is written on Shutdown")
+	flag.DurationVar(&s.shutdownTimeout, "shutdown-timeout", time.Second*10, "Time given to active Requests on Shutdown")
+	flag.Parse()
+	return
+}
+
+// Runs the Daemon until it receives a Termination Signal.
+func run(s settings) (err error) {
+	var cache *fsbcache.FixedSizeBubbleCache
+	cache, err = fsbcache.NewCheckedFixedSizeBubbleCache(s.capacity, s.recordTTL)
+	if err != nil {
+		return
+	}
+	cache.SetNegativeRecordTTL(s.negativeRecordTTL)
+	// Values of the HTTP API are Byte Arrays while the memcached Server stores
+	// Items, so the Codec must support both.
+	cache.SetCodec(fsbcache.GobCodec{})
+
+	if len(s.snapshotPath) > 0 {
+		err = readSnapshot(cache, s.snapshotPath)
+		if err != nil {
+			return
+		}
+	}
+
+	var handler *httpserver.Handler
+	handler, err = httpserver.NewHandler(cache, s.maxValueSize)
+	if err != nil {
+		return
+	}
+	var server = &http.Server{
+		Addr:    s.httpAddress,
+		Handler: handler,
+	}
+
+	var serverErrors = make(chan error, 3)
+	go func() {
+		log.Printf("HTTP Server is listening on %v", s.httpAddress)
+		serverErrors <- server.ListenAndServe()
+	}()
+
+	var mcServer *memcached.Server
+	if len(s.memcachedAddress) > 0 {
+		mcServer, err = memcached.NewServer(cache, int(s.maxValueSize))
+		if err != nil {
+			return
+		}
+		var listener net.Listener
+		listener, err = net.Listen("tcp", s.memcachedAddress)
+		if err != nil {
+			return
+		}
+		go func() {
+			log.Printf("memcached Server is listening on %v", s.memcachedAddress)
+			serverErrors <- mcServer.Serve(listener)
+		}()
+	}
+
+	var respServer *resp.Server
+	if len(s.respAddress) > 0 {
+		respServer, err = resp.NewServer(cache, int(s.maxValueSize))
+		if err != nil {
+			return
+		}
+		var listener net.Listener
+		listener, err = net.Listen("tcp", s.respAddress)
+		if err != nil {
+			return
+		}
+		go func() {
+			log.Printf("RESP Server is listening on %v", s.respAddress)
+			serverErrors <- respServer.Serve(listener)
+		}()
+	}
+
+	var signals = make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err = <-serverErrors:
+		return
+	case sig := <-signals:
+		log.Printf("Signal received: %v", sig)
+	}
+
+	// Graceful Shutdown.
+	var ctx, cancel = context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+	err = server.Shutdown(ctx)
+	if err != nil {
+		return
+	}
+	if mcServer != nil {
+		err = mcServer.Close()
+		if err != nil {
+			return
+		}
+	}
+	if respServer != nil {
+		err = respServer.Close()
+		if err != nil {
+			return
+		}
+	}
+
+	if len(s.snapshotPath) > 0 {
+		err = writeSnapshot(cache, s.snapshotPath)
+		if err != nil {
+			return
+		}
+		log.Printf("Snapshot is written to %v", s.snapshotPath)
+	}
+	return
+}
+
+// Restores the Cache from the Snapshot File, if it exists.
+func readSnapshot(cache *fsbcache.FixedSizeBubbleCache, path string) (err error) {
+	var file *os.File
+	file, err = os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return
+	}
+	defer func() {
+		var closeErr = file.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	return cache.ReadSnapshot(file)
+}
+
+// Writes the Snapshot File. The old File is replaced only when the new One
+// is written completely.
+func writeSnapshot(cache *fsbcache.FixedSizeBubbleCache, path string) (err error) {
+	var tmpPath = path + ".tmp"
+	var file *os.File
+	file, err = os.Create(tmpPath)
+	if err != nil {
+		return
+	}
+
+	err = cache.WriteSnapshot(file)
+	if err == nil {
+		err = file.Sync()
+	}
+	var closeErr = file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return
+	}
+
+	return os.Rename(tmpPath, path)
+}
@@ -8,10 +8,45 @@ const (
 	ErrRecordIsNotSet = `Record is not set`
 	ErrUIDIsEmpty     = `'UID' Field is not set`
 	ErrCacheZeroSize  = "Cache Size is Zero"
+	ErrLoaderIsNotSet = `Loader is not set`
+	//
+	ErrNegativeCachingIsDisabled = `Negative Caching is disabled`
+	ErrCacheIsNotSet             = `Cache is not set`
+	ErrStoreIsNotSet             = `Store is not set`
+	ErrCacheIsClosed             = `Cache is closed`
+	ErrfStoreFlushFailure        = `%v Change(s) could not be written to the Store: %v`
+	ErrDiskTierDirectoryIsNotSet = `Directory of the Disk Tier is not set`
+	ErrfDiskTierRecordIsTooLarge = `Record with UID='%v' is too large for the Disk Tier`
+	ErrfDiskTierFileIsBroken     = `Disk Tier File of the Record with UID='%v' is broken`
+	ErrArenaIsTooLarge           = `Arena is too large`
+	ErrfArenaRecordIsTooLarge    = `Record with UID='%v' is too large for the Arena`
+	ErrRecordIsStoredInCache     = `Record is stored in a Cache`
+	ErrTooManyPinnedRecords      = `Too many Records are pinned`
+	ErrfCapacityIsTooLarge       = `Capacity %v exceeds the maximum Capacity %v`
 	//
 	ErrfRecordWithUidIsNotFound = `Record with UID='%v' is not found`
 	ErrfRecordWithUidIsOutdated = `Record with UID='%v' is outdated`
+	ErrfRecordWithUidIsNegative = `Record with UID='%v' is negative`
+	ErrfRecordWithUidDoesNotFit = `Record with UID='%v' does not fit into the Cache`
+	ErrfRecordVersionMismatch   = `Record with UID='%v' has Version %v instead of %v`
 	ErrIntegrityCheckFailure    = `Integrity Check Failure`
 	//
+	ErrfSnapshotHeaderIsBroken        = `Snapshot Header is broken: %v`
+	ErrSnapshotMagicIsWrong           = `Snapshot Magic is wrong`
+	ErrfSnapshotVersionIsNotSupported = `Snapshot Version %v is not supported`
+	ErrfSnapshotRecordIsBroken        = `Snapshot Record #%v is broken: %v`
+	ErrfSnapshotRecordIsTooLarge      = `Snapshot Record with UID='%v' is too large`
+	ErrSnapshotRecordSizeIsWrong      = `Snapshot Record Size is wrong`
+	ErrSnapshotHasDuplicateUIDs       = `Snapshot has duplicate UIDs`
+	ErrChecksumMismatch               = `Checksum Mismatch`
+	//
+	ErrfCodecIsUnknown              = `Codec '%v' is unknown`
+	ErrfCodecDataTypeIsNotSupported = `Data Type %T is not supported by the Codec`
+	//
+	ErrWriteAheadLogMagicIsWrong           = `Write-Ahead Log Magic is wrong`
+	ErrfWriteAheadLogVersionIsNotSupported = `Write-Ahead Log Version %v is not supported`
+	ErrfWriteAheadLogOperationIsUnknown    = `Write-Ahead Log Operation %v is unknown`
+	ErrWriteAheadLogIsClosed               = `Write-Ahead Log is closed`
+	//
 	ErrTypeCast = "Type Cast Failure"
 )
@@ -0,0 +1,275 @@
+// Fixed Size Bubble Cache HTTP Server.
+
+package httpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	fsbcache "github.com/vault-thirteen/FixedSizeBubbleCache"
+)
+
+// Paths of the API.
+const (
+	RecordsPath       = "/records"
+	RecordsPathPrefix = RecordsPath + "/"
+	StatsPath         = "/stats"
+)
+
+// Default maximum Size of a Record's Value in Bytes.
+const MaxValueSizeDefault = 1024 * 1024
+
+// Content Types.
+const (
+	ContentTypeJSON  = "application/json"
+	ContentTypeBytes = "application/octet-stream"
+)
+
+// An HTTP Handler which exposes a fixed-Size Bubble Cache.
+//
+// The API is:
+//
+//	GET    /records/{uid} – returns the Record's Value;
+//	HEAD   /records/{uid} – checks whether the Record exists;
+//	PUT    /records/{uid} – adds the Record with the Value from the Body;
+//	DELETE /records/{uid} – deletes the Record;
+//	GET    /records       – lists the UIDs of all Records from Top to Bottom;
+//	GET    /stats         – returns the Cache's Parameters and Statistics.
+//
+// Values of Records are stored as raw Bytes. Data of other Types is served
+// by its 'Bytes() []byte' Method, e.g. the Items stored by the memcached
+// Server, or is encoded by the Cache's Codec. Lists, Statistics and Errors are
+// returned as JSON Objects.
+type Handler struct {
+	cache        *fsbcache.FixedSizeBubbleCache
+	maxValueSize int64
+	mux          *http.ServeMux
+}
+
+// A List of UIDs.
+type UIDList struct {
+	UIDs []fsbcache.FixedSizeBubbleCacheRecordUID `json:"uids"`
+}
+
+// Parameters and Statistics of the Cache.
+type Stats struct {
+	Capacity          uint   `json:"capacity"`
+	RecordTTL         uint   `json:"recordTTL"`
+	NegativeRecordTTL uint   `json:"negativeRecordTTL"`
+	Records           uint   `json:"records"`
+	NegativeRecords   uint   `json:"negativeRecords"`
+	Hits              uint64 `json:"hits"`
+	NegativeHits      uint64 `json:"negativeHits"`
+	Misses            uint64 `json:"misses"`
+	Expirations       uint64 `json:"expirations"`
+	Evictions         uint64 `json:"evictions"`
+}
+
+// An Error returned by the API.
+type Error struct {
+	Message string `json:"error"`
+}
+
+// Creates a new Handler. Zero maximum Value Size selects the default Size.
+func NewHandler(
+	cache *fsbcache.FixedSizeBubbleCache,
+	maxValueSize int64,
+) (h *Handler, err error) {
+	if cache == nil {
+		err = errors.New(fsbcache.ErrCacheIsNotSet)
+		return
+	}
+	if maxValueSize <= 0 {
+		maxValueSize = MaxValueSizeDefault
+	}
+
+	h = &Handler{
+		cache:        cache,
+		maxValueSize: maxValueSize,
+		mux:          http.NewServeMux(),
+	}
+	h.mux.HandleFunc(RecordsPath, h.handleRecords)
+	h.mux.HandleFunc(RecordsPathPrefix, h.handleRecord)
+	h.mux.HandleFunc(StatsPath, h.handleStats)
+	return
+}
+
+// Serves an HTTP Request.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// Serves Requests of a single Record.
+func (h *Handler) handleRecord(w http.ResponseWriter, r *http.Request) {
+	var uid = strings.TrimPrefix(r.URL.Path, RecordsPathPrefix)
+	if len(uid) == 0 {
+		if r.Method == http.MethodGet {
+			h.handleRecords(w, r)
+			return
+		}
+		writeError(w, http.StatusBadRequest, errors.New(fsbcache.ErrUIDIsEmpty))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getRecord(w, uid)
+	case http.MethodHead:
+		h.headRecord(w, uid)
+	case http.MethodPut:
+		h.putRecord(w, r, uid)
+	case http.MethodDelete:
+		h.deleteRecord(w, uid)
+	default:
+		writeMethodNotAllowed(w, "GET, HEAD, PUT, DELETE")
+	}
+}
+
+// Returns the Record's Value.
+func (h *Handler) getRecord(
+	w http.ResponseWriter,
+	uid fsbcache.FixedSizeBubbleCacheRecordUID,
+) {
+	var data, err = h.cache.GetActualRecordDataByUID(uid)
+	if err != nil {
+		// The Record is missing, outdated or negative.
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	var value, ok = valueBytes(data, h.cache.GetCodec())
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New(ErrValueCanNotBeEncoded))
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentTypeBytes)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(value)
+}
+
+// Checks whether the Record exists.
+func (h *Handler) headRecord(
+	w http.ResponseWriter,
+	uid fsbcache.FixedSizeBubbleCacheRecordUID,
+) {
+	if !h.cache.RecordUIDExists(uid) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// Adds the Record with the Value from the Request's Body.
+func (h *Handler) putRecord(
+	w http.ResponseWriter,
+	r *http.Request,
+	uid fsbcache.FixedSizeBubbleCacheRecordUID,
+) {
+	var value, err = ioutil.ReadAll(http.MaxBytesReader(w, r.Body, h.maxValueSize))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+
+	err = h.cache.AddRecord(&fsbcache.FixedSizeBubbleCacheRecord{
+		UID:  uid,
+		Data: value,
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Deletes the Record.
+func (h *Handler) deleteRecord(
+	w http.ResponseWriter,
+	uid fsbcache.FixedSizeBubbleCacheRecordUID,
+) {
+	var err = h.cache.DeleteRecordByUID(uid)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Lists the UIDs of all Records.
+func (h *Handler) handleRecords(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, "GET")
+		return
+	}
+
+	var records = h.cache.ListAllRecords()
+	var list = UIDList{
+		UIDs: make([]fsbcache.FixedSizeBubbleCacheRecordUID, 0, len(records)),
+	}
+	for _, record := range records {
+		list.UIDs = append(list.UIDs, record.UID)
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// Returns the Cache's Parameters and Statistics.
+func (h *Handler) handleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, "GET")
+		return
+	}
+
+	var cs = h.cache.Stats()
+	writeJSON(w, http.StatusOK, Stats{
+		Capacity:          h.cache.GetCapacity(),
+		RecordTTL:         h.cache.GetRecordTTL(),
+		NegativeRecordTTL: h.cache.GetNegativeRecordTTL(),
+		Records:           cs.Records,
+		NegativeRecords:   cs.NegativeRecords,
+		Hits:              cs.Hits,
+		NegativeHits:      cs.NegativeHits,
+		Misses:            cs.Misses,
+		Expirations:       cs.Expirations,
+		Evictions:         cs.Evictions,
+	})
+}
+
+// Returns the Bytes of a Record's Data. Data other than Bytes is encoded by
+// the Cache's Codec.
+func valueBytes(
+	data interface{},
+	codec fsbcache.Codec,
+) (value []byte, ok bool) {
+	switch v := data.(type) {
+	case []byte:
+		return v, true
+	case interface{ Bytes() []byte }:
+		return v.Bytes(), true
+	default:
+		var err error
+		value, err = codec.Encode(data)
+		return value, err == nil
+	}
+}
+
+// Writes an Object as JSON.
+func writeJSON(w http.ResponseWriter, status int, object interface{}) {
+	w.Header().Set("Content-Type", ContentTypeJSON)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(object)
+}
+
+// Writes an Error as JSON.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, Error{Message: err.Error()})
+}
+
+// Writes the 'Method Not Allowed' Error.
+func writeMethodNotAllowed(w http.ResponseWriter, allowedMethods string) {
+	w.Header().Set("Allow", allowedMethods)
+	writeError(w, http.StatusMethodNotAllowed, errors.New(ErrMethodIsNotAllowed))
+}
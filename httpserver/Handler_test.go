@@ -0,0 +1,129 @@
+// Fixed Size Bubble Cache HTTP Server.
+
+package httpserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vault-thirteen/tester"
+
+	fsbcache "github.com/vault-thirteen/FixedSizeBubbleCache"
+)
+
+// Performs a Request and returns the Status and the Body of the Response.
+func doRequest(
+	t *testing.T,
+	h http.Handler,
+	method string,
+	path string,
+	body string,
+) (status int, responseBody string) {
+	var r = httptest.NewRequest(method, path, strings.NewReader(body))
+	var w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	var data, err = ioutil.ReadAll(w.Result().Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w.Code, string(data)
+}
+
+func Test_NewHandler(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var h *Handler
+	var err error
+
+	// Test #1. No Cache.
+	_, err = NewHandler(nil, 0)
+	aTest.MustBeAnError(err)
+
+	// Test #2. Default Value Size.
+	h, err = NewHandler(fsbcache.NewFixedSizeBubbleCache(2, 60), 0)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(h.maxValueSize, int64(MaxValueSizeDefault))
+}
+
+func Test_Handler(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = fsbcache.NewFixedSizeBubbleCache(2, 60)
+	var h, err = NewHandler(cache, 8)
+	aTest.MustBeNoError(err)
+	var status int
+	var body string
+
+	// Test #1. Missing Record.
+	status, _ = doRequest(t, h, http.MethodGet, "/records/a", "")
+	aTest.MustBeEqual(status, http.StatusNotFound)
+	status, _ = doRequest(t, h, http.MethodHead, "/records/a", "")
+	aTest.MustBeEqual(status, http.StatusNotFound)
+
+	// Test #2. Addition and Request.
+	status, _ = doRequest(t, h, http.MethodPut, "/records/a", `{"x":1}`)
+	aTest.MustBeEqual(status, http.StatusNoContent)
+	status, body = doRequest(t, h, http.MethodGet, "/records/a", "")
+	aTest.MustBeEqual(status, http.StatusOK)
+	aTest.MustBeEqual(body, `{"x":1}`)
+	status, _ = doRequest(t, h, http.MethodHead, "/records/a", "")
+	aTest.MustBeEqual(status, http.StatusOK)
+
+	// Test #3. Too large Value.
+	status, _ = doRequest(t, h, http.MethodPut, "/records/b", "123456789")
+	aTest.MustBeEqual(status, http.StatusRequestEntityTooLarge)
+
+	// Test #4. List.
+	_, _ = doRequest(t, h, http.MethodPut, "/records/b", "2")
+	status, body = doRequest(t, h, http.MethodGet, "/records", "")
+	aTest.MustBeEqual(status, http.StatusOK)
+	var list UIDList
+	aTest.MustBeNoError(json.Unmarshal([]byte(body), &list))
+	aTest.MustBeEqual(list.UIDs, []string{"b", "a"})
+
+	// Test #5. Deletion.
+	status, _ = doRequest(t, h, http.MethodDelete, "/records/a", "")
+	aTest.MustBeEqual(status, http.StatusNoContent)
+	status, body = doRequest(t, h, http.MethodDelete, "/records/a", "")
+	aTest.MustBeEqual(status, http.StatusNotFound)
+	aTest.MustBeEqual(strings.Contains(body, `"error"`), true)
+
+	// Test #6. Statistics.
+	status, body = doRequest(t, h, http.MethodGet, "/stats", "")
+	aTest.MustBeEqual(status, http.StatusOK)
+	var stats Stats
+	aTest.MustBeNoError(json.Unmarshal([]byte(body), &stats))
+	aTest.MustBeEqual(stats.Capacity, uint(2))
+	aTest.MustBeEqual(stats.RecordTTL, uint(60))
+	aTest.MustBeEqual(stats.Records, uint(1))
+	aTest.MustBeEqual(stats.Hits, uint64(1))
+	aTest.MustBeEqual(stats.Misses, uint64(1))
+
+	// Test #7. Bad Requests.
+	status, _ = doRequest(t, h, http.MethodPost, "/records/a", "")
+	aTest.MustBeEqual(status, http.StatusMethodNotAllowed)
+	status, _ = doRequest(t, h, http.MethodPut, "/records/", "")
+	aTest.MustBeEqual(status, http.StatusBadRequest)
+	status, _ = doRequest(t, h, http.MethodPost, "/stats", "")
+	aTest.MustBeEqual(status, http.StatusMethodNotAllowed)
+
+	// Test #8. Not a Byte Array is encoded by the Cache's Codec.
+	cache.SetCodec(fsbcache.JSONCodec{})
+	_ = cache.AddRecord(&fsbcache.FixedSizeBubbleCacheRecord{UID: "c", Data: 3})
+	status, body = doRequest(t, h, http.MethodGet, "/records/c", "")
+	aTest.MustBeEqual(status, http.StatusOK)
+	aTest.MustBeEqual(body, "3")
+	cache.SetCodec(fsbcache.RawCodec{})
+	status, body = doRequest(t, h, http.MethodGet, "/records/c", "")
+	aTest.MustBeEqual(status, http.StatusInternalServerError)
+	aTest.MustBeEqual(strings.Contains(body, ErrValueCanNotBeEncoded), true)
+
+	// Test #9. Data with Bytes.
+	_ = cache.AddRecord(&fsbcache.FixedSizeBubbleCacheRecord{UID: "d", Data: bytes.NewBufferString("dd")})
+	status, body = doRequest(t, h, http.MethodGet, "/records/d", "")
+	aTest.MustBeEqual(status, http.StatusOK)
+	aTest.MustBeEqual(body, "dd")
+}
@@ -0,0 +1,9 @@
+// Fixed Size Bubble Cache HTTP Server.
+
+package httpserver
+
+// Error Messages.
+const (
+	ErrMethodIsNotAllowed   = `Method is not allowed`
+	ErrValueCanNotBeEncoded = `Record's Value can not be encoded`
+)
@@ -0,0 +1,181 @@
+// Fixed Size Bubble Cache Invalidation.
+
+package invalidation
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	fsbcache "github.com/vault-thirteen/FixedSizeBubbleCache"
+)
+
+// Message Format.
+//
+// A Message is encoded as:
+//
+//	Format Version (1 Byte), Kind (1 Byte),
+//	Source Size (uint16), Source Bytes,
+//	UID Size (uint32), UID Bytes.
+//
+// All Integers are written in the Big-Endian Byte Order.
+const (
+	MessageFormatVersion = byte(2)
+
+	// Maximum Size of an encoded Message. It fits into a UDP Datagram.
+	MaxMessageSize = 60 * 1024
+
+	messageFixedSize = 1 + 1 + 2 + 4
+)
+
+// Kind of an Invalidation Message.
+type MessageKind byte
+
+// Kinds of Invalidation Messages.
+const (
+	// Invalidation of a single Record. The Record's UID may be empty.
+	MessageKindRecord = MessageKind(0)
+
+	// Invalidation of all Records. The UID is not used.
+	MessageKindAll = MessageKind(1)
+)
+
+// An Invalidation Message.
+type Message struct {
+
+	// Identifier of the Instance which has published the Message. Instances
+	// ignore their own Messages.
+	Source string
+
+	// Kind of the Invalidation.
+	Kind MessageKind
+
+	// UID of the invalidated Record.
+	UID fsbcache.FixedSizeBubbleCacheRecordUID
+}
+
+// A Function which receives Messages. It is called sequentially for the
+// Messages of a single Sender.
+type Handler func(message Message)
+
+// A Bus which delivers Invalidation Messages between Instances.
+//
+// Delivery is best-effort: a Message may be lost, e.g. when a Peer is not
+// available. TTLs of Records limit the Staleness in such Cases.
+type Bus interface {
+
+	// Publishes the Message to all Subscribers.
+	Publish(message Message) (err error)
+
+	// Subscribes the Handler to all Messages. Returns a Function which
+	// cancels the Subscription.
+	Subscribe(handler Handler) (unsubscribe func())
+
+	// Closes the Bus.
+	Close() (err error)
+}
+
+// A List of Handlers shared by the Bus Implementations.
+type handlers struct {
+	lock     sync.Mutex
+	byID     map[int]Handler
+	sequence int
+}
+
+// Adds a Handler. Returns a Function which removes it.
+func (h *handlers) add(
+	handler Handler,
+) (remove func()) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.byID == nil {
+		h.byID = make(map[int]Handler)
+	}
+	h.sequence++
+	var id = h.sequence
+	h.byID[id] = handler
+
+	return func() {
+		h.lock.Lock()
+		defer h.lock.Unlock()
+
+		delete(h.byID, id)
+	}
+}
+
+// Delivers the Message to all Handlers.
+func (h *handlers) deliver(
+	message Message,
+) {
+	h.lock.Lock()
+	var list = make([]Handler, 0, len(h.byID))
+	for _, handler := range h.byID {
+		list = append(list, handler)
+	}
+	h.lock.Unlock()
+
+	for _, handler := range list {
+		handler(message)
+	}
+}
+
+// Encodes the Message.
+func encodeMessage(
+	message Message,
+) (encoded []byte, err error) {
+	var size = messageFixedSize + len(message.Source) + len(message.UID)
+	if (size > MaxMessageSize) || (len(message.Source) > 0xFFFF) {
+		err = errors.New(ErrMessageIsTooLarge)
+		return
+	}
+
+	encoded = make([]byte, 0, size)
+	encoded = append(encoded, MessageFormatVersion, byte(message.Kind))
+	var sizeBuf [4]byte
+	binary.BigEndian.PutUint16(sizeBuf[:2], uint16(len(message.Source)))
+	encoded = append(encoded, sizeBuf[:2]...)
+	encoded = append(encoded, message.Source...)
+	binary.BigEndian.PutUint32(sizeBuf[:], uint32(len(message.UID)))
+	encoded = append(encoded, sizeBuf[:]...)
+	encoded = append(encoded, message.UID...)
+	return
+}
+
+// Decodes the Message.
+func decodeMessage(
+	p []byte,
+) (message Message, err error) {
+	if len(p) < messageFixedSize {
+		err = errors.New(ErrMessageIsBroken)
+		return
+	}
+	if p[0] != MessageFormatVersion {
+		err = fmt.Errorf(ErrfMessageVersionIsNotSupported, p[0])
+		return
+	}
+	message.Kind = MessageKind(p[1])
+	if (message.Kind != MessageKindRecord) && (message.Kind != MessageKindAll) {
+		err = fmt.Errorf(ErrfMessageKindIsNotSupported, p[1])
+		return
+	}
+
+	var sourceSize = int(binary.BigEndian.Uint16(p[2:4]))
+	p = p[4:]
+	if sourceSize+4 > len(p) {
+		err = errors.New(ErrMessageIsBroken)
+		return
+	}
+	message.Source = string(p[:sourceSize])
+	p = p[sourceSize:]
+
+	var uidSize = binary.BigEndian.Uint32(p[0:4])
+	p = p[4:]
+	if uint64(uidSize) != uint64(len(p)) {
+		err = errors.New(ErrMessageIsBroken)
+		return
+	}
+	message.UID = string(p)
+	return
+}
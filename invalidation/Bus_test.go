@@ -0,0 +1,82 @@
+// Fixed Size Bubble Cache Invalidation.
+
+package invalidation
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/vault-thirteen/tester"
+)
+
+func Test_encodeMessage(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var encoded []byte
+	var message Message
+	var err error
+
+	// Test #1. Round Trip.
+	encoded, err = encodeMessage(Message{Source: "node-a", UID: "key"})
+	aTest.MustBeNoError(err)
+	message, err = decodeMessage(encoded)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(message, Message{Source: "node-a", UID: "key"})
+
+	// Test #2. Empty UID and Invalidation of all Records.
+	encoded, err = encodeMessage(Message{Source: "node-a"})
+	aTest.MustBeNoError(err)
+	message, err = decodeMessage(encoded)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(message, Message{Source: "node-a"})
+	encoded, err = encodeMessage(Message{Source: "node-a", Kind: MessageKindAll})
+	aTest.MustBeNoError(err)
+	message, err = decodeMessage(encoded)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(message, Message{Source: "node-a", Kind: MessageKindAll})
+
+	// Test #3. Too large Message.
+	_, err = encodeMessage(Message{Source: "node-a", UID: strings.Repeat("x", MaxMessageSize)})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrMessageIsTooLarge)
+}
+
+func Test_decodeMessage(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var encoded []byte
+	var err error
+
+	encoded, err = encodeMessage(Message{Source: "node-a", UID: "key"})
+	aTest.MustBeNoError(err)
+
+	// Test #1. Short Message.
+	_, err = decodeMessage(encoded[:3])
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrMessageIsBroken)
+
+	// Test #2. Truncated UID.
+	_, err = decodeMessage(encoded[:len(encoded)-1])
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrMessageIsBroken)
+
+	// Test #3. Source Size out of Range.
+	var broken = append([]byte(nil), encoded...)
+	broken[2] = 0xFF
+	_, err = decodeMessage(broken)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrMessageIsBroken)
+
+	// Test #4. Unknown Version.
+	broken = append([]byte(nil), encoded...)
+	broken[0] = 99
+	_, err = decodeMessage(broken)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfMessageVersionIsNotSupported, 99))
+
+	// Test #5. Unknown Kind.
+	broken = append([]byte(nil), encoded...)
+	broken[1] = 99
+	_, err = decodeMessage(broken)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfMessageKindIsNotSupported, 99))
+}
@@ -0,0 +1,140 @@
+// Fixed Size Bubble Cache Invalidation.
+
+package invalidation
+
+import (
+	"errors"
+
+	fsbcache "github.com/vault-thirteen/FixedSizeBubbleCache"
+)
+
+// A fixed-Size Bubble Cache connected to an Invalidation Bus.
+//
+// Additions and Deletions of Records publish Invalidations on the Bus.
+// Invalidations published by other Instances delete the Records from the
+// local Cache, so that they are loaded again on the next Request.
+type Cache struct {
+	cache       *fsbcache.FixedSizeBubbleCache
+	bus         Bus
+	source      string
+	unsubscribe func()
+}
+
+// Creates a new Cache connected to the Bus. The Source identifies this
+// Instance among all the Subscribers of the Bus and must be unique.
+func NewCache(
+	cache *fsbcache.FixedSizeBubbleCache,
+	bus Bus,
+	source string,
+) (ic *Cache, err error) {
+	if cache == nil {
+		err = errors.New(fsbcache.ErrCacheIsNotSet)
+		return
+	}
+	if bus == nil {
+		err = errors.New(ErrBusIsNotSet)
+		return
+	}
+	if len(source) == 0 {
+		err = errors.New(ErrSourceIsNotSet)
+		return
+	}
+
+	ic = &Cache{
+		cache:  cache,
+		bus:    bus,
+		source: source,
+	}
+	ic.unsubscribe = bus.Subscribe(ic.apply)
+	return
+}
+
+// Returns the wrapped Cache.
+func (ic *Cache) Cache() *fsbcache.FixedSizeBubbleCache {
+	return ic.cache
+}
+
+// Adds the Record to the Cache and invalidates it on other Instances.
+func (ic *Cache) AddRecord(
+	record *fsbcache.FixedSizeBubbleCacheRecord,
+) (err error) {
+	err = ic.cache.AddRecord(record)
+	if err != nil {
+		return
+	}
+	return ic.publish(MessageKindRecord, record.UID)
+}
+
+// Adds the Record with an individual TTL to the Cache and invalidates it on
+// other Instances.
+func (ic *Cache) AddRecordWithTTL(
+	record *fsbcache.FixedSizeBubbleCacheRecord,
+	ttl uint,
+) (err error) {
+	err = ic.cache.AddRecordWithTTL(record, ttl)
+	if err != nil {
+		return
+	}
+	return ic.publish(MessageKindRecord, record.UID)
+}
+
+// Deletes the Record from the Cache and invalidates it on other Instances.
+// Other Instances are notified even when the Record is absent in the local
+// Cache.
+func (ic *Cache) DeleteRecordByUID(
+	uid fsbcache.FixedSizeBubbleCacheRecordUID,
+) (err error) {
+	var deleteErr = ic.cache.DeleteRecordByUID(uid)
+	err = ic.publish(MessageKindRecord, uid)
+	if err != nil {
+		return
+	}
+	return deleteErr
+}
+
+// Deletes all Records from the Cache and from the Caches of other Instances.
+func (ic *Cache) Clear() (err error) {
+	err = ic.cache.Clear()
+	if err != nil {
+		return
+	}
+	return ic.publish(MessageKindAll, "")
+}
+
+// Gets the Record's Data by its UID.
+func (ic *Cache) GetActualRecordDataByUID(
+	uid fsbcache.FixedSizeBubbleCacheRecordUID,
+) (data interface{}, err error) {
+	return ic.cache.GetActualRecordDataByUID(uid)
+}
+
+// Stops applying Invalidations of other Instances. The Bus is not closed.
+func (ic *Cache) Close() (err error) {
+	ic.unsubscribe()
+	return
+}
+
+// Publishes an Invalidation of the Record or of all Records.
+func (ic *Cache) publish(
+	kind MessageKind,
+	uid fsbcache.FixedSizeBubbleCacheRecordUID,
+) (err error) {
+	return ic.bus.Publish(Message{Source: ic.source, Kind: kind, UID: uid})
+}
+
+// Applies an Invalidation published by another Instance.
+func (ic *Cache) apply(
+	message Message,
+) {
+	if message.Source == ic.source {
+		return
+	}
+
+	if message.Kind == MessageKindAll {
+		_ = ic.cache.Clear()
+		return
+	}
+
+	// The Record may be absent in the local Cache.
+	_ = ic.cache.DeleteRecordByUID(message.UID)
+}
@@ -0,0 +1,123 @@
+// Fixed Size Bubble Cache Invalidation.
+
+package invalidation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/tester"
+
+	fsbcache "github.com/vault-thirteen/FixedSizeBubbleCache"
+)
+
+func Test_NewCache(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = fsbcache.NewFixedSizeBubbleCache(10, 60)
+	var err error
+
+	// Test #1. No Cache.
+	_, err = NewCache(nil, NewMemoryBus(), "a")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fsbcache.ErrCacheIsNotSet)
+
+	// Test #2. No Bus.
+	_, err = NewCache(cache, nil, "a")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrBusIsNotSet)
+
+	// Test #3. No Source.
+	_, err = NewCache(cache, NewMemoryBus(), "")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrSourceIsNotSet)
+}
+
+func Test_Cache_MemoryBus(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var bus = NewMemoryBus()
+	var a, b *Cache
+	var err error
+	a, err = NewCache(fsbcache.NewFixedSizeBubbleCache(10, 60), bus, "a")
+	aTest.MustBeNoError(err)
+	b, err = NewCache(fsbcache.NewFixedSizeBubbleCache(10, 60), bus, "b")
+	aTest.MustBeNoError(err)
+
+	// Test #1. Update invalidates the Record on other Instances only.
+	aTest.MustBeNoError(b.AddRecord(&fsbcache.FixedSizeBubbleCacheRecord{UID: "x", Data: "old"}))
+	aTest.MustBeNoError(a.AddRecord(&fsbcache.FixedSizeBubbleCacheRecord{UID: "x", Data: "new"}))
+	aTest.MustBeEqual(a.Cache().RecordUIDExists("x"), true)
+	aTest.MustBeEqual(b.Cache().RecordUIDExists("x"), false)
+
+	// Test #2. Record with TTL.
+	aTest.MustBeNoError(a.AddRecord(&fsbcache.FixedSizeBubbleCacheRecord{UID: "y", Data: "1"}))
+	aTest.MustBeNoError(b.AddRecordWithTTL(&fsbcache.FixedSizeBubbleCacheRecord{UID: "y", Data: "2"}, 5))
+	aTest.MustBeEqual(a.Cache().RecordUIDExists("y"), false)
+	var data interface{}
+	data, err = b.GetActualRecordDataByUID("y")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "2")
+
+	// Test #3. Deletion of a Record absent locally is published too.
+	err = a.DeleteRecordByUID("y")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(b.Cache().RecordUIDExists("y"), false)
+
+	// Test #4. Empty UID does not invalidate all Records.
+	aTest.MustBeNoError(b.AddRecord(&fsbcache.FixedSizeBubbleCacheRecord{UID: "z", Data: "1"}))
+	err = a.DeleteRecordByUID("")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(b.Cache().RecordUIDExists("z"), true)
+
+	// Test #5. Clear.
+	aTest.MustBeNoError(a.Clear())
+	aTest.MustBeEqual(a.Cache().RecordUIDExists("x"), false)
+	aTest.MustBeEqual(b.Cache().RecordUIDExists("z"), false)
+
+	// Test #6. Closed Cache does not apply Invalidations.
+	aTest.MustBeNoError(b.AddRecord(&fsbcache.FixedSizeBubbleCacheRecord{UID: "w", Data: "1"}))
+	aTest.MustBeNoError(b.Close())
+	err = a.DeleteRecordByUID("w")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(b.Cache().RecordUIDExists("w"), true)
+}
+
+func Test_Cache_NetworkBus(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var busA, busB = startTestNetworkBuses(t, NetworkTCP)
+	defer func() {
+		_ = busA.Close()
+		_ = busB.Close()
+	}()
+	var a, b *Cache
+	var err error
+	a, err = NewCache(fsbcache.NewFixedSizeBubbleCache(10, 60), busA, "a")
+	aTest.MustBeNoError(err)
+	b, err = NewCache(fsbcache.NewFixedSizeBubbleCache(10, 60), busB, "b")
+	aTest.MustBeNoError(err)
+
+	// Test #1. Update on one Node invalidates the Record on its Peer.
+	// Records of the Peer are added without publishing Invalidations.
+	aTest.MustBeNoError(b.Cache().AddRecord(&fsbcache.FixedSizeBubbleCacheRecord{UID: "x", Data: "1"}))
+	aTest.MustBeNoError(a.AddRecord(&fsbcache.FixedSizeBubbleCacheRecord{UID: "x", Data: "2"}))
+	waitForTestRecordDeletion(b.Cache(), "x")
+	aTest.MustBeEqual(b.Cache().RecordUIDExists("x"), false)
+	aTest.MustBeEqual(a.Cache().RecordUIDExists("x"), true)
+
+	// Test #2. Deletion on one Node invalidates the Record on its Peer.
+	aTest.MustBeNoError(b.Cache().AddRecord(&fsbcache.FixedSizeBubbleCacheRecord{UID: "y", Data: "1"}))
+	err = a.DeleteRecordByUID("y")
+	aTest.MustBeAnError(err)
+	waitForTestRecordDeletion(b.Cache(), "y")
+	aTest.MustBeEqual(b.Cache().RecordUIDExists("y"), false)
+}
+
+// Waits until the Record is deleted from the Cache.
+func waitForTestRecordDeletion(
+	cache *fsbcache.FixedSizeBubbleCache,
+	uid fsbcache.FixedSizeBubbleCacheRecordUID,
+) {
+	var deadline = time.Now().Add(time.Second * 5)
+	for cache.RecordUIDExists(uid) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond * 10)
+	}
+}
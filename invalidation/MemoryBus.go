@@ -0,0 +1,53 @@
+// Fixed Size Bubble Cache Invalidation.
+
+package invalidation
+
+import (
+	"errors"
+	"sync"
+)
+
+// A Bus which delivers Messages within a single Process, e.g. between
+// several Caches or in Tests. Messages are delivered synchronously.
+type MemoryBus struct {
+	handlers handlers
+
+	lock     sync.Mutex
+	isClosed bool
+}
+
+// Creates a new in-memory Bus.
+func NewMemoryBus() (bus *MemoryBus) {
+	return new(MemoryBus)
+}
+
+// Publishes the Message to all Subscribers.
+func (b *MemoryBus) Publish(
+	message Message,
+) (err error) {
+	b.lock.Lock()
+	var isClosed = b.isClosed
+	b.lock.Unlock()
+	if isClosed {
+		return errors.New(ErrBusIsClosed)
+	}
+
+	b.handlers.deliver(message)
+	return
+}
+
+// Subscribes the Handler to all Messages.
+func (b *MemoryBus) Subscribe(
+	handler Handler,
+) (unsubscribe func()) {
+	return b.handlers.add(handler)
+}
+
+// Closes the Bus.
+func (b *MemoryBus) Close() (err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.isClosed = true
+	return
+}
@@ -0,0 +1,41 @@
+// Fixed Size Bubble Cache Invalidation.
+
+package invalidation
+
+import (
+	"testing"
+
+	"github.com/vault-thirteen/tester"
+)
+
+func Test_MemoryBus(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var bus = NewMemoryBus()
+	var received []Message
+	var err error
+
+	// Test #1. Delivery to all Subscribers.
+	var unsubscribe = bus.Subscribe(func(message Message) {
+		received = append(received, message)
+	})
+	_ = bus.Subscribe(func(message Message) {
+		received = append(received, message)
+	})
+	err = bus.Publish(Message{Source: "a", UID: "x"})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(received, []Message{{Source: "a", UID: "x"}, {Source: "a", UID: "x"}})
+
+	// Test #2. Cancelled Subscription.
+	unsubscribe()
+	received = nil
+	err = bus.Publish(Message{Source: "a", UID: "y"})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(received, []Message{{Source: "a", UID: "y"}})
+
+	// Test #3. Closed Bus.
+	err = bus.Close()
+	aTest.MustBeNoError(err)
+	err = bus.Publish(Message{Source: "a", UID: "z"})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrBusIsClosed)
+}
@@ -0,0 +1,524 @@
+// Fixed Size Bubble Cache Invalidation.
+
+package invalidation
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Networks supported by the Network Bus.
+const (
+	NetworkUDP = "udp"
+	NetworkTCP = "tcp"
+)
+
+// Default Settings of the Network Bus.
+const (
+	NetworkTimeoutDefault   = time.Second
+	NetworkQueueSizeDefault = 1024
+)
+
+// Settings of the Network Bus. Zero Values select the default Settings.
+type NetworkBusSettings struct {
+
+	// Network: 'udp' or 'tcp'. UDP sends each Message in a single Datagram,
+	// TCP keeps a Connection to each Peer and frames Messages by their Size.
+	Network string
+
+	// Address on which Messages of the Peers are received.
+	ListenAddress string
+
+	// Addresses of the Peers. They may also be set later with 'SetPeers'.
+	Peers []string
+
+	// Timeout of connecting to a Peer and of sending a Message.
+	Timeout time.Duration
+
+	// Maximum Count of Messages waiting to be sent to a TCP Peer. Messages
+	// published while the Queue of a Peer is full are not sent to it.
+	QueueSize int
+}
+
+// A Bus which delivers Messages to a static List of Peers over UDP or TCP.
+// Messages published on this Bus are sent to the Peers only, Messages
+// received from the Peers are delivered to the local Subscribers only.
+//
+// Over TCP, each Peer has its own Queue of Messages and its own Goroutine
+// which sends them, so that a slow or unavailable Peer does not delay the
+// Publisher and other Peers.
+type NetworkBus struct {
+	network   string
+	timeout   time.Duration
+	queueSize int
+
+	handlers handlers
+
+	// UDP.
+	packetConn net.PacketConn
+
+	// TCP.
+	listener net.Listener
+
+	lock           sync.Mutex
+	peers          []string
+	udpPeers       []*net.UDPAddr
+	tcpConnections map[string]net.Conn
+	senders        map[string]*peerSender
+	incoming       map[net.Conn]bool
+	isClosed       bool
+	wg             sync.WaitGroup
+}
+
+// Creates a new Network Bus and starts receiving Messages.
+func NewNetworkBus(
+	settings NetworkBusSettings,
+) (bus *NetworkBus, err error) {
+	bus = &NetworkBus{
+		network:        strings.ToLower(settings.Network),
+		timeout:        settings.Timeout,
+		queueSize:      settings.QueueSize,
+		tcpConnections: make(map[string]net.Conn),
+		senders:        make(map[string]*peerSender),
+		incoming:       make(map[net.Conn]bool),
+	}
+	if bus.timeout <= 0 {
+		bus.timeout = NetworkTimeoutDefault
+	}
+	if bus.queueSize <= 0 {
+		bus.queueSize = NetworkQueueSizeDefault
+	}
+
+	switch bus.network {
+	case NetworkUDP:
+		bus.packetConn, err = net.ListenPacket(NetworkUDP, settings.ListenAddress)
+		if err != nil {
+			return nil, err
+		}
+		bus.wg.Add(1)
+		go bus.receivePackets()
+
+	case NetworkTCP:
+		bus.listener, err = net.Listen(NetworkTCP, settings.ListenAddress)
+		if err != nil {
+			return nil, err
+		}
+		bus.wg.Add(1)
+		go bus.acceptConnections()
+
+	default:
+		return nil, fmt.Errorf(ErrfNetworkIsNotSupported, settings.Network)
+	}
+
+	err = bus.SetPeers(settings.Peers...)
+	if err != nil {
+		_ = bus.Close()
+		return nil, err
+	}
+	return
+}
+
+// Returns the Address on which Messages are received.
+func (b *NetworkBus) Addr() (addr net.Addr) {
+	if b.packetConn != nil {
+		return b.packetConn.LocalAddr()
+	}
+	return b.listener.Addr()
+}
+
+// Sets the Addresses of the Peers. Connections to the removed Peers are
+// closed and their queued Messages are dropped.
+func (b *NetworkBus) SetPeers(
+	peers ...string,
+) (err error) {
+	var udpPeers []*net.UDPAddr
+	if b.network == NetworkUDP {
+		udpPeers = make([]*net.UDPAddr, 0, len(peers))
+		var addr *net.UDPAddr
+		for _, peer := range peers {
+			addr, err = net.ResolveUDPAddr(NetworkUDP, peer)
+			if err != nil {
+				return err
+			}
+			udpPeers = append(udpPeers, addr)
+		}
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.isClosed {
+		return errors.New(ErrBusIsClosed)
+	}
+	b.peers = append([]string(nil), peers...)
+	b.udpPeers = udpPeers
+
+	var isListed = make(map[string]bool, len(peers))
+	for _, peer := range peers {
+		isListed[peer] = true
+	}
+	for peer, conn := range b.tcpConnections {
+		if !isListed[peer] {
+			_ = conn.Close()
+			delete(b.tcpConnections, peer)
+		}
+	}
+	for peer, sender := range b.senders {
+		if !isListed[peer] {
+			close(sender.stop)
+			delete(b.senders, peer)
+		}
+	}
+	if b.network == NetworkTCP {
+		for _, peer := range peers {
+			if b.senders[peer] == nil {
+				b.startSender(peer)
+			}
+		}
+	}
+	return
+}
+
+// Publishes the Message to all Peers. Delivery is attempted to every Peer;
+// the Error lists the Peers which could not be reached.
+//
+// Over TCP, the Message is queued for each Peer and is sent in the Background.
+// The Error lists the Peers whose Queues are full and the Peers to which the
+// previous Message could not be delivered.
+func (b *NetworkBus) Publish(
+	message Message,
+) (err error) {
+	var payload []byte
+	payload, err = encodeMessage(message)
+	if err != nil {
+		return err
+	}
+
+	var failedPeers []string
+	if b.network == NetworkTCP {
+		var frame = make([]byte, 4, 4+len(payload))
+		binary.BigEndian.PutUint32(frame, uint32(len(payload)))
+		frame = append(frame, payload...)
+
+		b.lock.Lock()
+		if b.isClosed {
+			b.lock.Unlock()
+			return errors.New(ErrBusIsClosed)
+		}
+		for _, peer := range b.peers {
+			if !b.senders[peer].enqueue(frame) {
+				failedPeers = append(failedPeers, peer)
+			}
+		}
+		b.lock.Unlock()
+	} else {
+		b.lock.Lock()
+		if b.isClosed {
+			b.lock.Unlock()
+			return errors.New(ErrBusIsClosed)
+		}
+		var peers = b.peers
+		var udpPeers = b.udpPeers
+		b.lock.Unlock()
+
+		for i, addr := range udpPeers {
+			err = b.packetConn.SetWriteDeadline(time.Now().Add(b.timeout))
+			if err == nil {
+				_, err = b.packetConn.WriteTo(payload, addr)
+			}
+			if err != nil {
+				failedPeers = append(failedPeers, peers[i])
+			}
+		}
+	}
+
+	if len(failedPeers) > 0 {
+		return fmt.Errorf(ErrfMessageCouldNotBeDelivered, len(failedPeers), strings.Join(failedPeers, ", "))
+	}
+	return nil
+}
+
+// A Sender of Frames to a TCP Peer.
+type peerSender struct {
+	peer   string
+	frames chan []byte
+	stop   chan struct{}
+
+	// Error of the last Sending. It is protected by the Lock of the Bus.
+	lastErr error
+}
+
+// Starts a Sender of Frames to the Peer. The Bus must be locked.
+func (b *NetworkBus) startSender(
+	peer string,
+) {
+	var sender = &peerSender{
+		peer:   peer,
+		frames: make(chan []byte, b.queueSize),
+		stop:   make(chan struct{}),
+	}
+	b.senders[peer] = sender
+	b.wg.Add(1)
+	go b.runSender(sender)
+}
+
+// Queues the Frame unless the Queue is full. Returns 'false' if the Frame
+// is not queued or if the previous Frame could not be sent. The Bus must be
+// locked.
+func (s *peerSender) enqueue(
+	frame []byte,
+) (isQueued bool) {
+	select {
+	case s.frames <- frame:
+		return s.lastErr == nil
+	default:
+		return false
+	}
+}
+
+// Sends the queued Frames to the Peer until the Sender is stopped.
+func (b *NetworkBus) runSender(
+	sender *peerSender,
+) {
+	defer b.wg.Done()
+
+	for {
+		select {
+		case <-sender.stop:
+			return
+		case frame := <-sender.frames:
+			var err = b.sendFrame(sender.peer, frame)
+			b.lock.Lock()
+			sender.lastErr = err
+			b.lock.Unlock()
+		}
+	}
+}
+
+// Sends a Frame to the Peer over TCP. A broken Connection is re-established
+// once.
+func (b *NetworkBus) sendFrame(
+	peer string,
+	frame []byte,
+) (err error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		var conn net.Conn
+		conn, err = b.getConnection(peer)
+		if err != nil {
+			return err
+		}
+
+		err = conn.SetWriteDeadline(time.Now().Add(b.timeout))
+		if err == nil {
+			_, err = conn.Write(frame)
+		}
+		if err == nil {
+			return nil
+		}
+		b.dropConnection(peer, conn)
+	}
+	return err
+}
+
+// Returns an open Connection to the Peer, connecting if necessary.
+func (b *NetworkBus) getConnection(
+	peer string,
+) (conn net.Conn, err error) {
+	b.lock.Lock()
+	conn = b.tcpConnections[peer]
+	b.lock.Unlock()
+	if conn != nil {
+		return conn, nil
+	}
+
+	conn, err = net.DialTimeout(NetworkTCP, peer, b.timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.isClosed {
+		_ = conn.Close()
+		return nil, errors.New(ErrBusIsClosed)
+	}
+	if b.senders[peer] == nil {
+		// The Peer has been removed while it was being connected.
+		_ = conn.Close()
+		return nil, fmt.Errorf(ErrfPeerIsNotListed, peer)
+	}
+	var existingConn = b.tcpConnections[peer]
+	if existingConn != nil {
+		_ = conn.Close()
+		return existingConn, nil
+	}
+	b.tcpConnections[peer] = conn
+	return conn, nil
+}
+
+// Closes a broken Connection to the Peer.
+func (b *NetworkBus) dropConnection(
+	peer string,
+	conn net.Conn,
+) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.tcpConnections[peer] == conn {
+		delete(b.tcpConnections, peer)
+	}
+	_ = conn.Close()
+}
+
+// Subscribes the Handler to the Messages received from the Peers.
+func (b *NetworkBus) Subscribe(
+	handler Handler,
+) (unsubscribe func()) {
+	return b.handlers.add(handler)
+}
+
+// Receives UDP Datagrams until the Bus is closed. Broken Datagrams are
+// ignored. After an Error of Reading, the Reception is paused for a while.
+func (b *NetworkBus) receivePackets() {
+	defer b.wg.Done()
+
+	var buf = make([]byte, MaxMessageSize)
+	for {
+		var n, _, err = b.packetConn.ReadFrom(buf)
+		if err != nil {
+			if b.isClosing() {
+				return
+			}
+			time.Sleep(b.timeout / 10)
+			continue
+		}
+
+		var message Message
+		message, err = decodeMessage(buf[:n])
+		if err != nil {
+			continue
+		}
+		b.handlers.deliver(message)
+	}
+}
+
+// Accepts TCP Connections of the Peers until the Bus is closed.
+func (b *NetworkBus) acceptConnections() {
+	defer b.wg.Done()
+
+	for {
+		var conn, err = b.listener.Accept()
+		if err != nil {
+			if b.isClosing() {
+				return
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Temporary() {
+				time.Sleep(b.timeout / 10)
+				continue
+			}
+			return
+		}
+
+		b.lock.Lock()
+		if b.isClosed {
+			b.lock.Unlock()
+			_ = conn.Close()
+			return
+		}
+		b.incoming[conn] = true
+		b.wg.Add(1)
+		b.lock.Unlock()
+
+		go b.receiveFrames(conn)
+	}
+}
+
+// Receives Frames from a TCP Connection. A broken Frame closes the
+// Connection, the Peer will reconnect.
+func (b *NetworkBus) receiveFrames(
+	conn net.Conn,
+) {
+	defer b.wg.Done()
+	defer func() {
+		b.lock.Lock()
+		delete(b.incoming, conn)
+		b.lock.Unlock()
+		_ = conn.Close()
+	}()
+
+	var r = bufio.NewReader(conn)
+	var sizeBuf [4]byte
+	for {
+		var _, err = io.ReadFull(r, sizeBuf[:])
+		if err != nil {
+			return
+		}
+		var size = binary.BigEndian.Uint32(sizeBuf[:])
+		if size > MaxMessageSize {
+			return
+		}
+
+		var payload = make([]byte, size)
+		_, err = io.ReadFull(r, payload)
+		if err != nil {
+			return
+		}
+
+		var message Message
+		message, err = decodeMessage(payload)
+		if err != nil {
+			return
+		}
+		b.handlers.deliver(message)
+	}
+}
+
+// Checks whether the Bus is closed.
+func (b *NetworkBus) isClosing() (isClosed bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.isClosed
+}
+
+// Closes the Bus with all its Connections and waits for the receiving and
+// sending Goroutines to stop. Queued Messages are dropped.
+func (b *NetworkBus) Close() (err error) {
+	b.lock.Lock()
+	if b.isClosed {
+		b.lock.Unlock()
+		return nil
+	}
+	b.isClosed = true
+
+	if b.packetConn != nil {
+		err = b.packetConn.Close()
+	}
+	if b.listener != nil {
+		err = b.listener.Close()
+	}
+	for peer, conn := range b.tcpConnections {
+		_ = conn.Close()
+		delete(b.tcpConnections, peer)
+	}
+	for peer, sender := range b.senders {
+		close(sender.stop)
+		delete(b.senders, peer)
+	}
+	for conn := range b.incoming {
+		_ = conn.Close()
+	}
+	b.lock.Unlock()
+
+	b.wg.Wait()
+	return err
+}
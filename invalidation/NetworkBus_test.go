@@ -0,0 +1,178 @@
+// Fixed Size Bubble Cache Invalidation.
+
+package invalidation
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/tester"
+)
+
+// Starts two connected Network Buses on the loopback Interface.
+func startTestNetworkBuses(
+	t *testing.T,
+	network string,
+) (a *NetworkBus, b *NetworkBus) {
+	var err error
+	a, err = NewNetworkBus(NetworkBusSettings{Network: network, ListenAddress: "127.0.0.1:0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err = NewNetworkBus(NetworkBusSettings{Network: network, ListenAddress: "127.0.0.1:0", Peers: []string{a.Addr().String()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = a.SetPeers(b.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+// Waits for a Message.
+func receiveTestMessage(
+	messages chan Message,
+) (message Message, isReceived bool) {
+	select {
+	case message = <-messages:
+		return message, true
+	case <-time.After(time.Second * 5):
+		return Message{}, false
+	}
+}
+
+func Test_NewNetworkBus(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var err error
+
+	// Test #1. Unsupported Network.
+	_, err = NewNetworkBus(NetworkBusSettings{Network: "ipx"})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfNetworkIsNotSupported, "ipx"))
+
+	// Test #2. Default Settings.
+	var bus *NetworkBus
+	bus, err = NewNetworkBus(NetworkBusSettings{Network: "TCP", ListenAddress: "127.0.0.1:0"})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(bus.timeout, NetworkTimeoutDefault)
+	aTest.MustBeNoError(bus.Close())
+}
+
+func Test_NetworkBus_Publish(t *testing.T) {
+	for _, network := range []string{NetworkUDP, NetworkTCP} {
+		t.Run(network, func(t *testing.T) {
+			var aTest *tester.Test = tester.New(t)
+			var a, b = startTestNetworkBuses(t, network)
+			defer func() {
+				_ = a.Close()
+				_ = b.Close()
+			}()
+			var messagesOfA = make(chan Message, 10)
+			var messagesOfB = make(chan Message, 10)
+			_ = a.Subscribe(func(message Message) { messagesOfA <- message })
+			_ = b.Subscribe(func(message Message) { messagesOfB <- message })
+			var message Message
+			var isReceived bool
+			var err error
+
+			// Test #1. Messages are delivered to the Peers.
+			err = a.Publish(Message{Source: "a", UID: "x"})
+			aTest.MustBeNoError(err)
+			message, isReceived = receiveTestMessage(messagesOfB)
+			aTest.MustBeEqual(isReceived, true)
+			aTest.MustBeEqual(message, Message{Source: "a", UID: "x"})
+
+			err = b.Publish(Message{Source: "b", Kind: MessageKindAll})
+			aTest.MustBeNoError(err)
+			message, isReceived = receiveTestMessage(messagesOfA)
+			aTest.MustBeEqual(isReceived, true)
+			aTest.MustBeEqual(message, Message{Source: "b", Kind: MessageKindAll})
+
+			// Test #2. Messages are not delivered to the Publisher.
+			aTest.MustBeEqual(len(messagesOfA), 0)
+			aTest.MustBeEqual(len(messagesOfB), 0)
+
+			// Test #3. Closed Bus.
+			aTest.MustBeNoError(a.Close())
+			err = a.Publish(Message{Source: "a", UID: "y"})
+			aTest.MustBeAnError(err)
+			aTest.MustBeEqual(err.Error(), ErrBusIsClosed)
+		})
+	}
+}
+
+func Test_NetworkBus_Reconnect(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var a, b = startTestNetworkBuses(t, NetworkTCP)
+	defer func() {
+		_ = a.Close()
+		_ = b.Close()
+	}()
+	var messages = make(chan Message, 10)
+	_ = b.Subscribe(func(message Message) { messages <- message })
+	var isReceived bool
+	var err error
+
+	// Test #1. Broken outgoing Connection is re-established.
+	err = a.Publish(Message{Source: "a", UID: "x"})
+	aTest.MustBeNoError(err)
+	_, isReceived = receiveTestMessage(messages)
+	aTest.MustBeEqual(isReceived, true)
+
+	a.lock.Lock()
+	for _, conn := range a.tcpConnections {
+		_ = conn.Close()
+	}
+	a.lock.Unlock()
+
+	err = a.Publish(Message{Source: "a", UID: "y"})
+	aTest.MustBeNoError(err)
+	var message Message
+	message, isReceived = receiveTestMessage(messages)
+	aTest.MustBeEqual(isReceived, true)
+	aTest.MustBeEqual(message.UID, "y")
+
+	// Test #2. Unavailable Peer.
+	var peerAddress = b.Addr().String()
+	aTest.MustBeNoError(b.Close())
+	err = a.Publish(Message{Source: "a", UID: "z"})
+	for i := 0; (err == nil) && (i < 100); i++ {
+		// Messages are sent in the Background, and writes into a Connection
+		// closed by the Peer may succeed until the Reset is received.
+		time.Sleep(time.Millisecond * 10)
+		err = a.Publish(Message{Source: "a", UID: "z"})
+	}
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfMessageCouldNotBeDelivered, 1, peerAddress))
+}
+
+func Test_NetworkBus_FullQueue(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var bus, err = NewNetworkBus(NetworkBusSettings{Network: NetworkTCP, ListenAddress: "127.0.0.1:0"})
+	aTest.MustBeNoError(err)
+	defer func() {
+		_ = bus.Close()
+	}()
+
+	// A Peer whose Sender does not run, so its Queue is never emptied.
+	var peer = "127.0.0.1:1"
+	bus.lock.Lock()
+	bus.peers = []string{peer}
+	bus.senders[peer] = &peerSender{
+		peer:   peer,
+		frames: make(chan []byte, 1),
+		stop:   make(chan struct{}),
+	}
+	bus.lock.Unlock()
+
+	// Test #1. The Message is queued.
+	err = bus.Publish(Message{Source: "a", UID: "x"})
+	aTest.MustBeNoError(err)
+
+	// Test #2. The Queue is full, the Publisher is not blocked.
+	err = bus.Publish(Message{Source: "a", UID: "y"})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrfMessageCouldNotBeDelivered, 1, peer))
+}
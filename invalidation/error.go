@@ -0,0 +1,17 @@
+// Fixed Size Bubble Cache Invalidation.
+
+package invalidation
+
+// Error Messages.
+const (
+	ErrBusIsClosed                   = `Bus is closed`
+	ErrBusIsNotSet                   = `Bus is not set`
+	ErrSourceIsNotSet                = `Source is not set`
+	ErrMessageIsTooLarge             = `Message is too large`
+	ErrMessageIsBroken               = `Message is broken`
+	ErrfMessageVersionIsNotSupported = `Message Version %v is not supported`
+	ErrfMessageKindIsNotSupported    = `Message Kind %v is not supported`
+	ErrfNetworkIsNotSupported        = `Network '%v' is not supported`
+	ErrfMessageCouldNotBeDelivered   = `Message could not be delivered to %v Peer(s): %v`
+	ErrfPeerIsNotListed              = `Peer '%v' is not listed`
+)
@@ -0,0 +1,683 @@
+// Fixed Size Bubble Cache Memcached Server.
+
+package memcached
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	fsbcache "github.com/vault-thirteen/FixedSizeBubbleCache"
+)
+
+// Default maximum Size of an Item's Value in Bytes.
+const MaxValueSizeDefault = 1024 * 1024
+
+// Limits of the Protocol.
+const (
+	MaxKeySize = 250
+
+	// Expiration Times greater than this Number of Seconds (30 Days) are
+	// absolute Unix Times.
+	RelativeExpirationTimeLimit = 60 * 60 * 24 * 30
+
+	maxLineSize = 64 * 1024
+)
+
+// Replies of the Protocol.
+const (
+	replyStored    = "STORED\r\n"
+	replyNotStored = "NOT_STORED\r\n"
+	replyDeleted   = "DELETED\r\n"
+	replyNotFound  = "NOT_FOUND\r\n"
+	replyTouched   = "TOUCHED\r\n"
+	replyOK        = "OK\r\n"
+	replyEnd       = "END\r\n"
+	replyError     = "ERROR\r\n"
+
+	replyPrefixClientError = "CLIENT_ERROR "
+	replyPrefixServerError = "SERVER_ERROR "
+	lineEnd                = "\r\n"
+)
+
+// An Item stored by the Server as the Data of a Record.
+//
+// Records with '[]byte' Data, e.g. those added via the HTTP API, are served
+// as Items with zero Flags and zero CAS. Data of other Types is encoded by the
+// Cache's Codec.
+type Item struct {
+	Value []byte
+	Flags uint32
+	CAS   uint64
+}
+
+func init() {
+	gob.Register(Item{})
+}
+
+// Returns the Value of the Item, so that other APIs may serve it.
+func (i Item) Bytes() []byte {
+	return i.Value
+}
+
+// A Server which exposes a fixed-Size Bubble Cache via the Subset of the
+// memcached Text Protocol: 'get', 'gets', 'set', 'add', 'replace', 'delete',
+// 'touch', 'flush_all', 'stats' and 'quit'.
+//
+// Expiration Times of Items are mapped to absolute Expiration Times of
+// Records, which Requests do not extend. Zero Expiration Time selects the
+// Cache's Record TTL, which is counted from the last Access to the Item.
+// 'flush_all' clears the Cache.
+type Server struct {
+
+	// Counters are accessed atomically, so they go first for the Alignment.
+	casCounter       uint64
+	cmdGet           uint64
+	cmdSet           uint64
+	cmdTouch         uint64
+	cmdFlush         uint64
+	totalConnections uint64
+
+	cache        *fsbcache.FixedSizeBubbleCache
+	maxValueSize int
+	startTime    time.Time
+
+	// A Lock which protects the Lists of Listeners and Connections.
+	lock        sync.Mutex
+	listeners   map[net.Listener]bool
+	connections map[net.Conn]bool
+	isClosed    bool
+	wg          sync.WaitGroup
+}
+
+// Creates a new Server. Zero maximum Value Size selects the default Size.
+func NewServer(
+	cache *fsbcache.FixedSizeBubbleCache,
+	maxValueSize int,
+) (s *Server, err error) {
+	if cache == nil {
+		err = errors.New(fsbcache.ErrCacheIsNotSet)
+		return
+	}
+	if maxValueSize <= 0 {
+		maxValueSize = MaxValueSizeDefault
+	}
+
+	s = &Server{
+		cache:        cache,
+		maxValueSize: maxValueSize,
+		startTime:    time.Now(),
+		listeners:    make(map[net.Listener]bool),
+		connections:  make(map[net.Conn]bool),
+	}
+	return
+}
+
+// Accepts Connections on the Listener and serves them until the Server is
+// closed. Always returns a non-null Error.
+func (s *Server) Serve(
+	listener net.Listener,
+) (err error) {
+	s.lock.Lock()
+	if s.isClosed {
+		s.lock.Unlock()
+		return errors.New(ErrServerIsClosed)
+	}
+	s.listeners[listener] = true
+	s.lock.Unlock()
+
+	var conn net.Conn
+	for {
+		conn, err = listener.Accept()
+		if err != nil {
+			s.lock.Lock()
+			delete(s.listeners, listener)
+			if s.isClosed {
+				err = errors.New(ErrServerIsClosed)
+			}
+			s.lock.Unlock()
+			return
+		}
+
+		s.lock.Lock()
+		if s.isClosed {
+			s.lock.Unlock()
+			_ = conn.Close()
+			return errors.New(ErrServerIsClosed)
+		}
+		s.connections[conn] = true
+		s.wg.Add(1)
+		s.lock.Unlock()
+		atomic.AddUint64(&s.totalConnections, 1)
+
+		go s.serveConnection(conn)
+	}
+}
+
+// Closes all Listeners and Connections and waits for the Connections'
+// Handlers to stop.
+func (s *Server) Close() (err error) {
+	s.lock.Lock()
+	s.isClosed = true
+	for listener := range s.listeners {
+		var closeErr = listener.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}
+	for conn := range s.connections {
+		_ = conn.Close()
+	}
+	s.lock.Unlock()
+
+	s.wg.Wait()
+	return
+}
+
+// Serves the Commands of a Connection until it is closed.
+func (s *Server) serveConnection(
+	conn net.Conn,
+) {
+	defer func() {
+		_ = conn.Close()
+		s.lock.Lock()
+		delete(s.connections, conn)
+		s.lock.Unlock()
+		s.wg.Done()
+	}()
+
+	var r = bufio.NewReaderSize(conn, maxLineSize)
+	var w = bufio.NewWriter(conn)
+	var line []byte
+	var isQuit bool
+	var err error
+	for {
+		line, err = r.ReadSlice('\n')
+		// A too long Line is skipped, the Connection is kept.
+		if err == bufio.ErrBufferFull {
+			err = skipLine(r)
+			if err != nil {
+				return
+			}
+			err = writeClientError(w, ErrLineIsTooLong)
+			if err != nil {
+				return
+			}
+			err = w.Flush()
+			if err != nil {
+				return
+			}
+			continue
+		}
+		if err != nil {
+			return
+		}
+
+		isQuit, err = s.execute(bytes.Fields(line), r, w)
+		if (err != nil) || isQuit {
+			_ = w.Flush()
+			return
+		}
+
+		// Pipelined Commands are answered together.
+		if r.Buffered() == 0 {
+			err = w.Flush()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Executes a Command. Errors are the I/O Errors of the Connection.
+func (s *Server) execute(
+	fields [][]byte,
+	r *bufio.Reader,
+	w *bufio.Writer,
+) (isQuit bool, err error) {
+	if len(fields) == 0 {
+		_, err = w.WriteString(replyError)
+		return
+	}
+
+	var args = fields[1:]
+	switch string(fields[0]) {
+	case "get":
+		err = s.get(w, args, false)
+	case "gets":
+		err = s.get(w, args, true)
+	case "set", "add", "replace":
+		err = s.store(r, w, string(fields[0]), args)
+	case "delete":
+		err = s.delete(w, args)
+	case "touch":
+		err = s.touch(w, args)
+	case "flush_all":
+		err = s.flushAll(w, args)
+	case "stats":
+		err = s.stats(w, args)
+	case "quit":
+		isQuit = true
+	default:
+		_, err = w.WriteString(replyError)
+	}
+	return
+}
+
+// Executes the 'get' and 'gets' Commands: get <key>*.
+func (s *Server) get(
+	w *bufio.Writer,
+	args [][]byte,
+	withCAS bool,
+) (err error) {
+	if len(args) == 0 {
+		_, err = w.WriteString(replyError)
+		return
+	}
+	for _, key := range args {
+		if !isKeyValid(key) {
+			return writeClientError(w, ErrBadCommandLineFormat)
+		}
+	}
+
+	var data interface{}
+	var item Item
+	var ok bool
+	for _, key := range args {
+		atomic.AddUint64(&s.cmdGet, 1)
+		data, err = s.cache.GetActualRecordDataByUID(string(key))
+		if err != nil {
+			// The Item is missing, outdated or negative.
+			continue
+		}
+		item, ok = itemOf(data, s.cache.GetCodec())
+		if !ok {
+			continue
+		}
+
+		if withCAS {
+			_, err = fmt.Fprintf(w, "VALUE %s %d %d %d\r\n", key, item.Flags, len(item.Value), item.CAS)
+		} else {
+			_, err = fmt.Fprintf(w, "VALUE %s %d %d\r\n", key, item.Flags, len(item.Value))
+		}
+		if err != nil {
+			return
+		}
+		_, err = w.Write(item.Value)
+		if err != nil {
+			return
+		}
+		_, err = w.WriteString(lineEnd)
+		if err != nil {
+			return
+		}
+	}
+
+	_, err = w.WriteString(replyEnd)
+	return
+}
+
+// Executes the Storage Commands:
+// set|add|replace <key> <flags> <exptime> <bytes> [noreply].
+func (s *Server) store(
+	r *bufio.Reader,
+	w *bufio.Writer,
+	command string,
+	args [][]byte,
+) (err error) {
+	atomic.AddUint64(&s.cmdSet, 1)
+	if (len(args) < 4) || (len(args) > 5) {
+		return writeClientError(w, ErrBadCommandLineFormat)
+	}
+	var size uint64
+	size, err = strconv.ParseUint(string(args[3]), 10, 31)
+	if err != nil {
+		return writeClientError(w, ErrBadCommandLineFormat)
+	}
+
+	// The Data Block is read before any other Check to keep the Stream in
+	// Sync with the Client.
+	if size > uint64(s.maxValueSize) {
+		_, err = io.CopyN(ioutil.Discard, r, int64(size)+int64(len(lineEnd)))
+		if err != nil {
+			return
+		}
+		return writeServerError(w, ErrObjectIsTooLarge)
+	}
+	var block = make([]byte, size+uint64(len(lineEnd)))
+	_, err = io.ReadFull(r, block)
+	if err != nil {
+		return
+	}
+	if string(block[size:]) != lineEnd {
+		// The Rest of the Line is skipped.
+		if block[len(block)-1] != '\n' {
+			err = skipLine(r)
+			if err != nil {
+				return
+			}
+		}
+		return writeClientError(w, ErrBadDataChunk)
+	}
+
+	var key = args[0]
+	var flags uint64
+	var flagsErr error
+	flags, flagsErr = strconv.ParseUint(string(args[1]), 10, 32)
+	var exptime int64
+	var exptimeErr error
+	exptime, exptimeErr = strconv.ParseInt(string(args[2]), 10, 64)
+	var noReply bool
+	var noReplyIsValid bool
+	noReply, noReplyIsValid = parseNoReply(args[4:])
+	if !isKeyValid(key) || (flagsErr != nil) || (exptimeErr != nil) || !noReplyIsValid {
+		return writeClientError(w, ErrBadCommandLineFormat)
+	}
+
+	var reply string
+	reply, err = s.storeItem(command, string(key), Item{
+		Value: block[:size],
+		Flags: uint32(flags),
+	}, exptime)
+	if err != nil {
+		return writeServerError(w, err.Error())
+	}
+	if noReply {
+		return
+	}
+	_, err = w.WriteString(reply)
+	return
+}
+
+// Stores the Item according to the Command. Returns the Reply.
+//
+// The Checks of the 'add' and 'replace' Commands are made by the Cache
+// together with the Changes, so other Clients of the Cache can not interfere.
+// An Item with a passed Expiration Time is stored and expires immediately.
+func (s *Server) storeItem(
+	command string,
+	key string,
+	item Item,
+	exptime int64,
+) (reply string, err error) {
+	var expirationTime, isExpired = expirationTimeOf(exptime, time.Now())
+	if isExpired && (command == "set") {
+		_ = s.cache.DeleteRecordByUID(key)
+		return replyStored, nil
+	}
+
+	item.CAS = atomic.AddUint64(&s.casCounter, 1)
+	var record = &fsbcache.FixedSizeBubbleCacheRecord{
+		UID:  key,
+		Data: item,
+	}
+	record.SetExpirationTime(expirationTime)
+
+	var isStored = true
+	switch command {
+	case "add":
+		isStored, err = s.cache.AddIfAbsent(record)
+	case "replace":
+		isStored, err = s.cache.ReplaceIfPresent(record)
+	default:
+		err = s.cache.AddRecord(record)
+	}
+	if err != nil {
+		return
+	}
+	if !isStored {
+		return replyNotStored, nil
+	}
+	return replyStored, nil
+}
+
+// Executes the 'delete' Command: delete <key> [noreply].
+func (s *Server) delete(
+	w *bufio.Writer,
+	args [][]byte,
+) (err error) {
+	if len(args) == 0 {
+		return writeClientError(w, ErrBadCommandLineFormat)
+	}
+	var noReply, noReplyIsValid = parseNoReply(args[1:])
+	if !isKeyValid(args[0]) || !noReplyIsValid {
+		return writeClientError(w, ErrBadCommandLineFormat)
+	}
+	var key = string(args[0])
+
+	var reply = replyNotFound
+	if s.cache.DeleteRecordByUID(key) == nil {
+		reply = replyDeleted
+	}
+
+	if noReply {
+		return
+	}
+	_, err = w.WriteString(reply)
+	return
+}
+
+// Executes the 'touch' Command: touch <key> <exptime> [noreply].
+func (s *Server) touch(
+	w *bufio.Writer,
+	args [][]byte,
+) (err error) {
+	atomic.AddUint64(&s.cmdTouch, 1)
+	if len(args) < 2 {
+		return writeClientError(w, ErrBadCommandLineFormat)
+	}
+	var exptime, exptimeErr = strconv.ParseInt(string(args[1]), 10, 64)
+	var noReply, noReplyIsValid = parseNoReply(args[2:])
+	if !isKeyValid(args[0]) || (exptimeErr != nil) || !noReplyIsValid {
+		return writeClientError(w, ErrBadCommandLineFormat)
+	}
+	var key = string(args[0])
+
+	var reply = replyNotFound
+	var expirationTime, isExpired = expirationTimeOf(exptime, time.Now())
+	if isExpired {
+		if s.cache.DeleteRecordByUID(key) == nil {
+			reply = replyTouched
+		}
+	} else {
+		if s.cache.SetRecordExpirationTime(key, expirationTime) == nil {
+			reply = replyTouched
+		}
+	}
+
+	if noReply {
+		return
+	}
+	_, err = w.WriteString(reply)
+	return
+}
+
+// Executes the 'flush_all' Command: flush_all [0] [noreply].
+// Delayed Flushes are not supported.
+func (s *Server) flushAll(
+	w *bufio.Writer,
+	args [][]byte,
+) (err error) {
+	atomic.AddUint64(&s.cmdFlush, 1)
+	if (len(args) > 0) && (string(args[0]) != "noreply") {
+		var delay, delayErr = strconv.ParseInt(string(args[0]), 10, 64)
+		if delayErr != nil {
+			return writeClientError(w, ErrBadCommandLineFormat)
+		}
+		if delay != 0 {
+			return writeClientError(w, ErrDelayedFlushIsUnsupported)
+		}
+		args = args[1:]
+	}
+	var noReply, noReplyIsValid = parseNoReply(args)
+	if !noReplyIsValid {
+		return writeClientError(w, ErrBadCommandLineFormat)
+	}
+
+	err = s.cache.Clear()
+	if err != nil {
+		return writeServerError(w, err.Error())
+	}
+
+	if noReply {
+		return
+	}
+	_, err = w.WriteString(replyOK)
+	return
+}
+
+// Executes the 'stats' Command. Only general Statistics are supported.
+func (s *Server) stats(
+	w *bufio.Writer,
+	args [][]byte,
+) (err error) {
+	if len(args) > 0 {
+		_, err = w.WriteString(replyError)
+		return
+	}
+
+	s.lock.Lock()
+	var currentConnections = len(s.connections)
+	s.lock.Unlock()
+
+	var now = time.Now()
+	var cs = s.cache.Stats()
+	var stats = []struct {
+		name  string
+		value interface{}
+	}{
+		{"pid", os.Getpid()},
+		{"uptime", int64(now.Sub(s.startTime).Seconds())},
+		{"time", now.Unix()},
+		{"curr_connections", currentConnections},
+		{"total_connections", atomic.LoadUint64(&s.totalConnections)},
+		{"cmd_get", atomic.LoadUint64(&s.cmdGet)},
+		{"cmd_set", atomic.LoadUint64(&s.cmdSet)},
+		{"cmd_touch", atomic.LoadUint64(&s.cmdTouch)},
+		{"cmd_flush", atomic.LoadUint64(&s.cmdFlush)},
+		{"get_hits", cs.Hits},
+		{"get_misses", cs.Misses},
+		{"get_expired", cs.Expirations},
+		{"evictions", cs.Evictions},
+		{"curr_items", cs.Records},
+		{"limit_items", s.cache.GetCapacity()},
+	}
+	for _, stat := range stats {
+		_, err = fmt.Fprintf(w, "STAT %s %v\r\n", stat.name, stat.value)
+		if err != nil {
+			return
+		}
+	}
+
+	_, err = w.WriteString(replyEnd)
+	return
+}
+
+// Converts the Data of a Record into an Item. Data other than Items and
+// Bytes is encoded by the Cache's Codec.
+func itemOf(
+	data interface{},
+	codec fsbcache.Codec,
+) (item Item, ok bool) {
+	switch v := data.(type) {
+	case Item:
+		return v, true
+	case []byte:
+		return Item{Value: v}, true
+	default:
+		var err error
+		item.Value, err = codec.Encode(data)
+		return item, err == nil
+	}
+}
+
+// Converts the Expiration Time of the Protocol into an absolute Time. Zero
+// Expiration Time selects the Cache's Record TTL, which is denoted by zero
+// Time. A passed Expiration Time is converted into a whole Second which has
+// already passed.
+func expirationTimeOf(
+	exptime int64,
+	now time.Time,
+) (expirationTime time.Time, isExpired bool) {
+	if exptime < 0 {
+		return time.Unix(now.Unix(), 0), true
+	}
+	if exptime == 0 {
+		return time.Time{}, false
+	}
+	if exptime <= RelativeExpirationTimeLimit {
+		return now.Add(time.Duration(exptime) * time.Second), false
+	}
+	expirationTime = time.Unix(exptime, 0)
+	return expirationTime, !now.Before(expirationTime)
+}
+
+// Checks the Key of an Item.
+func isKeyValid(
+	key []byte,
+) bool {
+	if (len(key) == 0) || (len(key) > MaxKeySize) {
+		return false
+	}
+	for _, b := range key {
+		if (b <= ' ') || (b == 0x7F) {
+			return false
+		}
+	}
+	return true
+}
+
+// Reads and discards the Rest of the current Line.
+func skipLine(
+	r *bufio.Reader,
+) (err error) {
+	for {
+		_, err = r.ReadSlice('\n')
+		if err != bufio.ErrBufferFull {
+			return
+		}
+	}
+}
+
+// Parses the optional 'noreply' Argument, which must be the last One.
+func parseNoReply(
+	args [][]byte,
+) (noReply bool, isValid bool) {
+	switch len(args) {
+	case 0:
+		return false, true
+	case 1:
+		noReply = string(args[0]) == "noreply"
+		return noReply, noReply
+	default:
+		return false, false
+	}
+}
+
+// Writes a Client Error.
+func writeClientError(
+	w *bufio.Writer,
+	message string,
+) (err error) {
+	_, err = w.WriteString(replyPrefixClientError + message + lineEnd)
+	return
+}
+
+// Writes a Server Error.
+func writeServerError(
+	w *bufio.Writer,
+	message string,
+) (err error) {
+	_, err = w.WriteString(replyPrefixServerError + message + lineEnd)
+	return
+}
@@ -0,0 +1,239 @@
+// Fixed Size Bubble Cache Memcached Server.
+
+package memcached
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/tester"
+
+	fsbcache "github.com/vault-thirteen/FixedSizeBubbleCache"
+)
+
+// A Client Connection used in Tests.
+type testConnection struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// Starts a Server on a loopback Listener and connects to it.
+func startTestServer(
+	t *testing.T,
+	cache *fsbcache.FixedSizeBubbleCache,
+	maxValueSize int,
+) (s *Server, tc *testConnection) {
+	var err error
+	s, err = NewServer(cache, maxValueSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var listener net.Listener
+	listener, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = s.Serve(listener)
+	}()
+
+	var conn net.Conn
+	conn, err = net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc = &testConnection{t: t, conn: conn, reader: bufio.NewReader(conn)}
+	return
+}
+
+// Sends the Request and reads the specified Number of Lines of the Reply.
+func (tc *testConnection) do(
+	request string,
+	replyLinesCount int,
+) (reply string) {
+	var err = tc.conn.SetDeadline(time.Now().Add(time.Second * 5))
+	if err != nil {
+		tc.t.Fatal(err)
+	}
+	_, err = tc.conn.Write([]byte(request))
+	if err != nil {
+		tc.t.Fatal(err)
+	}
+
+	var line string
+	for i := 0; i < replyLinesCount; i++ {
+		line, err = tc.reader.ReadString('\n')
+		if err != nil {
+			tc.t.Fatal(err)
+		}
+		reply += line
+	}
+	return
+}
+
+func Test_NewServer(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var s *Server
+	var err error
+
+	// Test #1. No Cache.
+	_, err = NewServer(nil, 0)
+	aTest.MustBeAnError(err)
+
+	// Test #2. Default Value Size.
+	s, err = NewServer(fsbcache.NewFixedSizeBubbleCache(2, 60), 0)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(s.maxValueSize, MaxValueSizeDefault)
+}
+
+func Test_Server_StorageCommands(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = fsbcache.NewFixedSizeBubbleCache(4, 60)
+	var s, c = startTestServer(t, cache, 8)
+	defer func() { _ = s.Close() }()
+
+	// Test #1. Missing Item.
+	aTest.MustBeEqual(c.do("get a\r\n", 1), "END\r\n")
+
+	// Test #2. Set and Get.
+	aTest.MustBeEqual(c.do("set a 5 0 3\r\nabc\r\n", 1), "STORED\r\n")
+	aTest.MustBeEqual(c.do("get a\r\n", 3), "VALUE a 5 3\r\nabc\r\nEND\r\n")
+	aTest.MustBeEqual(c.do("gets a\r\n", 3), "VALUE a 5 3 1\r\nabc\r\nEND\r\n")
+
+	// Test #3. Add and Replace.
+	aTest.MustBeEqual(c.do("add a 0 0 1\r\nx\r\n", 1), "NOT_STORED\r\n")
+	aTest.MustBeEqual(c.do("replace b 0 0 1\r\nx\r\n", 1), "NOT_STORED\r\n")
+	aTest.MustBeEqual(c.do("add b 0 0 2\r\nbb\r\n", 1), "STORED\r\n")
+	aTest.MustBeEqual(c.do("replace a 0 0 2\r\naa\r\n", 1), "STORED\r\n")
+	// CAS Values of Items which have not been stored are skipped.
+	aTest.MustBeEqual(c.do("gets a b c\r\n", 5), "VALUE a 0 2 5\r\naa\r\nVALUE b 0 2 4\r\nbb\r\nEND\r\n")
+
+	// Test #4. Expiration Time is the absolute Expiration Time of the Record.
+	aTest.MustBeEqual(c.do("set e 0 10 1\r\ne\r\n", 1), "STORED\r\n")
+	var isActive, err = cache.IsRecordUIDActive("e")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(isActive, true)
+	var info fsbcache.RecordInfo
+	info, err = cache.GetRecordInfo("e")
+	aTest.MustBeNoError(err)
+	var expiresIn = time.Until(info.ExpirationTime)
+	aTest.MustBeEqual((expiresIn > time.Second*8) && (expiresIn <= time.Second*11), true)
+	aTest.MustBeEqual(c.do("set e 0 -1 1\r\ne\r\n", 1), "STORED\r\n")
+	aTest.MustBeEqual(cache.RecordUIDExists("e"), false)
+
+	// Test #5. Add and Replace with a passed Expiration Time.
+	aTest.MustBeEqual(c.do("add a 0 -1 1\r\nx\r\n", 1), "NOT_STORED\r\n")
+	aTest.MustBeEqual(c.do("add e 0 -1 1\r\nx\r\n", 1), "STORED\r\n")
+	aTest.MustBeEqual(c.do("replace b 0 -1 1\r\nx\r\n", 1), "STORED\r\n")
+	aTest.MustBeEqual(c.do("get e b\r\n", 1), "END\r\n")
+	aTest.MustBeEqual(c.do("add b 0 0 2\r\nbb\r\n", 1), "STORED\r\n")
+
+	// Test #6. 'noreply'.
+	aTest.MustBeEqual(c.do("set n 0 0 1 noreply\r\nn\r\nget n\r\n", 3), "VALUE n 0 1\r\nn\r\nEND\r\n")
+
+	// Test #7. Too large Value.
+	aTest.MustBeEqual(c.do("set a 0 0 9\r\n123456789\r\n", 1), "SERVER_ERROR object too large for cache\r\n")
+	aTest.MustBeEqual(c.do("get a\r\n", 3), "VALUE a 0 2\r\naa\r\nEND\r\n")
+
+	// Test #8. Bad Data Chunk.
+	aTest.MustBeEqual(c.do("set a 0 0 1\r\nxyz\r\n", 1), "CLIENT_ERROR bad data chunk\r\n")
+
+	// Test #9. Bad Command Line.
+	aTest.MustBeEqual(c.do("set a x 0 1\r\nx\r\n", 1), "CLIENT_ERROR bad command line format\r\n")
+	aTest.MustBeEqual(c.do("unknown\r\n", 1), "ERROR\r\n")
+
+	// Test #10. Raw Values added via other APIs.
+	_ = cache.AddRecord(&fsbcache.FixedSizeBubbleCacheRecord{UID: "r", Data: []byte("raw")})
+	aTest.MustBeEqual(c.do("gets r\r\n", 3), "VALUE r 0 3 0\r\nraw\r\nEND\r\n")
+
+	// Test #11. Other Types are encoded by the Cache's Codec.
+	cache.SetCodec(fsbcache.JSONCodec{})
+	_ = cache.AddRecord(&fsbcache.FixedSizeBubbleCacheRecord{UID: "j", Data: 12})
+	aTest.MustBeEqual(c.do("get j\r\n", 3), "VALUE j 0 2\r\n12\r\nEND\r\n")
+}
+
+func Test_Server_OtherCommands(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = fsbcache.NewFixedSizeBubbleCache(4, 60)
+	var s, c = startTestServer(t, cache, 0)
+	defer func() { _ = s.Close() }()
+	_ = c.do("set a 0 0 1\r\na\r\nset b 0 0 1\r\nb\r\n", 2)
+
+	// Test #1. Delete.
+	aTest.MustBeEqual(c.do("delete a\r\n", 1), "DELETED\r\n")
+	aTest.MustBeEqual(c.do("delete a\r\n", 1), "NOT_FOUND\r\n")
+
+	// Test #2. Touch.
+	aTest.MustBeEqual(c.do("touch a 10\r\n", 1), "NOT_FOUND\r\n")
+	aTest.MustBeEqual(c.do("touch b 10\r\n", 1), "TOUCHED\r\n")
+	aTest.MustBeEqual(c.do("touch b -1\r\n", 1), "TOUCHED\r\n")
+	aTest.MustBeEqual(cache.RecordUIDExists("b"), false)
+
+	// Test #3. Flush.
+	_ = c.do("set a 0 0 1\r\na\r\n", 1)
+	aTest.MustBeEqual(c.do("flush_all 10\r\n", 1), "CLIENT_ERROR delayed flush_all is not supported\r\n")
+	aTest.MustBeEqual(c.do("flush_all\r\n", 1), "OK\r\n")
+	aTest.MustBeEqual(cache.Stats().Records, uint(0))
+
+	// Test #4. Statistics.
+	var stats = c.do("stats\r\n", 16)
+	aTest.MustBeEqual(strings.Contains(stats, "STAT curr_connections 1\r\n"), true)
+	aTest.MustBeEqual(strings.Contains(stats, "STAT cmd_flush 2\r\n"), true)
+	aTest.MustBeEqual(strings.Contains(stats, "STAT limit_items 4\r\n"), true)
+	aTest.MustBeEqual(strings.HasSuffix(stats, "END\r\n"), true)
+
+	// Test #5. Too long Line is skipped, the Connection is kept.
+	aTest.MustBeEqual(c.do("get "+strings.Repeat("k ", maxLineSize)+"\r\n", 1), "CLIENT_ERROR line is too long\r\n")
+	aTest.MustBeEqual(c.do("get a\r\n", 1), "END\r\n")
+
+	// Test #6. Long multi-Key Line.
+	_ = c.do("set a 0 0 1\r\na\r\n", 1)
+	aTest.MustBeEqual(c.do("get "+strings.Repeat("x ", 4096)+"a\r\n", 3), "VALUE a 0 1\r\na\r\nEND\r\n")
+}
+
+func Test_Server_Close(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var s, c = startTestServer(t, fsbcache.NewFixedSizeBubbleCache(4, 60), 0)
+
+	// Test #1.
+	aTest.MustBeEqual(c.do("get a\r\n", 1), "END\r\n")
+	aTest.MustBeNoError(s.Close())
+	var _, err = c.reader.ReadString('\n')
+	aTest.MustBeAnError(err)
+	err = s.Serve(nil)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrServerIsClosed)
+}
+
+func Test_expirationTimeOf(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var now = time.Unix(1000000000, 500)
+	var expirationTime time.Time
+	var isExpired bool
+
+	// Test #1. Relative Time.
+	expirationTime, isExpired = expirationTimeOf(10, now)
+	aTest.MustBeEqual(expirationTime, now.Add(time.Second*10))
+	aTest.MustBeEqual(isExpired, false)
+
+	// Test #2. Absolute Time.
+	expirationTime, isExpired = expirationTimeOf(now.Unix()+20, now)
+	aTest.MustBeEqual(expirationTime, time.Unix(now.Unix()+20, 0))
+	aTest.MustBeEqual(isExpired, false)
+
+	// Test #3. The Cache's Record TTL.
+	expirationTime, isExpired = expirationTimeOf(0, now)
+	aTest.MustBeEqual(expirationTime.IsZero(), true)
+	aTest.MustBeEqual(isExpired, false)
+
+	// Test #4. Past.
+	_, isExpired = expirationTimeOf(now.Unix()-20, now)
+	aTest.MustBeEqual(isExpired, true)
+	expirationTime, isExpired = expirationTimeOf(-1, now)
+	aTest.MustBeEqual(expirationTime, time.Unix(now.Unix(), 0))
+	aTest.MustBeEqual(isExpired, true)
+}
@@ -0,0 +1,14 @@
+// Fixed Size Bubble Cache Memcached Server.
+
+package memcached
+
+// Error Messages.
+const (
+	ErrServerIsClosed = `Server is closed`
+	//
+	ErrBadCommandLineFormat      = `bad command line format`
+	ErrBadDataChunk              = `bad data chunk`
+	ErrLineIsTooLong             = `line is too long`
+	ErrObjectIsTooLarge          = `object too large for cache`
+	ErrDelayedFlushIsUnsupported = `delayed flush_all is not supported`
+)
@@ -0,0 +1,695 @@
+// Fixed Size Bubble Cache RESP Server.
+
+package resp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	fsbcache "github.com/vault-thirteen/FixedSizeBubbleCache"
+)
+
+// Default maximum Size of a Bulk String in Bytes.
+const MaxValueSizeDefault = 1024 * 1024
+
+// Limits of the Protocol.
+const (
+	maxLineSize          = 64 * 1024
+	maxMultiBulkLength   = 1024 * 1024
+	maxRequestOverhead   = 64 * 1024
+	millisecondsInSecond = 1000
+)
+
+// Replies of the Protocol.
+const (
+	replyOK       = "+OK\r\n"
+	replyPong     = "+PONG\r\n"
+	replyNullBulk = "$-1\r\n"
+	lineEnd       = "\r\n"
+)
+
+// Replies of the 'TTL' Command for Keys without a Time-To-Live.
+const (
+	ttlOfMissingKey = -2
+)
+
+// A Server which exposes a fixed-Size Bubble Cache via the Subset of the
+// Redis Serialization Protocol (RESP2): 'GET', 'SET' (with the 'EX', 'PX',
+// 'NX' and 'XX' Options), 'DEL', 'EXISTS', 'TTL', 'EXPIRE', 'DBSIZE',
+// 'FLUSHDB' and 'INFO'. 'PING', 'SELECT 0' and 'QUIT' are also supported for
+// Clients which use them. Inline Commands are accepted as well.
+//
+// Values are stored as Byte Arrays. Expirations are mapped to absolute
+// Expiration Times of Records, with the Precision of one Second, so Requests
+// do not extend them. Keys without an explicit Expiration have the Cache's
+// Record TTL, which is counted from the last Access to the Key.
+type Server struct {
+
+	// Counters are accessed atomically, so they go first for the Alignment.
+	commandsProcessed uint64
+	totalConnections  uint64
+
+	cache        *fsbcache.FixedSizeBubbleCache
+	maxValueSize int
+	startTime    time.Time
+
+	// A Lock which protects the Lists of Listeners and Connections.
+	lock        sync.Mutex
+	listeners   map[net.Listener]bool
+	connections map[net.Conn]bool
+	isClosed    bool
+	wg          sync.WaitGroup
+}
+
+// Creates a new Server. Zero maximum Value Size selects the default Size.
+func NewServer(
+	cache *fsbcache.FixedSizeBubbleCache,
+	maxValueSize int,
+) (s *Server, err error) {
+	if cache == nil {
+		err = errors.New(fsbcache.ErrCacheIsNotSet)
+		return
+	}
+	if maxValueSize <= 0 {
+		maxValueSize = MaxValueSizeDefault
+	}
+
+	s = &Server{
+		cache:        cache,
+		maxValueSize: maxValueSize,
+		startTime:    time.Now(),
+		listeners:    make(map[net.Listener]bool),
+		connections:  make(map[net.Conn]bool),
+	}
+	return
+}
+
+// Accepts Connections on the Listener and serves them until the Server is
+// closed. Always returns a non-null Error.
+func (s *Server) Serve(
+	listener net.Listener,
+) (err error) {
+	s.lock.Lock()
+	if s.isClosed {
+		s.lock.Unlock()
+		return errors.New(ErrServerIsClosed)
+	}
+	s.listeners[listener] = true
+	s.lock.Unlock()
+
+	var conn net.Conn
+	for {
+		conn, err = listener.Accept()
+		if err != nil {
+			s.lock.Lock()
+			delete(s.listeners, listener)
+			if s.isClosed {
+				err = errors.New(ErrServerIsClosed)
+			}
+			s.lock.Unlock()
+			return
+		}
+
+		s.lock.Lock()
+		if s.isClosed {
+			s.lock.Unlock()
+			_ = conn.Close()
+			return errors.New(ErrServerIsClosed)
+		}
+		s.connections[conn] = true
+		s.wg.Add(1)
+		s.lock.Unlock()
+		atomic.AddUint64(&s.totalConnections, 1)
+
+		go s.serveConnection(conn)
+	}
+}
+
+// Closes all Listeners and Connections and waits for the Connections'
+// Handlers to stop.
+func (s *Server) Close() (err error) {
+	s.lock.Lock()
+	s.isClosed = true
+	for listener := range s.listeners {
+		var closeErr = listener.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}
+	for conn := range s.connections {
+		_ = conn.Close()
+	}
+	s.lock.Unlock()
+
+	s.wg.Wait()
+	return
+}
+
+// Serves the Commands of a Connection until it is closed.
+func (s *Server) serveConnection(
+	conn net.Conn,
+) {
+	defer func() {
+		_ = conn.Close()
+		s.lock.Lock()
+		delete(s.connections, conn)
+		s.lock.Unlock()
+		s.wg.Done()
+	}()
+
+	var r = bufio.NewReaderSize(conn, maxLineSize)
+	var w = bufio.NewWriter(conn)
+	var args [][]byte
+	var isQuit bool
+	var err error
+	for {
+		args, err = readCommand(r, s.maxValueSize)
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			// The Stream can not be synchronized with the Client any more.
+			_ = writeError(w, fmt.Sprintf(ErrfProtocolError, err.Error()))
+			_ = w.Flush()
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		isQuit, err = s.execute(args, w)
+		if (err != nil) || isQuit {
+			_ = w.Flush()
+			return
+		}
+
+		// Pipelined Commands are answered together.
+		if r.Buffered() == 0 {
+			err = w.Flush()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Reads a Command: either an Array of Bulk Strings or an inline Command.
+// Empty Commands have no Arguments. The Size of an Array, including Headers
+// of its Bulk Strings, is limited by the maximum Size of a Bulk String and the
+// Overhead for Keys and Options, so that a Client can not make the Server
+// allocate more Memory than a single Value requires.
+func readCommand(
+	r *bufio.Reader,
+	maxBulkSize int,
+) (args [][]byte, err error) {
+	var line []byte
+	line, err = readLine(r)
+	if err != nil {
+		return
+	}
+	if (len(line) == 0) || (line[0] != '*') {
+		for _, field := range bytes.Fields(line) {
+			args = append(args, append([]byte(nil), field...))
+		}
+		return
+	}
+
+	var count int64
+	count, err = strconv.ParseInt(string(line[1:]), 10, 64)
+	if (err != nil) || (count > maxMultiBulkLength) {
+		return nil, errors.New(ErrInvalidMultiBulkLength)
+	}
+
+	var maxRequestSize = int64(maxBulkSize) + maxRequestOverhead
+	var requestSize int64
+	var size int64
+	var bulk []byte
+	var i int64
+	for i = 0; i < count; i++ {
+		line, err = readLine(r)
+		if err != nil {
+			return
+		}
+		if (len(line) == 0) || (line[0] != '$') {
+			var got byte
+			if len(line) > 0 {
+				got = line[0]
+			}
+			return nil, fmt.Errorf(ErrfExpectedByte, '$', got)
+		}
+		size, err = strconv.ParseInt(string(line[1:]), 10, 64)
+		if (err != nil) || (size < 0) || (size > int64(maxBulkSize)) {
+			return nil, errors.New(ErrInvalidBulkLength)
+		}
+		requestSize += int64(len(line)+len(lineEnd)) + size + int64(len(lineEnd))
+		if requestSize > maxRequestSize {
+			return nil, errors.New(ErrRequestIsTooLarge)
+		}
+
+		bulk = make([]byte, size+int64(len(lineEnd)))
+		_, err = io.ReadFull(r, bulk)
+		if err != nil {
+			return
+		}
+		if string(bulk[size:]) != lineEnd {
+			return nil, errors.New(ErrBulkIsNotTerminated)
+		}
+		args = append(args, bulk[:size])
+	}
+	return
+}
+
+// Reads a Line without the Line End. The Line is valid until the next Read.
+func readLine(
+	r *bufio.Reader,
+) (line []byte, err error) {
+	line, err = r.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		return nil, errors.New(ErrLineIsTooLong)
+	}
+	if err != nil {
+		return
+	}
+	line = bytes.TrimSuffix(line[:len(line)-1], []byte{'\r'})
+	return
+}
+
+// Executes a Command. Errors are the I/O Errors of the Connection.
+func (s *Server) execute(
+	args [][]byte,
+	w *bufio.Writer,
+) (isQuit bool, err error) {
+	atomic.AddUint64(&s.commandsProcessed, 1)
+
+	var name = strings.ToLower(string(args[0]))
+	var arity, ok = commandArities[name]
+	if !ok {
+		return false, writeError(w, fmt.Sprintf(ErrfUnknownCommand, args[0]))
+	}
+	if (len(args) < arity.min) || ((arity.max > 0) && (len(args) > arity.max)) {
+		return false, writeError(w, fmt.Sprintf(ErrfWrongNumberOfArguments, name))
+	}
+
+	args = args[1:]
+	switch name {
+	case "get":
+		err = s.get(w, args[0])
+	case "set":
+		err = s.set(w, args)
+	case "del":
+		err = s.del(w, args)
+	case "exists":
+		err = s.exists(w, args)
+	case "ttl":
+		err = s.ttl(w, args[0])
+	case "expire":
+		err = s.expire(w, args[0], args[1])
+	case "dbsize":
+		err = writeInteger(w, int64(s.cache.Stats().Records))
+	case "flushdb":
+		err = s.flushDB(w, args)
+	case "info":
+		err = s.info(w, args)
+	case "ping":
+		if len(args) == 0 {
+			_, err = w.WriteString(replyPong)
+		} else {
+			err = writeBulk(w, args[0])
+		}
+	case "select":
+		err = s.selectDB(w, args[0])
+	case "quit":
+		isQuit = true
+		_, err = w.WriteString(replyOK)
+	}
+	return
+}
+
+// Number of Arguments of a Command, including the Command's Name.
+// Zero maximum Number means no Limit.
+type commandArity struct {
+	min int
+	max int
+}
+
+// Supported Commands.
+var commandArities = map[string]commandArity{
+	"get":     {2, 2},
+	"set":     {3, 6},
+	"del":     {2, 0},
+	"exists":  {2, 0},
+	"ttl":     {2, 2},
+	"expire":  {3, 3},
+	"dbsize":  {1, 1},
+	"flushdb": {1, 2},
+	"info":    {1, 2},
+	"ping":    {1, 2},
+	"select":  {2, 2},
+	"quit":    {1, 1},
+}
+
+// Executes the 'GET' Command: GET key.
+func (s *Server) get(
+	w *bufio.Writer,
+	key []byte,
+) (err error) {
+	var data, getErr = s.cache.GetActualRecordDataByUID(string(key))
+	if getErr != nil {
+		// The Key is missing, outdated or negative.
+		_, err = w.WriteString(replyNullBulk)
+		return
+	}
+
+	var value, ok = valueBytes(data, s.cache.GetCodec())
+	if !ok {
+		return writeError(w, ErrWrongType)
+	}
+	return writeBulk(w, value)
+}
+
+// Executes the 'SET' Command: SET key value [EX seconds|PX milliseconds]
+// [NX|XX].
+func (s *Server) set(
+	w *bufio.Writer,
+	args [][]byte,
+) (err error) {
+	var key = string(args[0])
+	var value = args[1]
+
+	// Options.
+	var expirationTime time.Time
+	var hasExpiration, isValid, nx, xx bool
+	var option string
+	var n int64
+	var parseErr error
+	for i := 2; i < len(args); i++ {
+		option = strings.ToLower(string(args[i]))
+		switch option {
+		case "nx":
+			nx = true
+		case "xx":
+			xx = true
+		case "ex", "px":
+			if hasExpiration || (i+1 >= len(args)) {
+				return writeError(w, ErrSyntaxError)
+			}
+			hasExpiration = true
+			i++
+			n, parseErr = strconv.ParseInt(string(args[i]), 10, 64)
+			if parseErr != nil {
+				return writeError(w, ErrValueIsNotAnInteger)
+			}
+			if option == "px" {
+				// The Times of the Cache are measured in whole Seconds.
+				n = (n + millisecondsInSecond - 1) / millisecondsInSecond
+			}
+			expirationTime, isValid = expirationTimeAfter(n)
+			if !isValid {
+				return writeError(w, fmt.Sprintf(ErrfInvalidExpireTime, "set"))
+			}
+		default:
+			return writeError(w, ErrSyntaxError)
+		}
+	}
+	if nx && xx {
+		return writeError(w, ErrSyntaxError)
+	}
+
+	// The Conditions are checked by the Cache together with the Change.
+	var record = &fsbcache.FixedSizeBubbleCacheRecord{
+		UID:  key,
+		Data: value,
+	}
+	record.SetExpirationTime(expirationTime)
+	var isStored = true
+	var addErr error
+	switch {
+	case nx:
+		isStored, addErr = s.cache.AddIfAbsent(record)
+	case xx:
+		isStored, addErr = s.cache.ReplaceIfPresent(record)
+	default:
+		addErr = s.cache.AddRecord(record)
+	}
+	if addErr != nil {
+		return writeError(w, "ERR "+addErr.Error())
+	}
+	if !isStored {
+		_, err = w.WriteString(replyNullBulk)
+		return
+	}
+
+	_, err = w.WriteString(replyOK)
+	return
+}
+
+// Executes the 'DEL' Command: DEL key [key ...].
+func (s *Server) del(
+	w *bufio.Writer,
+	keys [][]byte,
+) (err error) {
+	var count int64
+	for _, key := range keys {
+		if s.keyExists(string(key)) &&
+			(s.cache.DeleteRecordByUID(string(key)) == nil) {
+			count++
+		}
+	}
+
+	return writeInteger(w, count)
+}
+
+// Executes the 'EXISTS' Command: EXISTS key [key ...].
+func (s *Server) exists(
+	w *bufio.Writer,
+	keys [][]byte,
+) (err error) {
+	var count int64
+	for _, key := range keys {
+		if s.keyExists(string(key)) {
+			count++
+		}
+	}
+	return writeInteger(w, count)
+}
+
+// Executes the 'TTL' Command: TTL key.
+// As all Keys of the Cache have a TTL, '-1' is never returned.
+func (s *Server) ttl(
+	w *bufio.Writer,
+	key []byte,
+) (err error) {
+	var remainingTTL, ttlErr = s.cache.GetRecordRemainingTTL(string(key))
+	if ttlErr != nil {
+		return writeInteger(w, ttlOfMissingKey)
+	}
+	return writeInteger(w, int64(remainingTTL))
+}
+
+// Executes the 'EXPIRE' Command: EXPIRE key seconds.
+func (s *Server) expire(
+	w *bufio.Writer,
+	key []byte,
+	seconds []byte,
+) (err error) {
+	var n, parseErr = strconv.ParseInt(string(seconds), 10, 64)
+	if parseErr != nil {
+		return writeError(w, ErrValueIsNotAnInteger)
+	}
+
+	var result int64
+	if n <= 0 {
+		// The Key expires immediately.
+		if s.keyExists(string(key)) &&
+			(s.cache.DeleteRecordByUID(string(key)) == nil) {
+			result = 1
+		}
+	} else {
+		var expirationTime, isValid = expirationTimeAfter(n)
+		if !isValid {
+			return writeError(w, fmt.Sprintf(ErrfInvalidExpireTime, "expire"))
+		}
+		if s.cache.SetRecordExpirationTime(string(key), expirationTime) == nil {
+			result = 1
+		}
+	}
+
+	return writeInteger(w, result)
+}
+
+// Executes the 'FLUSHDB' Command: FLUSHDB [ASYNC|SYNC].
+// The Flush is always synchronous.
+func (s *Server) flushDB(
+	w *bufio.Writer,
+	args [][]byte,
+) (err error) {
+	if len(args) > 0 {
+		var mode = strings.ToLower(string(args[0]))
+		if (mode != "async") && (mode != "sync") {
+			return writeError(w, ErrSyntaxError)
+		}
+	}
+
+	var clearErr = s.cache.Clear()
+	if clearErr != nil {
+		return writeError(w, "ERR "+clearErr.Error())
+	}
+
+	_, err = w.WriteString(replyOK)
+	return
+}
+
+// Executes the 'INFO' Command: INFO [section].
+// The Sections are 'server', 'clients', 'stats' and 'keyspace'.
+func (s *Server) info(
+	w *bufio.Writer,
+	args [][]byte,
+) (err error) {
+	var section = "all"
+	if len(args) > 0 {
+		section = strings.ToLower(string(args[0]))
+	}
+	var isSelected = func(name string) bool {
+		return (section == name) || (section == "all") ||
+			(section == "default") || (section == "everything")
+	}
+
+	s.lock.Lock()
+	var connectedClients = len(s.connections)
+	s.lock.Unlock()
+	var cs = s.cache.Stats()
+
+	var buf bytes.Buffer
+	if isSelected("server") {
+		buf.WriteString("# Server\r\n")
+		fmt.Fprintf(&buf, "process_id:%d\r\n", os.Getpid())
+		fmt.Fprintf(&buf, "uptime_in_seconds:%d\r\n", int64(time.Since(s.startTime).Seconds()))
+		buf.WriteString(lineEnd)
+	}
+	if isSelected("clients") {
+		buf.WriteString("# Clients\r\n")
+		fmt.Fprintf(&buf, "connected_clients:%d\r\n", connectedClients)
+		buf.WriteString(lineEnd)
+	}
+	if isSelected("stats") {
+		buf.WriteString("# Stats\r\n")
+		fmt.Fprintf(&buf, "total_connections_received:%d\r\n", atomic.LoadUint64(&s.totalConnections))
+		fmt.Fprintf(&buf, "total_commands_processed:%d\r\n", atomic.LoadUint64(&s.commandsProcessed))
+		fmt.Fprintf(&buf, "expired_keys:%d\r\n", cs.Expirations)
+		fmt.Fprintf(&buf, "evicted_keys:%d\r\n", cs.Evictions)
+		fmt.Fprintf(&buf, "keyspace_hits:%d\r\n", cs.Hits)
+		fmt.Fprintf(&buf, "keyspace_misses:%d\r\n", cs.Misses)
+		buf.WriteString(lineEnd)
+	}
+	if isSelected("keyspace") {
+		buf.WriteString("# Keyspace\r\n")
+		if cs.Records > 0 {
+			fmt.Fprintf(&buf, "db0:keys=%d,expires=%d,avg_ttl=0\r\n", cs.Records, cs.Records)
+		}
+		buf.WriteString(lineEnd)
+	}
+
+	return writeBulk(w, buf.Bytes())
+}
+
+// Executes the 'SELECT' Command: SELECT index.
+// The Cache is the only Database, its Index is zero.
+func (s *Server) selectDB(
+	w *bufio.Writer,
+	index []byte,
+) (err error) {
+	var n, parseErr = strconv.ParseInt(string(index), 10, 64)
+	if parseErr != nil {
+		return writeError(w, ErrValueIsNotAnInteger)
+	}
+	if n != 0 {
+		return writeError(w, ErrDBIndexIsOutOfRange)
+	}
+	_, err = w.WriteString(replyOK)
+	return
+}
+
+// Checks whether an actual Record with the Key exists.
+func (s *Server) keyExists(
+	key string,
+) bool {
+	var isActive, err = s.cache.IsRecordUIDActive(key)
+	return (err == nil) && isActive
+}
+
+// Returns the Time which comes in the positive Number of Seconds, counted from
+// the current whole Second as the TTLs of the Cache are. Too large Numbers are
+// not valid.
+func expirationTimeAfter(
+	seconds int64,
+) (expirationTime time.Time, isValid bool) {
+	var now = time.Now().Unix()
+	if (seconds <= 0) || (seconds > math.MaxInt64-now) {
+		return expirationTime, false
+	}
+	return time.Unix(now+seconds, 0), true
+}
+
+// Returns the Bytes of a Record's Data. Data other than Bytes is encoded by
+// the Cache's Codec.
+func valueBytes(
+	data interface{},
+	codec fsbcache.Codec,
+) (value []byte, ok bool) {
+	switch v := data.(type) {
+	case []byte:
+		return v, true
+	case interface{ Bytes() []byte }:
+		return v.Bytes(), true
+	default:
+		var err error
+		value, err = codec.Encode(data)
+		return value, err == nil
+	}
+}
+
+// Writes an Error.
+func writeError(
+	w *bufio.Writer,
+	message string,
+) (err error) {
+	_, err = w.WriteString("-" + message + lineEnd)
+	return
+}
+
+// Writes an Integer.
+func writeInteger(
+	w *bufio.Writer,
+	n int64,
+) (err error) {
+	_, err = fmt.Fprintf(w, ":%d\r\n", n)
+	return
+}
+
+// Writes a Bulk String.
+func writeBulk(
+	w *bufio.Writer,
+	value []byte,
+) (err error) {
+	_, err = fmt.Fprintf(w, "$%d\r\n", len(value))
+	if err != nil {
+		return
+	}
+	_, err = w.Write(value)
+	if err != nil {
+		return
+	}
+	_, err = w.WriteString(lineEnd)
+	return
+}
@@ -0,0 +1,232 @@
+// Fixed Size Bubble Cache RESP Server.
+
+package resp
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/tester"
+
+	fsbcache "github.com/vault-thirteen/FixedSizeBubbleCache"
+)
+
+// A Client Connection used in Tests.
+type testConnection struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// Starts a Server on a loopback Listener and connects to it.
+func startTestServer(
+	t *testing.T,
+	cache *fsbcache.FixedSizeBubbleCache,
+	maxValueSize int,
+) (s *Server, tc *testConnection) {
+	var err error
+	s, err = NewServer(cache, maxValueSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var listener net.Listener
+	listener, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = s.Serve(listener)
+	}()
+
+	var conn net.Conn
+	conn, err = net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc = &testConnection{t: t, conn: conn, reader: bufio.NewReader(conn)}
+	return
+}
+
+// Sends the Command as an Array of Bulk Strings and reads the specified
+// Number of Lines of the Reply.
+func (tc *testConnection) do(
+	replyLinesCount int,
+	args ...string,
+) (reply string) {
+	var request = "*" + strconv.Itoa(len(args)) + "\r\n"
+	for _, arg := range args {
+		request += "$" + strconv.Itoa(len(arg)) + "\r\n" + arg + "\r\n"
+	}
+	return tc.doRaw(request, replyLinesCount)
+}
+
+// Sends the raw Request and reads the specified Number of Lines of the Reply.
+func (tc *testConnection) doRaw(
+	request string,
+	replyLinesCount int,
+) (reply string) {
+	var err = tc.conn.SetDeadline(time.Now().Add(time.Second * 5))
+	if err != nil {
+		tc.t.Fatal(err)
+	}
+	_, err = tc.conn.Write([]byte(request))
+	if err != nil {
+		tc.t.Fatal(err)
+	}
+
+	var line string
+	for i := 0; i < replyLinesCount; i++ {
+		line, err = tc.reader.ReadString('\n')
+		if err != nil {
+			tc.t.Fatal(err)
+		}
+		reply += line
+	}
+	return
+}
+
+// Sends the Command and reads a Bulk String Reply.
+func (tc *testConnection) doBulk(
+	args ...string,
+) (value string) {
+	var header = tc.do(1, args...)
+	var size, err = strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "$")))
+	if err != nil {
+		tc.t.Fatal(header)
+	}
+	var buf = make([]byte, size+2)
+	_, err = io.ReadFull(tc.reader, buf)
+	if err != nil {
+		tc.t.Fatal(err)
+	}
+	return string(buf[:size])
+}
+
+func Test_NewServer(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var s *Server
+	var err error
+
+	// Test #1. No Cache.
+	_, err = NewServer(nil, 0)
+	aTest.MustBeAnError(err)
+
+	// Test #2. Default Value Size.
+	s, err = NewServer(fsbcache.NewFixedSizeBubbleCache(2, 60), 0)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(s.maxValueSize, MaxValueSizeDefault)
+}
+
+func Test_Server_Commands(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var cache = fsbcache.NewFixedSizeBubbleCache(4, 60)
+	var s, c = startTestServer(t, cache, 8)
+	defer func() { _ = s.Close() }()
+
+	// Test #1. Missing Key.
+	aTest.MustBeEqual(c.do(1, "GET", "a"), "$-1\r\n")
+	aTest.MustBeEqual(c.do(1, "TTL", "a"), ":-2\r\n")
+
+	// Test #2. Set and Get.
+	aTest.MustBeEqual(c.do(1, "SET", "a", "abc"), "+OK\r\n")
+	aTest.MustBeEqual(c.do(2, "get", "a"), "$3\r\nabc\r\n")
+	aTest.MustBeEqual(c.do(1, "TTL", "a"), ":60\r\n")
+
+	// Test #3. Options of 'SET'.
+	aTest.MustBeEqual(c.do(1, "SET", "a", "x", "NX"), "$-1\r\n")
+	aTest.MustBeEqual(c.do(1, "SET", "b", "x", "XX"), "$-1\r\n")
+	aTest.MustBeEqual(c.do(1, "SET", "b", "bb", "NX", "EX", "10"), "+OK\r\n")
+	aTest.MustBeEqual(c.do(1, "TTL", "b"), ":10\r\n")
+	aTest.MustBeEqual(c.do(1, "SET", "b", "bb", "XX", "PX", "2500"), "+OK\r\n")
+	aTest.MustBeEqual(c.do(1, "TTL", "b"), ":3\r\n")
+	aTest.MustBeEqual(c.do(1, "SET", "b", "x", "NX", "XX"), "-ERR syntax error\r\n")
+	aTest.MustBeEqual(c.do(1, "SET", "b", "x", "EX", "0"), "-ERR invalid expire time in 'set' command\r\n")
+	aTest.MustBeEqual(c.do(1, "SET", "b", "x", "EX", "y"), "-ERR value is not an integer or out of range\r\n")
+	aTest.MustBeEqual(c.do(1, "SET", "b", "x", "EX"), "-ERR syntax error\r\n")
+
+	// Test #4. 'EXISTS', 'DBSIZE' and 'DEL'.
+	aTest.MustBeEqual(c.do(1, "EXISTS", "a", "b", "c", "a"), ":3\r\n")
+	aTest.MustBeEqual(c.do(1, "DBSIZE"), ":2\r\n")
+	aTest.MustBeEqual(c.do(1, "DEL", "a", "c"), ":1\r\n")
+	aTest.MustBeEqual(c.do(1, "EXISTS", "a"), ":0\r\n")
+
+	// Test #5. 'EXPIRE'.
+	aTest.MustBeEqual(c.do(1, "EXPIRE", "a", "10"), ":0\r\n")
+	aTest.MustBeEqual(c.do(1, "EXPIRE", "b", "100"), ":1\r\n")
+	aTest.MustBeEqual(c.do(1, "TTL", "b"), ":100\r\n")
+	aTest.MustBeEqual(c.do(1, "EXPIRE", "b", "0"), ":1\r\n")
+	aTest.MustBeEqual(cache.RecordUIDExists("b"), false)
+
+	// Test #6. 'FLUSHDB'.
+	aTest.MustBeEqual(c.do(1, "SET", "a", "abc"), "+OK\r\n")
+	aTest.MustBeEqual(c.do(1, "FLUSHDB", "LATER"), "-ERR syntax error\r\n")
+	aTest.MustBeEqual(c.do(1, "FLUSHDB"), "+OK\r\n")
+	aTest.MustBeEqual(c.do(1, "DBSIZE"), ":0\r\n")
+
+	// Test #7. 'INFO'.
+	aTest.MustBeEqual(c.doBulk("INFO", "keyspace"), "# Keyspace\r\n\r\n")
+	_ = c.do(1, "SET", "a", "abc")
+	var info = c.doBulk("INFO")
+	aTest.MustBeEqual(strings.HasPrefix(info, "# Server\r\n"), true)
+	aTest.MustBeEqual(strings.Contains(info, "db0:keys=1,"), true)
+
+	// Test #8. Other Commands.
+	aTest.MustBeEqual(c.do(1, "PING"), "+PONG\r\n")
+	aTest.MustBeEqual(c.do(2, "PING", "hi"), "$2\r\nhi\r\n")
+	aTest.MustBeEqual(c.do(1, "SELECT", "0"), "+OK\r\n")
+	aTest.MustBeEqual(c.do(1, "SELECT", "1"), "-ERR DB index is out of range\r\n")
+	aTest.MustBeEqual(c.do(1, "HELLO", "3"), "-ERR unknown command 'HELLO'\r\n")
+	aTest.MustBeEqual(c.do(1, "GET"), "-ERR wrong number of arguments for 'get' command\r\n")
+
+	// Test #9. Inline and pipelined Commands.
+	aTest.MustBeEqual(c.doRaw("SET i v\r\nGET i\r\n", 3), "+OK\r\n$1\r\nv\r\n")
+
+	// Test #10. Other Types are encoded by the Cache's Codec, or are of a
+	// wrong Type.
+	cache.SetCodec(fsbcache.JSONCodec{})
+	_ = cache.AddRecord(&fsbcache.FixedSizeBubbleCacheRecord{UID: "w", Data: 1})
+	aTest.MustBeEqual(c.do(2, "GET", "w"), "$1\r\n1\r\n")
+	cache.SetCodec(fsbcache.RawCodec{})
+	aTest.MustBeEqual(c.do(1, "GET", "w"), "-WRONGTYPE Operation against a key holding the wrong kind of value\r\n")
+
+	// Test #11. Protocol Error closes the Connection.
+	// The Value is not sent, so that the Connection is closed without unread
+	// Data.
+	aTest.MustBeEqual(c.doRaw("*3\r\n$3\r\nSET\r\n$1\r\na\r\n$9\r\n", 1), "-ERR Protocol error: invalid bulk length\r\n")
+	var _, err = c.reader.ReadString('\n')
+	aTest.MustBeAnError(err)
+}
+
+func Test_Server_RequestSize(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var _, c = startTestServer(t, fsbcache.NewFixedSizeBubbleCache(4, 60), 8)
+
+	// Test #1. Many Arguments of allowed Sizes exceed the Size of a Request.
+	// The Request ends with the Header which exceeds the Size, so that the
+	// Connection is closed without unread Data.
+	var arg = "$8\r\n12345678\r\n"
+	var argsCount = (8 + maxRequestOverhead) / len(arg)
+	var request = "*" + strconv.Itoa(argsCount+10) + "\r\n" + strings.Repeat(arg, argsCount) + "$8\r\n"
+	aTest.MustBeEqual(c.doRaw(request, 1), "-ERR Protocol error: request is too large\r\n")
+	var _, err = c.reader.ReadString('\n')
+	aTest.MustBeAnError(err)
+}
+
+func Test_Server_Close(t *testing.T) {
+	var aTest *tester.Test = tester.New(t)
+	var s, c = startTestServer(t, fsbcache.NewFixedSizeBubbleCache(4, 60), 0)
+
+	// Test #1.
+	aTest.MustBeEqual(c.do(1, "QUIT"), "+OK\r\n")
+	aTest.MustBeNoError(s.Close())
+	var _, err = c.reader.ReadString('\n')
+	aTest.MustBeAnError(err)
+	err = s.Serve(nil)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrServerIsClosed)
+}
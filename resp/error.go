@@ -0,0 +1,24 @@
+// Fixed Size Bubble Cache RESP Server.
+
+package resp
+
+// Error Messages.
+const (
+	ErrServerIsClosed = `Server is closed`
+	//
+	ErrfUnknownCommand         = `ERR unknown command '%s'`
+	ErrfWrongNumberOfArguments = `ERR wrong number of arguments for '%s' command`
+	ErrfInvalidExpireTime      = `ERR invalid expire time in '%s' command`
+	ErrSyntaxError             = `ERR syntax error`
+	ErrValueIsNotAnInteger     = `ERR value is not an integer or out of range`
+	ErrDBIndexIsOutOfRange     = `ERR DB index is out of range`
+	ErrWrongType               = `WRONGTYPE Operation against a key holding the wrong kind of value`
+	ErrfProtocolError          = `ERR Protocol error: %s`
+	//
+	ErrInvalidMultiBulkLength = `invalid multibulk length`
+	ErrInvalidBulkLength      = `invalid bulk length`
+	ErrfExpectedByte          = `expected '%c', got '%c'`
+	ErrLineIsTooLong          = `too big inline request`
+	ErrBulkIsNotTerminated    = `bulk string is not terminated`
+	ErrRequestIsTooLarge      = `request is too large`
+)